@@ -271,6 +271,7 @@ const (
 	ErrAdminCredentialsMismatch
 	ErrInsecureClientRequest
 	ErrObjectTampered
+	ErrAuditUnavailable
 
 	// Site-Replication errors
 	ErrSiteReplicationInvalidRequest
@@ -1303,6 +1304,11 @@ var errorCodes = errorCodeMap{
 		Description:    errObjectTampered.Error(),
 		HTTPStatusCode: http.StatusPartialContent,
 	},
+	ErrAuditUnavailable: {
+		Code:           "XMinioAuditUnavailable",
+		Description:    "Audit is configured to fail closed and no audit target is able to accept audit events, please try again.",
+		HTTPStatusCode: http.StatusServiceUnavailable,
+	},
 
 	ErrSiteReplicationInvalidRequest: {
 		Code:           "XMinioSiteReplicationInvalidRequest",
@@ -660,6 +660,40 @@ func handleCommonEnvVars() {
 		logger.Fatal(config.ErrInvalidFSOSyncValue(err), "Invalid MINIO_FS_OSYNC value in environment variable")
 	}
 
+	auditFailClosed, err := config.ParseBool(env.Get(logger.EnvAuditFailClosed, config.EnableOff))
+	if err != nil {
+		logger.Fatal(err, fmt.Sprintf("Invalid %s value in environment variable", logger.EnvAuditFailClosed))
+	}
+	if auditFailClosed {
+		logger.EnableAuditFailClosed()
+	}
+
+	auditTLSClientCert, err := config.ParseBool(env.Get(logger.EnvAuditTLSClientCert, config.EnableOff))
+	if err != nil {
+		logger.Fatal(err, fmt.Sprintf("Invalid %s value in environment variable", logger.EnvAuditTLSClientCert))
+	}
+	if auditTLSClientCert {
+		logger.EnableAuditTLSClientCert()
+	}
+
+	if sendRate := env.Get(logger.EnvLoggerSendRate, ""); sendRate != "" {
+		rate, err := strconv.Atoi(sendRate)
+		if err != nil {
+			logger.Fatal(err, fmt.Sprintf("Invalid %s value in environment variable", logger.EnvLoggerSendRate))
+		}
+		logger.SetGlobalSendRate(rate)
+	}
+
+	if maxQueued := env.Get(logger.EnvLoggerMaxQueued, ""); maxQueued != "" {
+		n, err := strconv.Atoi(maxQueued)
+		if err != nil {
+			logger.Fatal(err, fmt.Sprintf("Invalid %s value in environment variable", logger.EnvLoggerMaxQueued))
+		}
+		if err = logger.SetGlobalQueueLimit(n, env.Get(logger.EnvLoggerMaxQueuedPolicy, "")); err != nil {
+			logger.Fatal(err, fmt.Sprintf("Invalid %s value in environment variable", logger.EnvLoggerMaxQueuedPolicy))
+		}
+	}
+
 	globalOwnerID = env.Get(config.EnvOwnerID, globalMinioDefaultOwnerID)
 	globalOwnerDisplayName = env.Get(config.EnvOwnerDisplayName, globalMinioDefaultOwnerDisplayName)
 
@@ -19,8 +19,10 @@ package cmd
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 
@@ -63,6 +65,7 @@ func initHelp() {
 		config.LoggerWebhookSubSys:  logger.DefaultLoggerWebhookKVS,
 		config.AuditWebhookSubSys:   logger.DefaultAuditWebhookKVS,
 		config.AuditKafkaSubSys:     logger.DefaultAuditKafkaKVS,
+		config.AuditAMQPSubSys:      logger.DefaultAuditAMQPKVS,
 		config.HealSubSys:           heal.DefaultKVS,
 		config.ScannerSubSys:        scanner.DefaultKVS,
 		config.SubnetSubSys:         subnet.DefaultKVS,
@@ -136,6 +139,11 @@ func initHelp() {
 			Description:     "send audit logs to kafka endpoints",
 			MultipleTargets: true,
 		},
+		config.HelpKV{
+			Key:             config.AuditAMQPSubSys,
+			Description:     "send audit logs to amqp endpoints",
+			MultipleTargets: true,
+		},
 		config.HelpKV{
 			Key:             config.NotifyWebhookSubSys,
 			Description:     "publish bucket notifications to webhook endpoints",
@@ -221,6 +229,7 @@ func initHelp() {
 		config.LoggerWebhookSubSys:  logger.Help,
 		config.AuditWebhookSubSys:   logger.HelpWebhook,
 		config.AuditKafkaSubSys:     logger.HelpKafka,
+		config.AuditAMQPSubSys:      logger.HelpAMQP,
 		config.NotifyAMQPSubSys:     notify.HelpAMQP,
 		config.NotifyKafkaSubSys:    notify.HelpKafka,
 		config.NotifyMQTTSubSys:     notify.HelpMQTT,
@@ -345,7 +354,7 @@ func validateSubSysConfig(s config.Config, subSys string, objAPI ObjectLayer) er
 		}
 	default:
 		if config.LoggerSubSystems.Contains(subSys) {
-			if err := logger.ValidateSubSysConfig(s, subSys); err != nil {
+			if _, err := logger.ValidateSubSysConfig(s, subSys, false); err != nil {
 				return err
 			}
 		}
@@ -359,7 +368,10 @@ func validateSubSysConfig(s config.Config, subSys string, objAPI ObjectLayer) er
 	return nil
 }
 
-func validateConfig(s config.Config, subSys string) error {
+// validateConfig validates the config of subSys, or of every sub-system
+// if empty, and returns the warnings of its debatable settings which do
+// not fail the validation.
+func validateConfig(s config.Config, subSys string) (warnings []string, err error) {
 	objAPI := newObjectLayerFn()
 
 	// We must have a global lock for this so nobody else modifies env while we do.
@@ -371,17 +383,42 @@ func validateConfig(s config.Config, subSys string) error {
 	// Enable env values to validate KMS.
 	defer env.SetEnvOn()
 	if subSys != "" {
-		return validateSubSysConfig(s, subSys, objAPI)
+		if err = validateSubSysConfig(s, subSys, objAPI); err != nil {
+			return nil, err
+		}
+		return subSysConfigWarnings(s, subSys)
 	}
 
 	// No sub-system passed. Validate all of them.
 	for _, ss := range config.SubSystems.ToSlice() {
-		if err := validateSubSysConfig(s, ss, objAPI); err != nil {
-			return err
+		if err = validateSubSysConfig(s, ss, objAPI); err != nil {
+			return nil, err
 		}
+		ssWarnings, err := subSysConfigWarnings(s, ss)
+		if err != nil {
+			return nil, err
+		}
+		warnings = append(warnings, ssWarnings...)
 	}
+	sort.Strings(warnings)
+	return warnings, nil
+}
 
-	return nil
+// subSysConfigWarnings returns the warnings of the config of subSys,
+// only the logger sub-systems are checked for now.
+func subSysConfigWarnings(s config.Config, subSys string) ([]string, error) {
+	if !config.LoggerSubSystems.Contains(subSys) {
+		return nil, nil
+	}
+	loggerWarnings, err := logger.ValidateSubSysConfig(s, subSys, true)
+	if err != nil {
+		return nil, err
+	}
+	warnings := make([]string, 0, len(loggerWarnings))
+	for _, w := range loggerWarnings {
+		warnings = append(warnings, w.String())
+	}
+	return warnings, nil
 }
 
 func lookupConfigs(s config.Config, objAPI ObjectLayer) {
@@ -622,6 +659,8 @@ func applyDynamicConfigForSubSys(ctx context.Context, objAPI ObjectLayer, s conf
 		loggerCfg, err := logger.LookupConfigForSubSys(s, config.LoggerWebhookSubSys)
 		if err != nil {
 			logger.LogIf(ctx, fmt.Errorf("Unable to load logger webhook config: %w", err))
+			// Keep the running targets rather than starting invalid ones.
+			break
 		}
 		userAgent := getUserAgent(getMinioMode())
 		for n, l := range loggerCfg.HTTP {
@@ -632,6 +671,28 @@ func applyDynamicConfigForSubSys(ctx context.Context, objAPI ObjectLayer, s conf
 				loggerCfg.HTTP[n] = l
 			}
 		}
+		for n, l := range loggerCfg.File {
+			if l.Enabled {
+				l.LogOnce = logger.LogOnceIf
+				loggerCfg.File[n] = l
+			}
+		}
+		for n, l := range loggerCfg.Loki {
+			if l.Enabled {
+				l.LogOnce = logger.LogOnceIf
+				l.UserAgent = userAgent
+				l.Transport = NewGatewayHTTPTransport()
+				loggerCfg.Loki[n] = l
+			}
+		}
+		for n, l := range loggerCfg.WebSocket {
+			if l.Enabled {
+				l.LogOnce = logger.LogOnceIf
+				l.UserAgent = userAgent
+				l.TLSConfig = &tls.Config{RootCAs: globalRootCAs}
+				loggerCfg.WebSocket[n] = l
+			}
+		}
 		err = logger.UpdateSystemTargets(loggerCfg)
 		if err != nil {
 			logger.LogIf(ctx, fmt.Errorf("Unable to update logger webhook config: %w", err))
@@ -640,6 +701,8 @@ func applyDynamicConfigForSubSys(ctx context.Context, objAPI ObjectLayer, s conf
 		loggerCfg, err := logger.LookupConfigForSubSys(s, config.AuditWebhookSubSys)
 		if err != nil {
 			logger.LogIf(ctx, fmt.Errorf("Unable to load audit webhook config: %w", err))
+			// Keep the running targets rather than starting invalid ones.
+			break
 		}
 		userAgent := getUserAgent(getMinioMode())
 		for n, l := range loggerCfg.AuditWebhook {
@@ -650,6 +713,14 @@ func applyDynamicConfigForSubSys(ctx context.Context, objAPI ObjectLayer, s conf
 				loggerCfg.AuditWebhook[n] = l
 			}
 		}
+		for n, l := range loggerCfg.AuditSplunk {
+			if l.Enabled {
+				l.LogOnce = logger.LogOnceIf
+				l.UserAgent = userAgent
+				l.Transport = NewGatewayHTTPTransport()
+				loggerCfg.AuditSplunk[n] = l
+			}
+		}
 
 		err = logger.UpdateAuditWebhookTargets(loggerCfg)
 		if err != nil {
@@ -659,6 +730,8 @@ func applyDynamicConfigForSubSys(ctx context.Context, objAPI ObjectLayer, s conf
 		loggerCfg, err := logger.LookupConfigForSubSys(s, config.AuditKafkaSubSys)
 		if err != nil {
 			logger.LogIf(ctx, fmt.Errorf("Unable to load audit kafka config: %w", err))
+			// Keep the running targets rather than starting invalid ones.
+			break
 		}
 		for n, l := range loggerCfg.AuditKafka {
 			if l.Enabled {
@@ -670,6 +743,24 @@ func applyDynamicConfigForSubSys(ctx context.Context, objAPI ObjectLayer, s conf
 		if err != nil {
 			logger.LogIf(ctx, fmt.Errorf("Unable to update audit kafka targets: %w", err))
 		}
+	case config.AuditAMQPSubSys:
+		loggerCfg, err := logger.LookupConfigForSubSys(s, config.AuditAMQPSubSys)
+		if err != nil {
+			logger.LogIf(ctx, fmt.Errorf("Unable to load audit amqp config: %w", err))
+			// Keep the running targets rather than starting invalid ones.
+			break
+		}
+		for n, l := range loggerCfg.AuditAMQP {
+			if l.Enabled {
+				l.LogOnce = logger.LogOnceIf
+				l.TLS.RootCAs = globalRootCAs
+				loggerCfg.AuditAMQP[n] = l
+			}
+		}
+		err = logger.UpdateAuditAMQPTargets(loggerCfg)
+		if err != nil {
+			logger.LogIf(ctx, fmt.Errorf("Unable to update audit amqp targets: %w", err))
+		}
 	}
 	globalServerConfigMu.Lock()
 	defer globalServerConfigMu.Unlock()
@@ -20,9 +20,11 @@ package cmd
 import (
 	"context"
 	"os"
+	"strconv"
 	"testing"
 
 	"github.com/minio/minio/internal/config"
+	"github.com/minio/minio/internal/logger"
 )
 
 func TestServerConfig(t *testing.T) {
@@ -62,3 +64,25 @@ func TestServerConfig(t *testing.T) {
 		t.Fatalf("Unable to initialize from updated config file %s", err)
 	}
 }
+
+func TestApplyDynamicConfigMaxTargets(t *testing.T) {
+	targets := make(map[string]config.KVS)
+	for i := 0; i < 3; i++ {
+		kvs := logger.DefaultAuditWebhookKVS.Clone()
+		kvs.Set(config.Enable, config.EnableOn)
+		kvs.Set(logger.Endpoint, "https://localhost:8080/logs")
+		targets["target"+strconv.Itoa(i)] = kvs
+	}
+	s := config.Config{config.AuditWebhookSubSys: targets}
+
+	os.Setenv(logger.EnvLoggerMaxTargets, "2")
+	defer os.Unsetenv(logger.EnvLoggerMaxTargets)
+
+	before := len(logger.AuditTargets())
+	if err := applyDynamicConfigForSubSys(context.Background(), nil, s, config.AuditWebhookSubSys); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(logger.AuditTargets()); got != before {
+		t.Fatalf("expected no audit target to be started over the cap, got %d", got-before)
+	}
+}
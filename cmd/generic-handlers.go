@@ -112,6 +112,22 @@ func setRequestLimitHandler(h http.Handler) http.Handler {
 	})
 }
 
+// setAuditFailClosedHandler rejects S3 API requests when audit is
+// configured to fail closed and no audit target is able to accept
+// their audit entry.
+func setAuditFailClosedHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, minioReservedBucketPath) {
+			if err := logger.CheckAuditAvailable(); err != nil {
+				logger.LogOnceIf(r.Context(), err, logger.EnvAuditFailClosed)
+				writeErrorResponse(r.Context(), w, errorCodes.ToAPIErr(ErrAuditUnavailable), r.URL)
+				return
+			}
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
 // Reserved bucket.
 const (
 	minioReservedBucket     = "minio"
@@ -104,8 +104,13 @@ func ClusterReadCheckHandler(w http.ResponseWriter, r *http.Request) {
 	writeResponse(w, http.StatusOK, nil, mimeNone)
 }
 
-// ReadinessCheckHandler Checks if the process is up. Always returns success.
+// ReadinessCheckHandler Checks if the process is up, fails while
+// the server is in lame duck mode to let load balancers drain it.
 func ReadinessCheckHandler(w http.ResponseWriter, r *http.Request) {
+	if srv := newHTTPServerFn(); srv != nil && srv.IsLameDuck() {
+		writeResponse(w, http.StatusServiceUnavailable, nil, mimeNone)
+		return
+	}
 	LivenessCheckHandler(w, r)
 }
 
@@ -62,6 +62,7 @@ func init() {
 		getS3TTFBMetric(),
 		getILMNodeMetrics(),
 		getScannerNodeMetrics(),
+		getLoggerMetrics(),
 	}
 
 	allMetricsGroups := func() (allMetrics []*MetricsGroup) {
@@ -106,6 +107,7 @@ const (
 	diskSubsystem             MetricSubsystem = "disk"
 	fileDescriptorSubsystem   MetricSubsystem = "file_descriptor"
 	goRoutines                MetricSubsystem = "go_routine"
+	loggerSubsystem           MetricSubsystem = "logger"
 	ioSubsystem               MetricSubsystem = "io"
 	nodesSubsystem            MetricSubsystem = "nodes"
 	objectsSubsystem          MetricSubsystem = "objects"
@@ -143,6 +145,7 @@ const (
 	offlineTotal   MetricName = "offline_total"
 	onlineTotal    MetricName = "online_total"
 	openTotal      MetricName = "open_total"
+	queuedTotal    MetricName = "queued_total"
 	readTotal      MetricName = "read_total"
 	timestampTotal MetricName = "timestamp_total"
 	writeTotal     MetricName = "write_total"
@@ -1066,6 +1069,28 @@ func getMinioProcMetrics() *MetricsGroup {
 	return mg
 }
 
+func getLoggerQueuedTotalMD() MetricDescription {
+	return MetricDescription{
+		Namespace: nodeMetricNamespace,
+		Subsystem: loggerSubsystem,
+		Name:      queuedTotal,
+		Help:      "Total number of log and audit entries queued by the network logger targets.",
+		Type:      gaugeMetric,
+	}
+}
+
+func getLoggerMetrics() *MetricsGroup {
+	mg := &MetricsGroup{}
+	mg.RegisterRead(func(ctx context.Context) (metrics []Metric) {
+		metrics = append(metrics, Metric{
+			Description: getLoggerQueuedTotalMD(),
+			Value:       float64(logger.QueuedEntries()),
+		})
+		return
+	})
+	return mg
+}
+
 func getGoMetrics() *MetricsGroup {
 	mg := &MetricsGroup{}
 	mg.RegisterRead(func(ctx context.Context) (metrics []Metric) {
@@ -60,6 +60,8 @@ var globalHandlers = []mux.MiddlewareFunc{
 	addCustomHeaders,
 	// Add bucket forwarding handler
 	setBucketForwardingHandler,
+	// Reject requests which cannot be audited, if audit fails closed.
+	setAuditFailClosedHandler,
 	// Add new handlers here.
 }
 
@@ -495,6 +495,14 @@ func serverMain(ctx *cli.Context) {
 
 	setHTTPServer(httpServer)
 
+	if interval := env.Get(logger.EnvLoggerTelemetryInterval, ""); interval != "" {
+		d, err := time.ParseDuration(interval)
+		if err != nil {
+			logger.Fatal(err, fmt.Sprintf("Invalid %s value in environment variable", logger.EnvLoggerTelemetryInterval))
+		}
+		logger.StartTelemetry(GlobalContext, d, httpServer.GetRequestCount)
+	}
+
 	if globalIsDistErasure && globalEndpoints.FirstLocal() {
 		// Additionally in distributed setup, validate the setup and configuration.
 		if err := verifyServerSystemConfig(GlobalContext, globalEndpoints); err != nil {
@@ -24,6 +24,7 @@ import (
 	"os"
 	"strings"
 
+	xhttp "github.com/minio/minio/internal/http"
 	"github.com/minio/minio/internal/logger"
 )
 
@@ -55,7 +56,12 @@ func handleSignals() {
 		}
 
 		if httpServer := newHTTPServerFn(); httpServer != nil {
-			err = httpServer.Shutdown()
+			// Flush the audit records of the last requests served.
+			var auditTargets []xhttp.LoggerTarget
+			for _, t := range logger.AuditTargets() {
+				auditTargets = append(auditTargets, t)
+			}
+			err = httpServer.ShutdownWithLoggers(xhttp.DefaultShutdownTimeout, auditTargets...)
 			if !errors.Is(err, http.ErrServerClosed) {
 				logger.LogIf(context.Background(), err)
 			}
@@ -624,6 +624,17 @@ func newCustomHTTPTransport(tlsConfig *tls.Config, dialTimeout time.Duration) fu
 // used while communicating with the cloud backends.
 func NewGatewayHTTPTransportWithClientCerts(clientCert, clientKey string) *http.Transport {
 	transport := newGatewayHTTPTransport(1 * time.Minute)
+	if config.IsPEM(clientCert) || config.IsPEM(clientKey) {
+		// Inline PEM data, not reloaded.
+		cert, err := config.LoadClientKeyPair(clientCert, clientKey)
+		if err != nil {
+			logger.LogIf(GlobalContext, fmt.Errorf("failed to load client key and cert, please check your endpoint configuration: %s",
+				err.Error()))
+			return transport
+		}
+		transport.TLSClientConfig.Certificates = []tls.Certificate{cert}
+		return transport
+	}
 	if clientCert != "" && clientKey != "" {
 		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 		defer cancel()
@@ -25,7 +25,9 @@ import (
 	"crypto/x509"
 	"encoding/pem"
 	"errors"
+	"fmt"
 	"io/ioutil"
+	"strings"
 
 	"github.com/minio/pkg/env"
 )
@@ -81,6 +83,12 @@ func LoadX509KeyPair(certFile, keyFile string) (tls.Certificate, error) {
 	if err != nil {
 		return tls.Certificate{}, ErrSSLUnexpectedError(err)
 	}
+	return X509KeyPair(certPEMBlock, keyPEMBlock)
+}
+
+// X509KeyPair - parses an X509 key pair from PEM encoded data, the
+// private key is decrypted as with LoadX509KeyPair.
+func X509KeyPair(certPEMBlock, keyPEMBlock []byte) (tls.Certificate, error) {
 	key, rest := pem.Decode(keyPEMBlock)
 	if len(rest) > 0 {
 		return tls.Certificate{}, ErrSSLUnexpectedData(nil).Msg("The private key contains additional data")
@@ -119,11 +127,68 @@ func LoadX509KeyPair(certFile, keyFile string) (tls.Certificate, error) {
 	return cert, nil
 }
 
-// EnsureCertAndKey checks if both client certificate and key paths are provided
+// EnsureCertAndKey checks if both client certificate and key are provided,
+// either as paths or as inline PEM data which is then validated.
 func EnsureCertAndKey(clientCert, clientKey string) error {
 	if (clientCert != "" && clientKey == "") ||
 		(clientCert == "" && clientKey != "") {
 		return errors.New("cert and key must be specified as a pair")
 	}
+	if IsPEM(clientCert) || IsPEM(clientKey) {
+		_, err := LoadClientKeyPair(clientCert, clientKey)
+		return err
+	}
 	return nil
 }
+
+// IsPEM returns true if value is inline PEM data rather than a path.
+func IsPEM(value string) bool {
+	return strings.HasPrefix(strings.TrimSpace(value), "-----BEGIN")
+}
+
+// LoadRootCAs - returns a pool of the CA certificates of value, given
+// either as a path or as inline PEM data, see IsPEM. It fails unless
+// value holds at least one certificate and nothing else.
+func LoadRootCAs(value string) (*x509.CertPool, error) {
+	data := []byte(value)
+	if !IsPEM(value) {
+		var err error
+		if data, err = ioutil.ReadFile(value); err != nil {
+			return nil, err
+		}
+	}
+	pool := x509.NewCertPool()
+	current := bytes.TrimSpace(data)
+	if len(current) == 0 {
+		return nil, errors.New("no CA certificate found")
+	}
+	for len(current) > 0 {
+		var pemBlock *pem.Block
+		if pemBlock, current = pem.Decode(current); pemBlock == nil || pemBlock.Type != "CERTIFICATE" {
+			return nil, errors.New("invalid CA certificate PEM data")
+		}
+		cert, err := x509.ParseCertificate(pemBlock.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CA certificate: %w", err)
+		}
+		pool.AddCert(cert)
+		current = bytes.TrimSpace(current)
+	}
+	return pool, nil
+}
+
+// LoadClientKeyPair - loads a client certificate and key, given either
+// as paths or as inline PEM data, see IsPEM. Both must be of the same kind.
+func LoadClientKeyPair(clientCert, clientKey string) (tls.Certificate, error) {
+	switch certPEM, keyPEM := IsPEM(clientCert), IsPEM(clientKey); {
+	case certPEM && keyPEM:
+		cert, err := X509KeyPair([]byte(clientCert), []byte(clientKey))
+		if err != nil {
+			return cert, fmt.Errorf("invalid client certificate and key PEM: %w", err)
+		}
+		return cert, nil
+	case certPEM || keyPEM:
+		return tls.Certificate{}, errors.New("cert and key must be both inline PEM data or both paths")
+	}
+	return LoadX509KeyPair(clientCert, clientKey)
+}
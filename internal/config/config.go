@@ -82,6 +82,7 @@ const (
 	LoggerWebhookSubSys  = "logger_webhook"
 	AuditWebhookSubSys   = "audit_webhook"
 	AuditKafkaSubSys     = "audit_kafka"
+	AuditAMQPSubSys      = "audit_amqp"
 	HealSubSys           = "heal"
 	ScannerSubSys        = "scanner"
 	CrawlerSubSys        = "crawler"
@@ -125,6 +126,7 @@ var LoggerSubSystems = set.CreateStringSet(
 	LoggerWebhookSubSys,
 	AuditWebhookSubSys,
 	AuditKafkaSubSys,
+	AuditAMQPSubSys,
 )
 
 // SubSystems - all supported sub-systems
@@ -140,6 +142,7 @@ var SubSystems = set.CreateStringSet(
 	LoggerWebhookSubSys,
 	AuditWebhookSubSys,
 	AuditKafkaSubSys,
+	AuditAMQPSubSys,
 	PolicyOPASubSys,
 	IdentityLDAPSubSys,
 	IdentityOpenIDSubSys,
@@ -169,6 +172,7 @@ var SubSystemsDynamic = set.CreateStringSet(
 	LoggerWebhookSubSys,
 	AuditWebhookSubSys,
 	AuditKafkaSubSys,
+	AuditAMQPSubSys,
 )
 
 // SubSystemsSingleTargets - subsystems which only support single target.
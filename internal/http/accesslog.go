@@ -0,0 +1,122 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"net/http"
+	"time"
+)
+
+// AccessLogTarget receives the access log entries, any logger target satisfies it.
+type AccessLogTarget interface {
+	Send(entry interface{}, errKind string) error
+}
+
+// AccessLogField selects the fields recorded in access log entries.
+type AccessLogField uint8
+
+// Access log fields, can be combined.
+const (
+	AccessLogMethod AccessLogField = 1 << iota
+	AccessLogPath
+	AccessLogStatus
+	AccessLogBytes
+	AccessLogDuration
+
+	AccessLogAllFields = AccessLogMethod | AccessLogPath | AccessLogStatus | AccessLogBytes | AccessLogDuration
+)
+
+// AccessLogEntry - access log entry of a single request.
+type AccessLogEntry struct {
+	Time     time.Time     `json:"time"`
+	Method   string        `json:"method,omitempty"`
+	Path     string        `json:"path,omitempty"`
+	Status   int           `json:"status,omitempty"`
+	Bytes    int64         `json:"bytes,omitempty"`
+	Duration time.Duration `json:"duration,omitempty"`
+}
+
+// accessLogWriter - traps the status code and number of bytes written.
+type accessLogWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (w *accessLogWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *accessLogWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.bytes += int64(n)
+	return n, err
+}
+
+// Flush - Calls the underlying Flush.
+func (w *accessLogWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// logAccess sends the access log entry of a served request.
+func (srv *Server) logAccess(w *accessLogWriter, r *http.Request, start time.Time) {
+	fields := srv.accessLogFields
+	entry := AccessLogEntry{Time: start.UTC()}
+	if fields&AccessLogMethod != 0 {
+		entry.Method = r.Method
+	}
+	if fields&AccessLogPath != 0 {
+		entry.Path = r.URL.Path
+	}
+	if fields&AccessLogStatus != 0 {
+		entry.Status = w.status
+		if entry.Status == 0 {
+			entry.Status = http.StatusOK
+		}
+	}
+	if fields&AccessLogBytes != 0 {
+		entry.Bytes = w.bytes
+	}
+	if fields&AccessLogDuration != 0 {
+		entry.Duration = time.Since(start)
+	}
+	srv.accessLog.Send(entry, "ALL")
+}
+
+// UseAccessLog send an access log entry for each request served by
+// this HTTP *Server to target, only the given fields are recorded,
+// all of them if none are given.
+func (srv *Server) UseAccessLog(target AccessLogTarget, fields ...AccessLogField) *Server {
+	srv.accessLog = target
+	srv.accessLogFields = 0
+	for _, field := range fields {
+		srv.accessLogFields |= field
+	}
+	if srv.accessLogFields == 0 {
+		srv.accessLogFields = AccessLogAllFields
+	}
+	return srv
+}
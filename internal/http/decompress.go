@@ -0,0 +1,106 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+// DefaultMaxDecompressedSize - default maximum size of decompressed request bodies.
+const DefaultMaxDecompressedSize = 64 * humanize.MiByte
+
+// ErrDecompressedTooLarge is returned when reading a decompressed
+// request body past the maximum decompressed size.
+var ErrDecompressedTooLarge = errors.New("http: decompressed request body too large")
+
+// decompressedBody - request body decompressed up to a maximum size.
+type decompressedBody struct {
+	io.Reader
+	body      io.ReadCloser // compressed body, closed with it.
+	remaining int64
+}
+
+func (b *decompressedBody) Read(p []byte) (int, error) {
+	if b.remaining <= 0 {
+		// Check whether there is more than the limit.
+		var one [1]byte
+		if n, _ := b.Reader.Read(one[:]); n > 0 {
+			return 0, ErrDecompressedTooLarge
+		}
+		return 0, io.EOF
+	}
+	if int64(len(p)) > b.remaining {
+		p = p[:b.remaining]
+	}
+	n, err := b.Reader.Read(p)
+	b.remaining -= int64(n)
+	return n, err
+}
+
+func (b *decompressedBody) Close() error {
+	return b.body.Close()
+}
+
+// decompressRequest replaces the body of a gzip or deflate encoded request
+// by its decompressed content, bounded by maxSize. Requests with another
+// or no encoding are left as is.
+func decompressRequest(r *http.Request, maxSize int64) error {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil
+	}
+	var (
+		reader io.Reader
+		err    error
+	)
+	switch strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding"))) {
+	case "gzip", "x-gzip":
+		reader, err = gzip.NewReader(r.Body)
+	case "deflate":
+		reader, err = zlib.NewReader(r.Body)
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	r.Body = &decompressedBody{Reader: reader, body: r.Body, remaining: maxSize}
+	r.Header.Del("Content-Encoding")
+	r.Header.Del("Content-Length")
+	r.ContentLength = -1
+	return nil
+}
+
+// UseRequestDecompression decompresses the gzip and deflate encoded request
+// bodies before they reach the handler, reading them past maxSize bytes once
+// decompressed fails with ErrDecompressedTooLarge, DefaultMaxDecompressedSize
+// if zero. Requests whose body cannot be decompressed are rejected with 400
+// (bad request), bodies with other encodings are passed as is.
+func (srv *Server) UseRequestDecompression(maxSize int64) *Server {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxDecompressedSize
+	}
+	srv.maxDecompressedSize = maxSize
+	return srv
+}
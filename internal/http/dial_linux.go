@@ -29,32 +29,52 @@ import (
 	"golang.org/x/sys/unix"
 )
 
-func setTCPParameters(network, address string, c syscall.RawConn) error {
-	c.Control(func(fdPtr uintptr) {
-		// got socket file descriptor to set parameters.
-		fd := int(fdPtr)
+// setTCPParametersFn returns a socket control function applying opts.
+func setTCPParametersFn(opts TCPOptions) func(network, address string, c syscall.RawConn) error {
+	return func(network, address string, c syscall.RawConn) error {
+		c.Control(func(fdPtr uintptr) {
+			// got socket file descriptor to set parameters.
+			fd := int(fdPtr)
 
-		_ = unix.SetsockoptInt(fd, unix.SOL_SOCKET, unix.SO_REUSEADDR, 1)
+			_ = unix.SetsockoptInt(fd, unix.SOL_SOCKET, unix.SO_REUSEADDR, 1)
 
-		_ = unix.SetsockoptInt(fd, unix.SOL_SOCKET, unix.SO_REUSEPORT, 1)
+			_ = unix.SetsockoptInt(fd, unix.SOL_SOCKET, unix.SO_REUSEPORT, 1)
 
-		// Enable TCP open
-		// https://lwn.net/Articles/508865/ - 16k queue size.
-		_ = syscall.SetsockoptInt(fd, syscall.SOL_TCP, unix.TCP_FASTOPEN, 16*1024)
+			// Enable TCP open
+			// https://lwn.net/Articles/508865/ - 16k queue size.
+			if opts.FastOpenQueueLen >= 0 {
+				qlen := opts.FastOpenQueueLen
+				if qlen == 0 {
+					qlen = 16 * 1024
+				}
+				_ = syscall.SetsockoptInt(fd, syscall.SOL_TCP, unix.TCP_FASTOPEN, qlen)
+			}
 
-		// Enable TCP fast connect
-		// TCPFastOpenConnect sets the underlying socket to use
-		// the TCP fast open connect. This feature is supported
-		// since Linux 4.11.
-		_ = syscall.SetsockoptInt(fd, syscall.IPPROTO_TCP, unix.TCP_FASTOPEN_CONNECT, 1)
+			// Enable TCP fast connect
+			// TCPFastOpenConnect sets the underlying socket to use
+			// the TCP fast open connect. This feature is supported
+			// since Linux 4.11.
+			_ = syscall.SetsockoptInt(fd, syscall.IPPROTO_TCP, unix.TCP_FASTOPEN_CONNECT, 1)
 
-		// Enable TCP quick ACK, John Nagle says
-		// "Set TCP_QUICKACK. If you find a case where that makes things worse, let me know."
-		_ = syscall.SetsockoptInt(fd, syscall.IPPROTO_TCP, unix.TCP_QUICKACK, 1)
-	})
-	return nil
+			// Enable TCP quick ACK, John Nagle says
+			// "Set TCP_QUICKACK. If you find a case where that makes things worse, let me know."
+			_ = syscall.SetsockoptInt(fd, syscall.IPPROTO_TCP, unix.TCP_QUICKACK, 1)
+
+			// Buffer sizes set on the listening socket are inherited
+			// by accepted connections and used for window scaling.
+			if opts.SendBufSize > 0 {
+				_ = syscall.SetsockoptInt(fd, syscall.SOL_SOCKET, syscall.SO_SNDBUF, opts.SendBufSize)
+			}
+			if opts.RecvBufSize > 0 {
+				_ = syscall.SetsockoptInt(fd, syscall.SOL_SOCKET, syscall.SO_RCVBUF, opts.RecvBufSize)
+			}
+		})
+		return nil
+	}
 }
 
+var setTCPParameters = setTCPParametersFn(TCPOptions{})
+
 // DialContext is a function to make custom Dial for internode communications
 type DialContext func(ctx context.Context, network, address string) (net.Conn, error)
 
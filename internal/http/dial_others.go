@@ -33,6 +33,11 @@ func setTCPParameters(string, string, syscall.RawConn) error {
 	return nil
 }
 
+// setTCPParametersFn returns a socket control function, the options are ignored.
+func setTCPParametersFn(TCPOptions) func(string, string, syscall.RawConn) error {
+	return setTCPParameters
+}
+
 // DialContext is a function to make custom Dial for internode communications
 type DialContext func(ctx context.Context, network, address string) (net.Conn, error)
 
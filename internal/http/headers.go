@@ -156,6 +156,9 @@ const (
 	// Reports number of drives currently healing
 	MinIOHealingDrives = "x-minio-healing-drives"
 
+	// Reports a debatable setting of the config set, once per setting
+	MinIOConfigWarning = "x-minio-config-warning"
+
 	// Header indicates if the delete marker should be preserved by client
 	MinIOSourceDeleteMarker = "x-minio-source-deletemarker"
 
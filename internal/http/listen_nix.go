@@ -25,6 +25,8 @@ import (
 )
 
 // Unix listener with special TCP options.
-var listenCfg = net.ListenConfig{
-	Control: setTCPParameters,
+func newListenConfig(opts TCPOptions) net.ListenConfig {
+	return net.ListenConfig{
+		Control: setTCPParametersFn(opts),
+	}
 }
@@ -23,4 +23,6 @@ package http
 import "net"
 
 // Windows, plan9 specific listener.
-var listenCfg = net.ListenConfig{}
+func newListenConfig(TCPOptions) net.ListenConfig {
+	return net.ListenConfig{}
+}
@@ -19,11 +19,47 @@ package http
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
+	"os"
+	"strconv"
+	"strings"
 	"syscall"
 )
 
+// inheritedFDPrefix - prefix of server addresses referring to a listening
+// socket inherited from the parent process, e.g. `fd://3` with systemd
+// socket activation.
+const inheritedFDPrefix = "fd://"
+
+// TCPOptions specify customizable TCP optimizations on the listening sockets.
+type TCPOptions struct {
+	// TCP_FASTOPEN queue length, 0 uses the default of 16k
+	// and a negative value disables it, only on Linux.
+	FastOpenQueueLen int
+	// Disable TCP_NODELAY on accepted connections, enabling
+	// Nagle's algorithm for fewer but larger segments.
+	DisableNoDelay bool
+	// SO_SNDBUF and SO_RCVBUF sizes in bytes for accepted
+	// connections, 0 leaves the system defaults.
+	SendBufSize int
+	RecvBufSize int
+}
+
+// applyConn sets the per connection options on an accepted connection.
+func (opts TCPOptions) applyConn(conn *net.TCPConn) {
+	if opts.DisableNoDelay {
+		conn.SetNoDelay(false)
+	}
+	if opts.SendBufSize > 0 {
+		conn.SetWriteBuffer(opts.SendBufSize)
+	}
+	if opts.RecvBufSize > 0 {
+		conn.SetReadBuffer(opts.RecvBufSize)
+	}
+}
+
 type acceptResult struct {
 	conn net.Conn
 	err  error
@@ -33,9 +69,16 @@ type acceptResult struct {
 // httpListener - HTTP listener capable of handling multiple server addresses.
 type httpListener struct {
 	tcpListeners []*net.TCPListener // underlaying TCP listeners.
+	serverAddrs  []string           // server address each TCP listener was set up for, as given.
+	inherited    []*os.File         // inherited listening sockets, left open on Close.
 	acceptCh     chan acceptResult  // channel where all TCP listeners write accepted connection.
+	opts         TCPOptions
 	ctx          context.Context
 	ctxCanceler  context.CancelFunc
+
+	// wrapConn when set, wraps the connections accepted, along
+	// with the server address they were accepted on.
+	wrapConn func(serverAddr string, conn net.Conn) net.Conn
 }
 
 // start - starts separate goroutine for each TCP listener.  A valid new connection is passed to httpListener.acceptCh.
@@ -58,6 +101,7 @@ func (listener *httpListener) start() {
 			tcpConn, err := tcpListener.AcceptTCP()
 			if tcpConn != nil {
 				tcpConn.SetKeepAlive(true)
+				listener.opts.applyConn(tcpConn)
 			}
 			send(acceptResult{tcpConn, err, idx})
 		}
@@ -74,6 +118,9 @@ func (listener *httpListener) Accept() (conn net.Conn, err error) {
 	select {
 	case result, ok := <-listener.acceptCh:
 		if ok {
+			if result.err == nil && listener.wrapConn != nil {
+				return listener.wrapConn(listener.serverAddrs[result.lidx], result.conn), nil
+			}
 			return result.conn, result.err
 		}
 	case <-listener.ctx.Done():
@@ -92,6 +139,23 @@ func (listener *httpListener) Close() (err error) {
 	return nil
 }
 
+// files - returns duplicates of the listening sockets, which are left
+// open when the listener is closed.
+func (listener *httpListener) files() ([]*os.File, error) {
+	files := make([]*os.File, 0, len(listener.tcpListeners))
+	for _, tcpListener := range listener.tcpListeners {
+		f, err := tcpListener.File()
+		if err != nil {
+			for _, f := range files {
+				f.Close()
+			}
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
 // Addr - net.Listener interface compatible method returns net.Addr.  In case of multiple TCP listeners, it returns '0.0.0.0' as IP address.
 func (listener *httpListener) Addr() (addr net.Addr) {
 	addr = listener.tcpListeners[0].Addr()
@@ -121,10 +185,13 @@ func (listener *httpListener) Addrs() (addrs []net.Addr) {
 // httpListener is capable to
 // * listen to multiple addresses
 // * controls incoming connections only doing HTTP protocol
-func newHTTPListener(ctx context.Context, serverAddrs []string) (listener *httpListener, err error) {
-	var tcpListeners []*net.TCPListener
+func newHTTPListener(ctx context.Context, serverAddrs []string, opts TCPOptions) (listener *httpListener, err error) {
+	var (
+		tcpListeners []*net.TCPListener
+		inherited    []*os.File
+	)
 
-	// Close all opened listeners on error
+	// Close all opened listeners and inherited files on error
 	defer func() {
 		if err == nil {
 			return
@@ -134,11 +201,36 @@ func newHTTPListener(ctx context.Context, serverAddrs []string) (listener *httpL
 			// Ignore error on close.
 			tcpListener.Close()
 		}
+		for _, f := range inherited {
+			f.Close()
+		}
 	}()
 
+	var origins []string
+	if serverAddrs, origins, err = resolveInterfaceAddrs(serverAddrs); err != nil {
+		return nil, err
+	}
+
+	listenCfg := newListenConfig(opts)
 	for _, serverAddr := range serverAddrs {
 		var l net.Listener
-		if l, err = listenCfg.Listen(ctx, "tcp", serverAddr); err != nil {
+		if strings.HasPrefix(serverAddr, inheritedFDPrefix) {
+			var f *os.File
+			if f, err = inheritedFile(serverAddr); err != nil {
+				return nil, err
+			}
+			inherited = append(inherited, f)
+			// Listens on a duplicate of the inherited descriptor, the
+			// socket options are left as set up by the parent process.
+			if l, err = net.FileListener(f); err != nil {
+				return nil, fmt.Errorf("unable to listen on %s: %w", serverAddr, err)
+			}
+		} else if l, err = listenCfg.Listen(ctx, "tcp", serverAddr); err != nil {
+			var dnsErr *net.DNSError
+			if errors.As(err, &dnsErr) {
+				return nil, fmt.Errorf("unable to listen on %s, %s is neither a network interface nor a known host: %w",
+					serverAddr, dnsErr.Name, err)
+			}
 			return nil, err
 		}
 
@@ -152,10 +244,77 @@ func newHTTPListener(ctx context.Context, serverAddrs []string) (listener *httpL
 
 	listener = &httpListener{
 		tcpListeners: tcpListeners,
+		serverAddrs:  origins,
+		inherited:    inherited,
 		acceptCh:     make(chan acceptResult, len(tcpListeners)),
+		opts:         opts,
 	}
 	listener.ctx, listener.ctxCanceler = context.WithCancel(ctx)
 	listener.start()
 
 	return listener, nil
 }
+
+// InheritedFDAddrs - returns the server addresses of n listening sockets
+// passed to a child process as its extra files, e.g. `fd://3` for the
+// first one, for the child to serve on them, see Server.ListenerFiles.
+func InheritedFDAddrs(n int) []string {
+	addrs := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		// Extra files follow the standard input, output and error.
+		addrs = append(addrs, inheritedFDPrefix+strconv.Itoa(3+i))
+	}
+	return addrs
+}
+
+// inheritedFile - returns the file of the inherited descriptor referred
+// to by a `fd://<number>` server address.
+func inheritedFile(serverAddr string) (*os.File, error) {
+	fd, err := strconv.Atoi(strings.TrimPrefix(serverAddr, inheritedFDPrefix))
+	if err != nil || fd < 0 {
+		return nil, fmt.Errorf("invalid inherited file descriptor %s", serverAddr)
+	}
+	return os.NewFile(uintptr(fd), serverAddr), nil
+}
+
+// resolveInterfaceAddrs - replaces the server addresses naming a network
+// interface instead of a host, e.g. `eth0:9000`, by the current IPv4 and
+// IPv6 addresses of the interface with the same port. Link-local addresses
+// are left out. The server address each resolved address comes from is
+// returned at the same index of origins.
+func resolveInterfaceAddrs(serverAddrs []string) (resolved, origins []string, err error) {
+	resolved = make([]string, 0, len(serverAddrs))
+	origins = make([]string, 0, len(serverAddrs))
+	for _, serverAddr := range serverAddrs {
+		host, port, err := net.SplitHostPort(serverAddr)
+		if err != nil || host == "" || net.ParseIP(host) != nil || strings.HasPrefix(serverAddr, inheritedFDPrefix) {
+			resolved = append(resolved, serverAddr)
+			origins = append(origins, serverAddr)
+			continue
+		}
+		iface, err := net.InterfaceByName(host)
+		if err != nil {
+			// Not an interface, a host name.
+			resolved = append(resolved, serverAddr)
+			origins = append(origins, serverAddr)
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to list the addresses of network interface %s: %w", host, err)
+		}
+		n := len(resolved)
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok || ipNet.IP.IsLinkLocalUnicast() {
+				continue
+			}
+			resolved = append(resolved, net.JoinHostPort(ipNet.IP.String(), port))
+			origins = append(origins, serverAddr)
+		}
+		if len(resolved) == n {
+			return nil, nil, fmt.Errorf("unable to listen on %s, network interface %s has no address", serverAddr, host)
+		}
+	}
+	return resolved, origins, nil
+}
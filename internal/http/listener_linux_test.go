@@ -0,0 +1,208 @@
+//go:build linux
+// +build linux
+
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"reflect"
+	"syscall"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+func getsockoptInt(t *testing.T, c syscall.Conn, level, opt int) int {
+	t.Helper()
+	rawConn, err := c.SyscallConn()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		value  int
+		optErr error
+	)
+	if err = rawConn.Control(func(fd uintptr) {
+		value, optErr = unix.GetsockoptInt(int(fd), level, opt)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if optErr != nil {
+		t.Skipf("getsockopt(%d, %d) not supported: %v", level, opt, optErr)
+	}
+	return value
+}
+
+func TestHTTPListenerTCPOptions(t *testing.T) {
+	opts := TCPOptions{
+		FastOpenQueueLen: 256,
+		DisableNoDelay:   true,
+		RecvBufSize:      64 * 1024,
+	}
+	listener, err := newHTTPListener(context.Background(), []string{"127.0.0.1:0"}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	if qlen := getsockoptInt(t, listener.tcpListeners[0], unix.SOL_TCP, unix.TCP_FASTOPEN); qlen != opts.FastOpenQueueLen {
+		t.Errorf("TCP_FASTOPEN: expected = %d, got = %d", opts.FastOpenQueueLen, qlen)
+	}
+
+	client, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	conn, err := listener.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	tcpConn := conn.(*net.TCPConn)
+	if noDelay := getsockoptInt(t, tcpConn, unix.IPPROTO_TCP, unix.TCP_NODELAY); noDelay != 0 {
+		t.Errorf("TCP_NODELAY: expected = 0, got = %d", noDelay)
+	}
+	// Linux doubles the requested value to allow for bookkeeping overhead.
+	if rcvBuf := getsockoptInt(t, tcpConn, unix.SOL_SOCKET, unix.SO_RCVBUF); rcvBuf < opts.RecvBufSize {
+		t.Errorf("SO_RCVBUF: expected >= %d, got = %d", opts.RecvBufSize, rcvBuf)
+	}
+}
+
+func TestHTTPListenerInheritedFD(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	f, err := l.(*net.TCPListener).File()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	listener, err := newHTTPListener(context.Background(), []string{fmt.Sprintf("fd://%d", f.Fd())}, TCPOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if listener.Addr().String() != l.Addr().String() {
+		t.Fatalf("expected listener on %s, got %s", l.Addr(), listener.Addr())
+	}
+
+	client, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	conn, err := listener.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+
+	listener.Close()
+	var stat unix.Stat_t
+	if err = unix.Fstat(int(f.Fd()), &stat); err != nil {
+		t.Fatalf("expected inherited descriptor to be left open, got %v", err)
+	}
+
+	for _, addr := range []string{"fd://", "fd://-1", "fd://abc"} {
+		if _, err = newHTTPListener(context.Background(), []string{addr}, TCPOptions{}); err == nil {
+			t.Fatalf("expected %s to be rejected", addr)
+		}
+	}
+
+	// The inherited descriptor is closed when a later address fails.
+	fd, err := unix.Dup(int(f.Fd()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	addrs := []string{fmt.Sprintf("fd://%d", fd), "127.0.0.1:-1"}
+	if _, err = newHTTPListener(context.Background(), addrs, TCPOptions{}); err == nil {
+		unix.Close(fd)
+		t.Fatal("expected an invalid address to be rejected")
+	}
+	if err = unix.Fstat(fd, &stat); err != unix.EBADF {
+		unix.Close(fd)
+		t.Fatalf("expected inherited descriptor to be closed, got %v", err)
+	}
+}
+
+func TestServerListenerFiles(t *testing.T) {
+	handler := func(name string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(name))
+		})
+	}
+	old := NewServer(nil).UseHandler(handler("old")).UseShutdownTimeout(time.Second)
+	if _, err := old.ListenerFiles(); err == nil {
+		t.Fatal("expected an error before the server listens")
+	}
+	addr := startTestServer(t, old)
+
+	files, err := old.ListenerFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}()
+	if len(files) != 1 {
+		t.Fatalf("expected 1 listening socket, got %d", len(files))
+	}
+	// The old server is drained while the new one takes over.
+	if err = old.Shutdown(); err != nil {
+		t.Fatal(err)
+	}
+
+	upgraded := NewServer([]string{fmt.Sprintf("fd://%d", files[0].Fd())}).UseHandler(handler("new"))
+	go upgraded.Start(context.Background())
+	defer upgraded.Shutdown()
+
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		resp, err := client.Get("http://" + addr)
+		if err == nil {
+			body, _ := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			if string(body) == "new" {
+				break
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the handed off socket to be served, got %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if addrs := InheritedFDAddrs(2); !reflect.DeepEqual(addrs, []string{"fd://3", "fd://4"}) {
+		t.Fatalf("unexpected inherited addresses %v", addrs)
+	}
+}
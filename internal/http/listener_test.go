@@ -20,6 +20,7 @@ package http
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"net"
 	"strconv"
 	"strings"
@@ -150,6 +151,7 @@ func TestNewHTTPListener(t *testing.T) {
 	for _, testCase := range testCases {
 		listener, err := newHTTPListener(context.Background(),
 			testCase.serverAddrs,
+			TCPOptions{},
 		)
 
 		if !testCase.expectedErr {
@@ -183,6 +185,7 @@ func TestHTTPListenerStartClose(t *testing.T) {
 	for i, testCase := range testCases {
 		listener, err := newHTTPListener(context.Background(),
 			testCase.serverAddrs,
+			TCPOptions{},
 		)
 		if err != nil {
 			if strings.Contains(err.Error(), "The requested address is not valid in its context") {
@@ -229,6 +232,7 @@ func TestHTTPListenerAddr(t *testing.T) {
 	for i, testCase := range testCases {
 		listener, err := newHTTPListener(context.Background(),
 			testCase.serverAddrs,
+			TCPOptions{},
 		)
 		if err != nil {
 			if strings.Contains(err.Error(), "The requested address is not valid in its context") {
@@ -272,6 +276,7 @@ func TestHTTPListenerAddrs(t *testing.T) {
 	for i, testCase := range testCases {
 		listener, err := newHTTPListener(context.Background(),
 			testCase.serverAddrs,
+			TCPOptions{},
 		)
 		if err != nil {
 			if strings.Contains(err.Error(), "The requested address is not valid in its context") {
@@ -297,3 +302,53 @@ func TestHTTPListenerAddrs(t *testing.T) {
 		listener.Close()
 	}
 }
+
+func TestHTTPListenerInterfaceAddrs(t *testing.T) {
+	iface, err := loopbackInterface()
+	if err != nil {
+		t.Skip(err)
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := getNextPort()
+	expectedAddrs := set.NewStringSet()
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && !ipNet.IP.IsLinkLocalUnicast() {
+			expectedAddrs.Add(net.JoinHostPort(ipNet.IP.String(), port))
+		}
+	}
+
+	listener, err := newHTTPListener(context.Background(), []string{iface.Name + ":" + port}, TCPOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	addrSet := set.NewStringSet()
+	for _, addr := range listener.Addrs() {
+		addrSet.Add(addr.String())
+	}
+	if !addrSet.Equals(expectedAddrs) {
+		t.Fatalf("expected = %v, got = %v", expectedAddrs, addrSet)
+	}
+
+	_, err = newHTTPListener(context.Background(), []string{"nosuchif0.invalid:" + getNextPort()}, TCPOptions{})
+	if err == nil || !strings.Contains(err.Error(), "neither a network interface nor a known host") {
+		t.Fatalf("expected an unknown interface to be reported, got %v", err)
+	}
+}
+
+// loopbackInterface - returns the loopback network interface.
+func loopbackInterface() (*net.Interface, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+	for i := range ifaces {
+		if ifaces[i].Flags&net.FlagLoopback != 0 {
+			return &ifaces[i], nil
+		}
+	}
+	return nil, errors.New("no loopback interface")
+}
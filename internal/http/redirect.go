@@ -0,0 +1,140 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// tlsRecordHandshake - content type of the TLS record starting a
+// handshake, the first byte sent by a TLS client.
+const tlsRecordHandshake = 0x16
+
+// tlsAlertHandshakeFailure - fatal handshake_failure TLS alert record,
+// sent to TLS clients of a plaintext address which cannot serve TLS.
+var tlsAlertHandshakeFailure = []byte{0x15, 0x03, 0x01, 0x00, 0x02, 0x02, 0x28}
+
+// httpsRedirectKey - context key of the HTTPS address the requests
+// of a connection are redirected to.
+type httpsRedirectKey struct{}
+
+// httpsRedirectURL - returns the URL of r on httpsAddr. The host of
+// the request is kept if httpsAddr has none, e.g. ":9443".
+func httpsRedirectURL(r *http.Request, httpsAddr string) string {
+	host, port, err := net.SplitHostPort(httpsAddr)
+	if err != nil {
+		host, port = httpsAddr, ""
+	}
+	if host == "" {
+		host = r.Host
+		if h, _, err := net.SplitHostPort(r.Host); err == nil {
+			host = h
+		}
+		host = strings.Trim(host, "[]")
+	}
+	if port != "" && port != "443" {
+		host = net.JoinHostPort(host, port)
+	} else if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	return "https://" + host + r.URL.RequestURI()
+}
+
+// redirectConn - connection of an address redirecting to HTTPS. Clients
+// mistakenly starting a TLS handshake on it are served over TLS with
+// tlsConfig if set, to receive the redirect, and sent a TLS alert
+// otherwise, rather than a plaintext response they cannot parse.
+type redirectConn struct {
+	net.Conn
+	httpsAddr string
+	tlsConfig *tls.Config
+
+	sniffed bool
+	prefix  []byte   // bytes read while sniffing, not returned yet.
+	tlsConn net.Conn // set once the client started a TLS handshake.
+}
+
+// sniff - reads the first byte sent by the client to tell whether
+// it starts a TLS handshake, only called by the first Read.
+func (c *redirectConn) sniff() error {
+	c.sniffed = true
+	b := make([]byte, 1)
+	if _, err := io.ReadFull(c.Conn, b); err != nil {
+		return err
+	}
+	if b[0] != tlsRecordHandshake {
+		c.prefix = b
+		return nil
+	}
+	if c.tlsConfig == nil {
+		c.Conn.Write(tlsAlertHandshakeFailure)
+		return io.EOF
+	}
+	c.tlsConn = tls.Server(&prefixConn{Conn: c.Conn, prefix: b}, c.tlsConfig)
+	return nil
+}
+
+func (c *redirectConn) Read(b []byte) (int, error) {
+	if !c.sniffed {
+		if err := c.sniff(); err != nil {
+			return 0, err
+		}
+	}
+	if c.tlsConn != nil {
+		return c.tlsConn.Read(b)
+	}
+	if len(c.prefix) > 0 {
+		n := copy(b, c.prefix)
+		c.prefix = c.prefix[n:]
+		return n, nil
+	}
+	return c.Conn.Read(b)
+}
+
+func (c *redirectConn) Write(b []byte) (int, error) {
+	if c.tlsConn != nil {
+		return c.tlsConn.Write(b)
+	}
+	return c.Conn.Write(b)
+}
+
+func (c *redirectConn) Close() error {
+	if c.tlsConn != nil {
+		return c.tlsConn.Close()
+	}
+	return c.Conn.Close()
+}
+
+// prefixConn - net.Conn returning prefix before the bytes read from Conn.
+type prefixConn struct {
+	net.Conn
+	prefix []byte
+}
+
+func (c *prefixConn) Read(b []byte) (int, error) {
+	if len(c.prefix) > 0 {
+		n := copy(b, c.prefix)
+		c.prefix = c.prefix[n:]
+		return n, nil
+	}
+	return c.Conn.Read(b)
+}
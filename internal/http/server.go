@@ -21,11 +21,16 @@ import (
 	"context"
 	"crypto/tls"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"log"
+	"math"
 	"net"
 	"net/http"
+	"os"
 	"runtime/pprof"
+	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -54,12 +59,43 @@ const (
 // Server - extended http.Server supports multiple addresses to serve and enhanced connection handling.
 type Server struct {
 	http.Server
-	Addrs           []string      // addresses on which the server listens for new connection.
+	Addrs           []string      // addresses on which the server listens for new connection, `fd://<n>` for an inherited socket, `<interface>:<port>` for the addresses of a network interface.
 	ShutdownTimeout time.Duration // timeout used for graceful server shutdown.
+	TCPOptions      TCPOptions    // TCP socket options applied to the listeners.
 	listenerMutex   sync.Mutex    // to guard 'listener' field.
 	listener        *httpListener // HTTP listener for all 'Addrs' field.
 	inShutdown      uint32        // indicates whether the server is in shutdown or not
+	lameDuck        uint32        // indicates whether the server is draining before shutdown.
 	requestCount    int32         // counter holds no. of request in progress.
+	rejectedCount   int32         // counter holds no. of request rejected during shutdown.
+	shedCount       int32         // counter holds no. of request shed above the in-flight limit.
+	counters        *connCounters // bytes read from and written to connections, nil unless counted.
+
+	accessLog       AccessLogTarget // optional target for access log entries.
+	accessLogFields AccessLogField  // fields recorded in access log entries.
+
+	longLived       func(r *http.Request) bool           // identifies long-lived requests, e.g. SSE or WebSocket.
+	longLivedMutex  sync.Mutex                           // to guard 'longLivedCancel' field.
+	longLivedCancel map[*http.Request]context.CancelFunc // cancels in progress long-lived requests.
+
+	maxBodySize   int64                      // maximum size of request bodies, unlimited if zero.
+	maxBodyExempt func(r *http.Request) bool // identifies requests whose body size is not limited.
+
+	maxDecompressedSize int64 // maximum size of decompressed request bodies, not decompressed if zero.
+
+	maxInFlight       int32                      // maximum no. of requests in progress, unlimited if zero.
+	maxInFlightExempt func(r *http.Request) bool // identifies requests never shed, e.g. health checks.
+
+	preShutdownMutex sync.Mutex                    // to guard 'preShutdown' field.
+	preShutdown      []func(context.Context) error // hooks run before the listener is closed on shutdown.
+	preShutdownOnce  sync.Once                     // runs the pre-shutdown hooks once.
+	preShutdownErr   error                         // errors of the pre-shutdown hooks.
+
+	onListen      func(addr string)         // called with each address listened on, once all are bound.
+	tlsObserver   func(tls.ConnectionState) // observes the TLS state negotiated by each connection.
+	activeTLS     atomic.Value              // *tls.Config used by new TLS handshakes, swapped by ReloadTLSConfig.
+	addrTLS       map[string]*tls.Config    // TLS config of some of the 'Addrs', nil for plaintext, instead of 'TLSConfig'.
+	httpsRedirect map[string]string         // HTTPS address the requests to some of the 'Addrs' are redirected to.
 }
 
 // GetRequestCount - returns number of request in progress.
@@ -67,20 +103,96 @@ func (srv *Server) GetRequestCount() int {
 	return int(atomic.LoadInt32(&srv.requestCount))
 }
 
+// GetBytesIn - returns number of bytes read from all connections,
+// always 0 unless counted, see UseConnCounters.
+func (srv *Server) GetBytesIn() uint64 {
+	if srv.counters == nil {
+		return 0
+	}
+	return atomic.LoadUint64(&srv.counters.bytesIn)
+}
+
+// GetBytesOut - returns number of bytes written to all connections,
+// always 0 unless counted, see UseConnCounters.
+func (srv *Server) GetBytesOut() uint64 {
+	if srv.counters == nil {
+		return 0
+	}
+	return atomic.LoadUint64(&srv.counters.bytesOut)
+}
+
+// GetShutdownRejectedCount - returns number of requests rejected during shutdown.
+func (srv *Server) GetShutdownRejectedCount() int {
+	return int(atomic.LoadInt32(&srv.rejectedCount))
+}
+
+// GetShedCount - returns number of requests shed above the in-flight limit.
+func (srv *Server) GetShedCount() int {
+	return int(atomic.LoadInt32(&srv.shedCount))
+}
+
+// SetLameDuck - sets whether the server is in lame duck mode, where it
+// keeps serving requests normally but health probes should report it as
+// not ready so that load balancers stop sending it new traffic.
+func (srv *Server) SetLameDuck(lameDuck bool) {
+	var v uint32
+	if lameDuck {
+		v = 1
+	}
+	atomic.StoreUint32(&srv.lameDuck, v)
+}
+
+// IsLameDuck - returns true if the server is in lame duck mode.
+func (srv *Server) IsLameDuck() bool {
+	return atomic.LoadUint32(&srv.lameDuck) == 1
+}
+
 // Start - start HTTP server
 func (srv *Server) Start(ctx context.Context) (err error) {
 	// Take a copy of server fields.
 	var tlsConfig *tls.Config
 	if srv.TLSConfig != nil {
-		tlsConfig = srv.TLSConfig.Clone()
+		// Each handshake picks the active config so that it can be
+		// swapped by ReloadTLSConfig without reopening the listener.
+		srv.activeTLS.Store(srv.prepareTLSConfig(srv.TLSConfig))
+		tlsConfig = &tls.Config{GetConfigForClient: srv.getConfigForClient}
+	}
+	addrTLS := make(map[string]*tls.Config, len(srv.addrTLS))
+	for addr, cfg := range srv.addrTLS {
+		if !containsAddr(srv.Addrs, addr) {
+			return fmt.Errorf("tls config set for %s, which is not a server address", addr)
+		}
+		if cfg != nil {
+			cfg = srv.prepareTLSConfig(cfg)
+		}
+		addrTLS[addr] = cfg
+	}
+	httpsRedirect := make(map[string]string, len(srv.httpsRedirect))
+	for addr, httpsAddr := range srv.httpsRedirect {
+		if !containsAddr(srv.Addrs, addr) {
+			return fmt.Errorf("https redirect set for %s, which is not a server address", addr)
+		}
+		httpsRedirect[addr] = httpsAddr
 	}
 	handler := srv.Handler // if srv.Handler holds non-synced state -> possible data race
+	// Clients are asked to retry once the shutdown timeout has elapsed.
+	retryAfterSecs := int(math.Ceil(srv.ShutdownTimeout.Seconds()))
+	if retryAfterSecs < 1 {
+		retryAfterSecs = 1
+	}
+	retryAfter := strconv.Itoa(retryAfterSecs)
+	accessLog := srv.accessLog
+	longLived := srv.longLived
+	maxBodySize, maxBodyExempt := srv.maxBodySize, srv.maxBodyExempt
+	maxDecompressedSize := srv.maxDecompressedSize
+	maxInFlight, maxInFlightExempt := srv.maxInFlight, srv.maxInFlightExempt
 
 	// Create new HTTP listener.
 	var listener *httpListener
 	listener, err = newHTTPListener(
 		ctx,
 		srv.Addrs,
+		srv.TCPOptions,
 	)
 	if err != nil {
 		return err
@@ -88,16 +200,71 @@ func (srv *Server) Start(ctx context.Context) (err error) {
 
 	// Wrap given handler to do additional
 	// * return 503 (service unavailable) if the server in shutdown.
+	// * return 503 (service unavailable) if too many requests are in progress.
+	// * send an access log entry if configured.
+	// * return 413 (request entity too large) if the body exceeds the maximum size.
+	// * decompress the body if configured, return 400 (bad request) if it fails.
 	wrappedHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if accessLog != nil {
+			alw := &accessLogWriter{ResponseWriter: w}
+			defer srv.logAccess(alw, r, time.Now())
+			w = alw
+		}
+
+		// Redirect the plaintext requests of an address redirecting to HTTPS.
+		if httpsAddr, ok := r.Context().Value(httpsRedirectKey{}).(string); ok && r.TLS == nil {
+			http.Redirect(w, r, httpsRedirectURL(r, httpsAddr), http.StatusMovedPermanently)
+			return
+		}
+
 		// If server is in shutdown.
 		if atomic.LoadUint32(&srv.inShutdown) != 0 {
+			atomic.AddInt32(&srv.rejectedCount, 1)
 			// To indicate disable keep-alives
 			w.Header().Set("Connection", "close")
+			w.Header().Set(RetryAfter, retryAfter)
 			w.WriteHeader(http.StatusServiceUnavailable)
 			w.Write([]byte(http.ErrServerClosed.Error()))
 			return
 		}
 
+		// Shed load to protect the latency of the requests in progress.
+		if maxInFlight > 0 && atomic.LoadInt32(&srv.requestCount) >= maxInFlight &&
+			(maxInFlightExempt == nil || !maxInFlightExempt(r)) {
+			atomic.AddInt32(&srv.shedCount, 1)
+			w.Header().Set(RetryAfter, "1")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		// Bound the body of requests unless exempted, reads past the
+		// limit of a body of unknown length fail instead.
+		if maxBodySize > 0 && r.Body != nil && (maxBodyExempt == nil || !maxBodyExempt(r)) {
+			if r.ContentLength > maxBodySize {
+				w.Header().Set("Connection", "close")
+				w.WriteHeader(http.StatusRequestEntityTooLarge)
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
+		}
+
+		if maxDecompressedSize > 0 {
+			if err := decompressRequest(r, maxDecompressedSize); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+		}
+
+		// Long-lived requests are not waited for on shutdown,
+		// their context is canceled instead.
+		if longLived != nil && longLived(r) {
+			ctx, cancel := context.WithCancel(r.Context())
+			srv.trackLongLived(r, cancel)
+			defer srv.untrackLongLived(r)
+			handler.ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
+
 		atomic.AddInt32(&srv.requestCount, 1)
 		defer atomic.AddInt32(&srv.requestCount, -1)
 
@@ -108,17 +275,117 @@ func (srv *Server) Start(ctx context.Context) (err error) {
 	srv.listenerMutex.Lock()
 	srv.Handler = wrappedHandler
 	srv.listener = listener
+	if len(httpsRedirect) > 0 {
+		// Tags the requests of the addresses redirecting to HTTPS.
+		connContext := srv.ConnContext
+		srv.ConnContext = func(ctx context.Context, c net.Conn) context.Context {
+			if connContext != nil {
+				ctx = connContext(ctx, c)
+			}
+			if rc, ok := c.(*redirectConn); ok {
+				ctx = context.WithValue(ctx, httpsRedirectKey{}, rc.httpsAddr)
+			}
+			return ctx
+		}
+	}
 	srv.listenerMutex.Unlock()
 
-	// Start servicing with listener.
-	if tlsConfig != nil {
-		return srv.Server.Serve(tls.NewListener(listener, tlsConfig))
+	if srv.onListen != nil {
+		for _, addr := range listener.Addrs() {
+			srv.onListen(addr.String())
+		}
+	}
+
+	// Connections are served with the TLS config of the address
+	// they were accepted on, if any, bytes are counted below TLS.
+	listener.wrapConn = func(serverAddr string, conn net.Conn) net.Conn {
+		if srv.counters != nil {
+			conn = &countingConn{Conn: conn, counters: srv.counters}
+		}
+		if httpsAddr, ok := httpsRedirect[serverAddr]; ok {
+			cfg, ok := addrTLS[httpsAddr]
+			if !ok {
+				cfg = tlsConfig
+			}
+			return &redirectConn{Conn: conn, httpsAddr: httpsAddr, tlsConfig: cfg}
+		}
+		cfg, ok := addrTLS[serverAddr]
+		if !ok {
+			cfg = tlsConfig
+		}
+		if cfg != nil {
+			return tls.Server(conn, cfg)
+		}
+		return conn
 	}
+
+	// Start servicing with listener.
 	return srv.Server.Serve(listener)
 }
 
+// containsAddr - returns true if addr is one of addrs.
+func containsAddr(addrs []string, addr string) bool {
+	for _, a := range addrs {
+		if a == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// prepareTLSConfig - returns a copy of cfg observed by the TLS observer, if any.
+func (srv *Server) prepareTLSConfig(cfg *tls.Config) *tls.Config {
+	cfg = cfg.Clone()
+	if srv.tlsObserver != nil {
+		observe, verify := srv.tlsObserver, cfg.VerifyConnection
+		cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+			if verify != nil {
+				if err := verify(cs); err != nil {
+					return err
+				}
+			}
+			observe(cs)
+			return nil
+		}
+	}
+	return cfg
+}
+
+// getConfigForClient - returns the active TLS config for a new handshake.
+func (srv *Server) getConfigForClient(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+	cfg := srv.activeTLS.Load().(*tls.Config)
+	if cfg.GetConfigForClient != nil {
+		if clientCfg, err := cfg.GetConfigForClient(hello); clientCfg != nil || err != nil {
+			return clientCfg, err
+		}
+	}
+	return cfg, nil
+}
+
+// ReloadTLSConfig - swaps the TLS config of a running server, e.g. to
+// pick up renewed certificates. New handshakes use cfg while existing
+// connections are left untouched.
+func (srv *Server) ReloadTLSConfig(cfg *tls.Config) error {
+	if cfg == nil {
+		return errors.New("tls config must not be nil")
+	}
+	if srv.activeTLS.Load() == nil {
+		return errors.New("server is not serving TLS")
+	}
+	srv.activeTLS.Store(srv.prepareTLSConfig(cfg))
+	return nil
+}
+
 // Shutdown - shuts down HTTP server.
 func (srv *Server) Shutdown() error {
+	return srv.ShutdownWithProgress(context.Background(), nil)
+}
+
+// ShutdownWithProgress - shuts down HTTP server as Shutdown does, progress
+// is called with the number of requests still in progress each time they
+// are polled while draining, e.g. for an orchestrator to decide whether
+// to escalate. ctx cancels the wait for the requests in progress.
+func (srv *Server) ShutdownWithProgress(ctx context.Context, progress func(remaining int)) error {
 	srv.listenerMutex.Lock()
 	if srv.listener == nil {
 		srv.listenerMutex.Unlock()
@@ -126,11 +393,26 @@ func (srv *Server) Shutdown() error {
 	}
 	srv.listenerMutex.Unlock()
 
+	// Hooks run once, while requests are still served, concurrent
+	// callers wait for them before the listener can be closed.
+	srv.preShutdownOnce.Do(func() {
+		srv.preShutdownErr = srv.runPreShutdown(ctx)
+	})
+
 	if atomic.AddUint32(&srv.inShutdown, 1) > 1 {
 		// shutdown in progress
 		return http.ErrServerClosed
 	}
 
+	if err := srv.closeAndDrain(ctx, progress); err != nil {
+		return err
+	}
+	return srv.preShutdownErr
+}
+
+// closeAndDrain closes the listener and waits for the requests
+// in progress to complete up to the shutdown timeout.
+func (srv *Server) closeAndDrain(ctx context.Context, progress func(remaining int)) error {
 	// Close underneath HTTP listener.
 	srv.listenerMutex.Lock()
 	err := srv.listener.Close()
@@ -139,6 +421,9 @@ func (srv *Server) Shutdown() error {
 		return err
 	}
 
+	// Close long-lived requests right away instead of waiting for them.
+	srv.cancelLongLived()
+
 	// Wait for opened connection to be closed up to Shutdown timeout.
 	shutdownTimeout := srv.ShutdownTimeout
 	shutdownTimer := time.NewTimer(shutdownTimeout)
@@ -156,11 +441,166 @@ func (srv *Server) Shutdown() error {
 			}
 			return errors.New("timed out. some connections are still active")
 		case <-ticker.C:
-			if atomic.LoadInt32(&srv.requestCount) <= 0 {
+			remaining := atomic.LoadInt32(&srv.requestCount)
+			if remaining <= 0 {
 				return nil
 			}
+			if progress != nil {
+				progress(int(remaining))
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// PreShutdownError - errors returned by the pre-shutdown hooks,
+// in their registration order.
+type PreShutdownError []error
+
+func (e PreShutdownError) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return "pre-shutdown hooks failed: " + strings.Join(msgs, "; ")
+}
+
+// OnPreShutdown registers a hook run at the start of Shutdown, before the
+// listener is closed, while requests are still served, e.g. to flush caches
+// or announce the departure of the node. Hooks run in registration order
+// and share a deadline of the shutdown timeout, the hooks not run by then
+// are skipped. Their errors are returned by Shutdown as a PreShutdownError
+// unless it fails otherwise, the server is shut down regardless.
+func (srv *Server) OnPreShutdown(hook func(ctx context.Context) error) *Server {
+	srv.preShutdownMutex.Lock()
+	srv.preShutdown = append(srv.preShutdown, hook)
+	srv.preShutdownMutex.Unlock()
+	return srv
+}
+
+// runPreShutdown runs the pre-shutdown hooks.
+func (srv *Server) runPreShutdown(ctx context.Context) error {
+	srv.preShutdownMutex.Lock()
+	hooks := srv.preShutdown
+	srv.preShutdownMutex.Unlock()
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	if srv.ShutdownTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, srv.ShutdownTimeout)
+		defer cancel()
+	}
+	var errs PreShutdownError
+	for _, hook := range hooks {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		if err := hook(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// ListenerFiles - returns duplicates of the listening sockets of the
+// server for a zero-downtime upgrade: they are passed to the new binary
+// as extra files, which serves on them using the InheritedFDAddrs, while
+// this server is shut down. Shutting it down, which closes its own
+// sockets, leaves the duplicates open, the caller must close them once
+// handed off.
+func (srv *Server) ListenerFiles() ([]*os.File, error) {
+	srv.listenerMutex.Lock()
+	defer srv.listenerMutex.Unlock()
+	if srv.listener == nil {
+		return nil, errors.New("server is not listening")
+	}
+	return srv.listener.files()
+}
+
+// LoggerTarget is flushed by ShutdownWithLoggers, any logger target satisfies it.
+type LoggerTarget interface {
+	Cancel()
+}
+
+// ShutdownWithLoggers - shuts down HTTP server as Shutdown does, then flushes
+// and cancels the logger targets, so that the entries of the last requests
+// served, e.g. their audit records, are sent before the connections are
+// closed. Targets are flushed even if in-flight requests did not complete
+// in time, flushTimeout bounds the wait for them once Shutdown returned.
+func (srv *Server) ShutdownWithLoggers(flushTimeout time.Duration, targets ...LoggerTarget) error {
+	err := srv.Shutdown()
+
+	// The flush gets its own budget, Shutdown may have used up
+	// the whole ShutdownTimeout waiting for in-flight requests.
+	ctx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var wg sync.WaitGroup
+		for _, target := range targets {
+			wg.Add(1)
+			go func(target LoggerTarget) {
+				defer wg.Done()
+				target.Cancel()
+			}(target)
+		}
+		wg.Wait()
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		if err == nil {
+			err = errors.New("timed out. some logger targets are still flushing")
 		}
 	}
+
+	if cerr := srv.Server.Close(); err == nil && cerr != nil {
+		err = cerr
+	}
+	return err
+}
+
+// trackLongLived registers the cancel function of a long-lived request,
+// which is canceled right away if the server is in shutdown.
+func (srv *Server) trackLongLived(r *http.Request, cancel context.CancelFunc) {
+	srv.longLivedMutex.Lock()
+	defer srv.longLivedMutex.Unlock()
+	if atomic.LoadUint32(&srv.inShutdown) != 0 {
+		cancel()
+		return
+	}
+	if srv.longLivedCancel == nil {
+		srv.longLivedCancel = make(map[*http.Request]context.CancelFunc)
+	}
+	srv.longLivedCancel[r] = cancel
+}
+
+func (srv *Server) untrackLongLived(r *http.Request) {
+	srv.longLivedMutex.Lock()
+	defer srv.longLivedMutex.Unlock()
+	if cancel, ok := srv.longLivedCancel[r]; ok {
+		cancel()
+		delete(srv.longLivedCancel, r)
+	}
+}
+
+// cancelLongLived cancels all in progress long-lived requests.
+func (srv *Server) cancelLongLived() {
+	srv.longLivedMutex.Lock()
+	defer srv.longLivedMutex.Unlock()
+	for r, cancel := range srv.longLivedCancel {
+		cancel()
+		delete(srv.longLivedCancel, r)
+	}
 }
 
 // UseShutdownTimeout configure server shutdown timeout
@@ -169,12 +609,101 @@ func (srv *Server) UseShutdownTimeout(d time.Duration) *Server {
 	return srv
 }
 
+// UseTCPOptions configure the TCP socket options for this HTTP *Server
+func (srv *Server) UseTCPOptions(opts TCPOptions) *Server {
+	srv.TCPOptions = opts
+	return srv
+}
+
+// UseLongLivedRequests configure a predicate identifying long-lived
+// requests such as SSE or WebSocket connections, their context is
+// canceled on shutdown instead of being waited for, handlers must
+// return once it is done.
+func (srv *Server) UseLongLivedRequests(fn func(r *http.Request) bool) *Server {
+	srv.longLived = fn
+	return srv
+}
+
+// UseMaxBodySize sets the maximum size of request bodies unless exempt returns
+// true for their request, e.g. for uploads. Requests with a larger Content-Length
+// are rejected with 413 (request entity too large), reading a body of unknown
+// length past the limit fails instead. Bodies are not limited by default.
+func (srv *Server) UseMaxBodySize(size int64, exempt func(r *http.Request) bool) *Server {
+	srv.maxBodySize = size
+	srv.maxBodyExempt = exempt
+	return srv
+}
+
+// UseMaxInFlight sets the maximum number of requests in progress, new
+// requests above it are rejected with 503 (service unavailable) unless
+// exempt returns true for them, e.g. for health checks. Long-lived
+// requests are not counted. Requests are not limited by default.
+func (srv *Server) UseMaxInFlight(n int, exempt func(r *http.Request) bool) *Server {
+	srv.maxInFlight = int32(n)
+	srv.maxInFlightExempt = exempt
+	return srv
+}
+
+// UseTLSObserver configure a function called with the state of each
+// TLS handshake, e.g. to report the negotiated versions and ciphers.
+// It is called once the handshake is verified, before it completes,
+// and must not block. Configs returned by GetConfigForClient are not
+// observed.
+func (srv *Server) UseTLSObserver(fn func(tls.ConnectionState)) *Server {
+	srv.tlsObserver = fn
+	return srv
+}
+
+// UseOnListen - calls fn with each address the server listens on once
+// Start bound all of them, before serving, e.g. to report which
+// interfaces are live. Addresses are resolved, e.g. the actual port
+// of ":0". Start fails without calling it if any address is not bound.
+func (srv *Server) UseOnListen(fn func(addr string)) *Server {
+	srv.onListen = fn
+	return srv
+}
+
+// UseConnCounters - counts the bytes read from and written to the
+// connections, reported by GetBytesIn and GetBytesOut. Connections
+// are left unwrapped otherwise, saving an atomic add per read and
+// write.
+func (srv *Server) UseConnCounters() *Server {
+	srv.counters = &connCounters{}
+	return srv
+}
+
 // UseHandler configure final handler for this HTTP *Server
 func (srv *Server) UseHandler(h http.Handler) *Server {
 	srv.Handler = h
 	return srv
 }
 
+// UseAddrTLSConfig sets the TLS config of the connections accepted on
+// addr, one of the Addrs, instead of the TLSConfig of the server, e.g.
+// to serve plaintext on an internal address and TLS on an external one.
+// A nil cfg serves plaintext. ReloadTLSConfig only swaps the TLSConfig
+// of the server, not the ones set here.
+func (srv *Server) UseAddrTLSConfig(addr string, cfg *tls.Config) *Server {
+	if srv.addrTLS == nil {
+		srv.addrTLS = make(map[string]*tls.Config)
+	}
+	srv.addrTLS[addr] = cfg
+	return srv
+}
+
+// UseHTTPSRedirect serves the connections accepted on addr, one of the
+// Addrs, in plaintext and redirects their requests with a 301 to the
+// same URL on httpsAddr, e.g. ":443" keeping the host of the requests.
+// Clients starting a TLS handshake on addr are served over TLS with the
+// config of httpsAddr, or the TLSConfig of the server, if any.
+func (srv *Server) UseHTTPSRedirect(addr, httpsAddr string) *Server {
+	if srv.httpsRedirect == nil {
+		srv.httpsRedirect = make(map[string]string)
+	}
+	srv.httpsRedirect[addr] = httpsAddr
+	return srv
+}
+
 // UseTLSConfig pass configured TLSConfig for this HTTP *Server
 func (srv *Server) UseTLSConfig(cfg *tls.Config) *Server {
 	srv.TLSConfig = cfg
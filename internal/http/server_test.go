@@ -18,11 +18,30 @@
 package http
 
 import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net"
 	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/minio/pkg/certs"
 )
@@ -88,3 +107,930 @@ func TestNewServer(t *testing.T) {
 		}
 	}
 }
+
+// startTestServer starts the server on a local port and returns its
+// address once it accepts connections and its listener is set, for
+// Shutdown not to return early.
+func startTestServer(t *testing.T, server *Server) string {
+	t.Helper()
+	addr := "127.0.0.1:" + getNextPort()
+	server.Addrs = []string{addr}
+	go server.Start(context.Background())
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		server.listenerMutex.Lock()
+		listening := server.listener != nil
+		server.listenerMutex.Unlock()
+		if listening {
+			if conn, err := net.Dial("tcp", addr); err == nil {
+				conn.Close()
+				return addr
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("server did not start listening on %s", addr)
+	return ""
+}
+
+type testAccessLogTarget struct {
+	sync.Mutex
+	entries []AccessLogEntry
+}
+
+func (l *testAccessLogTarget) Send(entry interface{}, _ string) error {
+	l.Lock()
+	defer l.Unlock()
+	l.entries = append(l.entries, entry.(AccessLogEntry))
+	return nil
+}
+
+func TestServerAccessLog(t *testing.T) {
+	target := &testAccessLogTarget{}
+	server := NewServer(nil).
+		UseHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, "Hello, world")
+		})).
+		UseShutdownTimeout(time.Second).
+		UseAccessLog(target, AccessLogMethod, AccessLogStatus, AccessLogBytes)
+	addr := startTestServer(t, server)
+	defer server.Shutdown()
+
+	resp, err := http.Post("http://"+addr+"/bucket/object", "text/plain", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	target.Lock()
+	defer target.Unlock()
+	if len(target.entries) != 1 {
+		t.Fatalf("expected 1 access log entry, got %d", len(target.entries))
+	}
+	entry := target.entries[0]
+	if entry.Method != http.MethodPost || entry.Status != http.StatusCreated || entry.Bytes != int64(len("Hello, world")) {
+		t.Fatalf("unexpected access log entry %#v", entry)
+	}
+	if entry.Path != "" || entry.Duration != 0 {
+		t.Fatalf("expected unselected fields to be empty, got %#v", entry)
+	}
+}
+
+func TestServerShutdownLongLived(t *testing.T) {
+	started := make(chan struct{})
+	done := make(chan struct{})
+	server := NewServer(nil).
+		UseHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush()
+			close(started)
+			<-r.Context().Done()
+			close(done)
+		})).
+		UseShutdownTimeout(10 * time.Second).
+		UseLongLivedRequests(func(r *http.Request) bool {
+			return r.URL.Path == "/events"
+		})
+	addr := startTestServer(t, server)
+
+	resp, err := http.Get("http://" + addr + "/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	<-started
+
+	start := time.Now()
+	if err = server.Shutdown(); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("shutdown waited on long-lived request for %s", elapsed)
+	}
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("long-lived request was not canceled")
+	}
+}
+
+func TestServerShutdownRejectedCount(t *testing.T) {
+	server := NewServer(nil).
+		UseHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})).
+		UseShutdownTimeout(3 * time.Second)
+	accepted := make(chan struct{}, 1)
+	server.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			select {
+			case accepted <- struct{}{}:
+			default:
+			}
+		}
+	}
+	addr := startTestServer(t, server)
+	<-accepted
+
+	// Connection established before shutdown, still served afterwards.
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	<-accepted
+
+	if err = server.Shutdown(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = conn.Write([]byte("GET / HTTP/1.1\r\nHost: " + addr + "\r\n\r\n")); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected status %d, got %d", http.StatusServiceUnavailable, resp.StatusCode)
+	}
+	if retryAfter := resp.Header.Get(RetryAfter); retryAfter != "3" {
+		t.Fatalf("expected Retry-After 3, got %q", retryAfter)
+	}
+	if count := server.GetShutdownRejectedCount(); count != 1 {
+		t.Fatalf("expected 1 rejected request, got %d", count)
+	}
+}
+
+// testLoggerTarget records the entries sent to it until canceled.
+type testLoggerTarget struct {
+	sync.Mutex
+	entries  int
+	canceled bool
+	delay    time.Duration // time taken to flush on Cancel.
+}
+
+func (l *testLoggerTarget) Send(entry interface{}, _ string) error {
+	l.Lock()
+	defer l.Unlock()
+	if !l.canceled {
+		l.entries++
+	}
+	return nil
+}
+
+func (l *testLoggerTarget) Cancel() {
+	time.Sleep(l.delay)
+	l.Lock()
+	defer l.Unlock()
+	l.canceled = true
+}
+
+func TestServerShutdownWithProgress(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	server := NewServer(nil).
+		UseHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+			w.WriteHeader(http.StatusOK)
+		})).
+		UseShutdownTimeout(10 * time.Second)
+	addr := startTestServer(t, server)
+
+	go func() {
+		resp, err := http.Get("http://" + addr + "/")
+		if err == nil {
+			resp.Body.Close()
+		}
+	}()
+	<-started
+
+	progressCh := make(chan int, 100)
+	shutdownCh := make(chan error, 1)
+	go func() {
+		shutdownCh <- server.ShutdownWithProgress(context.Background(), func(remaining int) {
+			progressCh <- remaining
+		})
+	}()
+	if remaining := <-progressCh; remaining != 1 {
+		t.Fatalf("expected 1 request in progress, got %d", remaining)
+	}
+	close(release)
+	if err := <-shutdownCh; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestServerShutdownWithLoggers(t *testing.T) {
+	target := &testLoggerTarget{delay: 50 * time.Millisecond}
+	started := make(chan struct{})
+	release := make(chan struct{})
+	server := NewServer(nil).
+		UseHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+			target.Send("served", "")
+			w.WriteHeader(http.StatusOK)
+		})).
+		UseShutdownTimeout(10 * time.Second)
+	addr := startTestServer(t, server)
+
+	respCh := make(chan error, 1)
+	go func() {
+		resp, err := http.Get("http://" + addr + "/")
+		if err == nil {
+			resp.Body.Close()
+		}
+		respCh <- err
+	}()
+	<-started
+
+	shutdownCh := make(chan error, 1)
+	go func() {
+		// Shorter than the in-flight request, the flush is timed
+		// from the end of Shutdown.
+		shutdownCh <- server.ShutdownWithLoggers(200*time.Millisecond, target)
+	}()
+	time.Sleep(300 * time.Millisecond)
+	target.Lock()
+	canceled := target.canceled
+	target.Unlock()
+	if canceled {
+		t.Fatal("expected the target to be canceled after in-flight requests")
+	}
+
+	close(release)
+	if err := <-respCh; err != nil {
+		t.Fatal(err)
+	}
+	if err := <-shutdownCh; err != nil {
+		t.Fatal(err)
+	}
+	target.Lock()
+	defer target.Unlock()
+	if !target.canceled || target.entries != 1 {
+		t.Fatalf("expected the entry of the in-flight request before canceling, got %d entries, canceled %v",
+			target.entries, target.canceled)
+	}
+}
+
+func TestServerTLSObserver(t *testing.T) {
+	cert, err := getTLSCert()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		mu     sync.Mutex
+		states []tls.ConnectionState
+	)
+	server := NewServer(nil).UseHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})).UseTLSConfig(&tls.Config{
+		Certificates: []tls.Certificate{cert},
+	}).UseTLSObserver(func(cs tls.ConnectionState) {
+		mu.Lock()
+		states = append(states, cs)
+		mu.Unlock()
+	})
+	addr := startTestServer(t, server)
+	defer server.Shutdown()
+
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: true,
+			MaxVersion:         tls.VersionTLS12,
+			CipherSuites:       []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256},
+		},
+	}}
+	resp, err := client.Get("https://" + addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(states) != 1 {
+		t.Fatalf("expected 1 observed handshake, got %d", len(states))
+	}
+	if states[0].Version != tls.VersionTLS12 || states[0].CipherSuite != tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256 {
+		t.Fatalf("unexpected negotiated version %x and cipher %s", states[0].Version, tls.CipherSuiteName(states[0].CipherSuite))
+	}
+}
+
+func TestServerMaxBodySize(t *testing.T) {
+	server := NewServer(nil).
+		UseHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, err := io.Copy(ioutil.Discard, r.Body); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		})).
+		UseShutdownTimeout(time.Second).
+		UseMaxBodySize(10, func(r *http.Request) bool {
+			return r.URL.Path == "/upload"
+		})
+	addr := startTestServer(t, server)
+	defer server.Shutdown()
+
+	testCases := []struct {
+		path     string
+		body     io.Reader
+		expected int
+	}{
+		{"/", strings.NewReader("small"), http.StatusOK},
+		{"/", strings.NewReader(strings.Repeat("x", 11)), http.StatusRequestEntityTooLarge},
+		// Body of unknown length, reads past the limit fail.
+		{"/", ioutil.NopCloser(strings.NewReader(strings.Repeat("x", 11))), http.StatusBadRequest},
+		{"/upload", strings.NewReader(strings.Repeat("x", 11)), http.StatusOK},
+	}
+	for i, testCase := range testCases {
+		resp, err := http.Post("http://"+addr+testCase.path, "text/plain", testCase.body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != testCase.expected {
+			t.Errorf("Test %d: expected status %d, got %d", i+1, testCase.expected, resp.StatusCode)
+		}
+	}
+}
+
+func TestServerMaxInFlight(t *testing.T) {
+	release := make(chan struct{})
+	server := NewServer(nil).
+		UseHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/slow" {
+				<-release
+			}
+			w.WriteHeader(http.StatusOK)
+		})).
+		UseShutdownTimeout(time.Second).
+		UseMaxInFlight(2, func(r *http.Request) bool {
+			return r.URL.Path == "/health"
+		})
+	addr := startTestServer(t, server)
+	defer server.Shutdown()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := http.Get("http://" + addr + "/slow")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	for server.GetRequestCount() < 2 {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	get := func(path string) *http.Response {
+		t.Helper()
+		resp, err := http.Get("http://" + addr + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+	if resp := get("/"); resp.StatusCode != http.StatusServiceUnavailable || resp.Header.Get(RetryAfter) != "1" {
+		t.Fatalf("expected the request to be shed, got %d", resp.StatusCode)
+	}
+	if resp := get("/health"); resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected exempted request to be served, got %d", resp.StatusCode)
+	}
+
+	close(release)
+	wg.Wait()
+	if resp := get("/"); resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected request to be served once below the limit, got %d", resp.StatusCode)
+	}
+	if n := server.GetShedCount(); n != 1 {
+		t.Fatalf("expected 1 shed request, got %d", n)
+	}
+}
+
+func TestServerLameDuck(t *testing.T) {
+	server := NewServer(nil).UseHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	addr := startTestServer(t, server)
+	defer server.Shutdown()
+
+	if server.IsLameDuck() {
+		t.Fatal("expected server not to start in lame duck mode")
+	}
+	server.SetLameDuck(true)
+	if !server.IsLameDuck() {
+		t.Fatal("expected server to be in lame duck mode")
+	}
+	// Requests are still served normally.
+	resp, err := http.Get("http://" + addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected %d in lame duck mode, got %d", http.StatusOK, resp.StatusCode)
+	}
+	server.SetLameDuck(false)
+	if server.IsLameDuck() {
+		t.Fatal("expected server to leave lame duck mode")
+	}
+}
+
+func TestServerByteCounters(t *testing.T) {
+	server := NewServer(nil).UseConnCounters().UseHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello"))
+	}))
+	addr := startTestServer(t, server)
+	defer server.Shutdown()
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	request := "GET / HTTP/1.1\r\nHost: " + addr + "\r\nConnection: close\r\n\r\n"
+	if _, err = conn.Write([]byte(request)); err != nil {
+		t.Fatal(err)
+	}
+	// The server closes the connection once the response is written.
+	response, err := ioutil.ReadAll(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Counters are updated right after the server reads or writes.
+	deadline := time.Now().Add(5 * time.Second)
+	for server.GetBytesOut() != uint64(len(response)) && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if in := server.GetBytesIn(); in != uint64(len(request)) {
+		t.Fatalf("expected %d bytes in, got %d", len(request), in)
+	}
+	if out := server.GetBytesOut(); out != uint64(len(response)) {
+		t.Fatalf("expected %d bytes out, got %d", len(response), out)
+	}
+
+	// Connections are not wrapped unless counted.
+	conns := make(chan net.Conn, 1)
+	uncounted := NewServer(nil).UseHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	uncounted.ConnContext = func(ctx context.Context, c net.Conn) context.Context {
+		select {
+		case conns <- c:
+		default:
+		}
+		return ctx
+	}
+	addr = startTestServer(t, uncounted)
+	defer uncounted.Shutdown()
+	resp, err := http.Get("http://" + addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if c := <-conns; reflect.TypeOf(c) != reflect.TypeOf(&net.TCPConn{}) {
+		t.Fatalf("expected a plain TCP connection, got %T", c)
+	}
+	if uncounted.GetBytesIn() != 0 || uncounted.GetBytesOut() != 0 {
+		t.Fatal("expected no bytes counted")
+	}
+}
+
+// generateTLSCert returns a self-signed certificate for 127.0.0.1.
+func generateTLSCert(t *testing.T) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func TestServerReloadTLSConfig(t *testing.T) {
+	oldCert, newCert := generateTLSCert(t), generateTLSCert(t)
+	server := NewServer(nil).UseHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})).UseTLSConfig(&tls.Config{
+		Certificates: []tls.Certificate{oldCert},
+	})
+	if err := server.ReloadTLSConfig(&tls.Config{}); err == nil {
+		t.Fatal("expected reload to fail before the server is started")
+	}
+	addr := startTestServer(t, server)
+	defer server.Shutdown()
+
+	peerCert := func(conn *tls.Conn) []byte {
+		t.Helper()
+		if err := conn.Handshake(); err != nil {
+			t.Fatal(err)
+		}
+		return conn.ConnectionState().PeerCertificates[0].Raw
+	}
+
+	oldConn, err := tls.Dial("tcp", addr, &tls.Config{InsecureSkipVerify: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer oldConn.Close()
+	if !bytes.Equal(peerCert(oldConn), oldCert.Certificate[0]) {
+		t.Fatal("expected the initial certificate")
+	}
+
+	if err = server.ReloadTLSConfig(&tls.Config{Certificates: []tls.Certificate{newCert}}); err != nil {
+		t.Fatal(err)
+	}
+
+	newConn, err := tls.Dial("tcp", addr, &tls.Config{InsecureSkipVerify: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer newConn.Close()
+	if !bytes.Equal(peerCert(newConn), newCert.Certificate[0]) {
+		t.Fatal("expected the reloaded certificate for a new connection")
+	}
+
+	// The connection established before the reload is still served.
+	if _, err = oldConn.Write([]byte("GET / HTTP/1.1\r\nHost: " + addr + "\r\n\r\n")); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(oldConn), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+}
+
+func TestServerOnPreShutdown(t *testing.T) {
+	server := NewServer(nil).
+		UseHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})).
+		UseShutdownTimeout(3 * time.Second)
+	addr := startTestServer(t, server)
+
+	var order []int
+	errFlush := errors.New("flush failed")
+	server.OnPreShutdown(func(ctx context.Context) error {
+		order = append(order, 1)
+		// Requests are still served.
+		resp, err := http.Get("http://" + addr)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		return nil
+	}).OnPreShutdown(func(ctx context.Context) error {
+		order = append(order, 2)
+		return errFlush
+	})
+
+	err := server.Shutdown()
+	var hookErrs PreShutdownError
+	if !errors.As(err, &hookErrs) {
+		t.Fatalf("expected the hook errors to be returned, got %v", err)
+	}
+	if len(hookErrs) != 1 || hookErrs[0] != errFlush {
+		t.Fatalf("expected %v, got %v", errFlush, hookErrs)
+	}
+	if !reflect.DeepEqual(order, []int{1, 2}) {
+		t.Fatalf("expected the hooks to run in registration order, got %v", order)
+	}
+	if err = server.Shutdown(); err != http.ErrServerClosed {
+		t.Fatalf("expected %v, got %v", http.ErrServerClosed, err)
+	}
+}
+
+func TestServerConcurrentShutdown(t *testing.T) {
+	server := NewServer(nil).
+		UseHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})).
+		UseShutdownTimeout(3 * time.Second)
+	addr := startTestServer(t, server)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	server.OnPreShutdown(func(ctx context.Context) error {
+		close(started)
+		<-release
+		return nil
+	})
+
+	first := make(chan error, 1)
+	go func() { first <- server.Shutdown() }()
+	<-started
+	second := make(chan error, 1)
+	go func() { second <- server.Shutdown() }()
+
+	// The second caller waits for the hooks of the first one
+	// rather than closing the listener under them.
+	time.Sleep(100 * time.Millisecond)
+	select {
+	case err := <-second:
+		t.Fatalf("expected the second shutdown to wait for the hooks, got %v", err)
+	default:
+	}
+	resp, err := http.Get("http://" + addr)
+	if err != nil {
+		t.Fatalf("expected requests to be served while the hooks run, got %v", err)
+	}
+	resp.Body.Close()
+
+	close(release)
+	if err = <-first; err != nil {
+		t.Fatal(err)
+	}
+	if err = <-second; err != http.ErrServerClosed {
+		t.Fatalf("expected %v, got %v", http.ErrServerClosed, err)
+	}
+}
+
+func TestServerRequestDecompression(t *testing.T) {
+	server := NewServer(nil).
+		UseHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := ioutil.ReadAll(r.Body)
+			if errors.Is(err, ErrDecompressedTooLarge) {
+				w.WriteHeader(http.StatusRequestEntityTooLarge)
+				return
+			}
+			w.Header().Set("X-Content-Encoding", r.Header.Get("Content-Encoding"))
+			w.Write(body)
+		})).
+		UseRequestDecompression(1024)
+	addr := startTestServer(t, server)
+	defer server.Shutdown()
+
+	compress := func(newWriter func(io.Writer) io.WriteCloser, data []byte) []byte {
+		var buf bytes.Buffer
+		w := newWriter(&buf)
+		w.Write(data)
+		w.Close()
+		return buf.Bytes()
+	}
+	gzipWriter := func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) }
+	zlibWriter := func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) }
+
+	entry := []byte(`{"message":"entry"}`)
+	for _, c := range []struct {
+		encoding     string
+		body         []byte
+		status       int
+		expectedBody []byte
+	}{
+		{"gzip", compress(gzipWriter, entry), http.StatusOK, entry},
+		{"deflate", compress(zlibWriter, entry), http.StatusOK, entry},
+		{"", entry, http.StatusOK, entry},
+		// Unknown encodings are passed as is.
+		{"br", entry, http.StatusOK, entry},
+		{"gzip", entry, http.StatusBadRequest, nil},
+		{"gzip", compress(gzipWriter, make([]byte, 1<<20)), http.StatusRequestEntityTooLarge, nil},
+	} {
+		req, err := http.NewRequest(http.MethodPost, "http://"+addr, bytes.NewReader(c.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.encoding != "" {
+			req.Header.Set("Content-Encoding", c.encoding)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != c.status {
+			t.Fatalf("%s: expected status %d, got %d", c.encoding, c.status, resp.StatusCode)
+		}
+		if c.status != http.StatusOK {
+			continue
+		}
+		if !bytes.Equal(body, c.expectedBody) {
+			t.Fatalf("%s: expected %q, got %q", c.encoding, c.expectedBody, body)
+		}
+		if c.encoding == "br" && resp.Header.Get("X-Content-Encoding") != "br" {
+			t.Fatal("expected the unknown encoding to be kept")
+		}
+	}
+}
+
+func TestServerOnListen(t *testing.T) {
+	addrCh := make(chan string, 2)
+	server := NewServer([]string{"127.0.0.1:0", "127.0.0.1:0"}).
+		UseHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).
+		UseOnListen(func(addr string) { addrCh <- addr })
+	go server.Start(context.Background())
+	defer server.Shutdown()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case addr := <-addrCh:
+			// Listening once reported.
+			resp, err := http.Get("http://" + addr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for the listening addresses")
+		}
+	}
+
+	// Nothing is reported unless every address is bound.
+	server = NewServer([]string{"127.0.0.1:0", "256.0.0.1:0"}).
+		UseOnListen(func(addr string) { t.Errorf("unexpected listening address %s", addr) })
+	if err := server.Start(context.Background()); err == nil {
+		t.Fatal("expected an error listening on an invalid address")
+	}
+}
+
+func TestServerAddrTLSConfig(t *testing.T) {
+	cert, err := getTLSCert()
+	if err != nil {
+		t.Fatal(err)
+	}
+	plainAddr := "127.0.0.1:" + getNextPort()
+	tlsAddr := "127.0.0.1:" + getNextPort()
+	listening := make(chan string, 2)
+	server := NewServer([]string{plainAddr, tlsAddr}).
+		UseConnCounters().
+		UseHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.TLS != nil {
+				w.Write([]byte("tls"))
+			} else {
+				w.Write([]byte("plain"))
+			}
+		})).
+		UseTLSConfig(&tls.Config{Certificates: []tls.Certificate{cert}}).
+		UseAddrTLSConfig(plainAddr, nil).
+		UseOnListen(func(addr string) { listening <- addr })
+	go server.Start(context.Background())
+	defer server.Shutdown()
+	for i := 0; i < 2; i++ {
+		select {
+		case <-listening:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for the server to listen")
+		}
+	}
+
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}}
+	testCases := []struct {
+		url      string
+		expected string
+	}{
+		{"http://" + plainAddr, "plain"},
+		{"https://" + tlsAddr, "tls"},
+	}
+	for _, testCase := range testCases {
+		resp, err := client.Get(testCase.url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(body) != testCase.expected {
+			t.Fatalf("%s: expected %s, got %s", testCase.url, testCase.expected, body)
+		}
+	}
+	// Both are counted by the same server.
+	if server.GetBytesIn() == 0 || server.GetBytesOut() == 0 {
+		t.Fatal("expected the bytes of both listeners to be counted")
+	}
+
+	// Only server addresses can be configured.
+	server = NewServer([]string{"127.0.0.1:0"}).UseAddrTLSConfig("127.0.0.1:1", nil)
+	if err = server.Start(context.Background()); err == nil {
+		t.Fatal("expected an error for a tls config of an unknown address")
+	}
+}
+
+func TestHTTPSRedirectURL(t *testing.T) {
+	testCases := []struct {
+		httpsAddr string
+		host      string
+		expected  string
+	}{
+		{":9443", "example.com:9000", "https://example.com:9443/bucket/object?versionId=1"},
+		{":443", "example.com:9000", "https://example.com/bucket/object?versionId=1"},
+		{"secure.example.com:8443", "example.com:9000", "https://secure.example.com:8443/bucket/object?versionId=1"},
+		{":9443", "example.com", "https://example.com:9443/bucket/object?versionId=1"},
+		{":9443", "[::1]:9000", "https://[::1]:9443/bucket/object?versionId=1"},
+		{":443", "[::1]:9000", "https://[::1]/bucket/object?versionId=1"},
+	}
+	for i, testCase := range testCases {
+		r := httptest.NewRequest(http.MethodGet, "/bucket/object?versionId=1", nil)
+		r.Host = testCase.host
+		if u := httpsRedirectURL(r, testCase.httpsAddr); u != testCase.expected {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.expected, u)
+		}
+	}
+}
+
+func TestServerHTTPSRedirect(t *testing.T) {
+	cert, err := getTLSCert()
+	if err != nil {
+		t.Fatal(err)
+	}
+	plainAddr := "127.0.0.1:" + getNextPort()
+	tlsAddr := "127.0.0.1:" + getNextPort()
+	listening := make(chan string, 2)
+	server := NewServer([]string{plainAddr, tlsAddr}).
+		UseHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})).
+		UseTLSConfig(&tls.Config{Certificates: []tls.Certificate{cert}}).
+		UseHTTPSRedirect(plainAddr, tlsAddr).
+		UseOnListen(func(addr string) { listening <- addr })
+	go server.Start(context.Background())
+	defer server.Shutdown()
+	for i := 0; i < 2; i++ {
+		select {
+		case <-listening:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for the server to listen")
+		}
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	testCases := []struct {
+		url              string
+		expectedStatus   int
+		expectedLocation string
+	}{
+		{"http://" + plainAddr + "/bucket?list-type=2", http.StatusMovedPermanently, "https://" + tlsAddr + "/bucket?list-type=2"},
+		// A TLS client of the plaintext address is redirected as well.
+		{"https://" + plainAddr + "/bucket", http.StatusMovedPermanently, "https://" + tlsAddr + "/bucket"},
+		{"https://" + tlsAddr + "/bucket", http.StatusOK, ""},
+	}
+	for _, testCase := range testCases {
+		resp, err := client.Get(testCase.url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != testCase.expectedStatus || resp.Header.Get("Location") != testCase.expectedLocation {
+			t.Fatalf("%s: expected %d to %q, got %d to %q", testCase.url, testCase.expectedStatus,
+				testCase.expectedLocation, resp.StatusCode, resp.Header.Get("Location"))
+		}
+	}
+}
+
+func TestServerHTTPSRedirectNoTLS(t *testing.T) {
+	addr := "127.0.0.1:" + getNextPort()
+	listening := make(chan string, 1)
+	server := NewServer([]string{addr}).
+		UseHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).
+		UseHTTPSRedirect(addr, ":443").
+		UseOnListen(func(addr string) { listening <- addr })
+	go server.Start(context.Background())
+	defer server.Shutdown()
+	select {
+	case <-listening:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the server to listen")
+	}
+
+	// A TLS client is sent an alert rather than left hanging.
+	conn, err := tls.Dial("tcp", addr, &tls.Config{InsecureSkipVerify: true})
+	if err == nil {
+		conn.Close()
+		t.Fatal("expected the handshake to fail without a tls config")
+	}
+}
@@ -20,6 +20,7 @@ package logger
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -32,6 +33,79 @@ import (
 	"github.com/minio/minio/internal/logger/message/audit"
 )
 
+// EnvAuditFailClosed enables the fail-closed audit mode, requests are
+// rejected unless an audit target is ready before they are served. It
+// has no effect as long as no audit target is configured. It does not
+// guarantee delivery, the entry of a request is only sent once it is
+// served and may still be dropped, e.g. when the queue filled up.
+const EnvAuditFailClosed = "MINIO_AUDIT_FAIL_CLOSED"
+
+// ErrAuditUnavailable is returned in fail-closed audit mode
+// when no audit target is able to accept audit entries.
+var ErrAuditUnavailable = errors.New("no audit target is able to accept audit events")
+
+// EnvAuditTLSClientCert records the TLS client certificate of requests in audit entries
+const EnvAuditTLSClientCert = "MINIO_AUDIT_TLS_CLIENT_CERT"
+
+// auditFailClosed is set when requests must fail if they cannot be audited.
+var auditFailClosed int32
+
+// auditTLSClientCert is set when audit entries record the TLS client certificate.
+var auditTLSClientCert int32
+
+// EnableAuditTLSClientCert - records the subject and issuer of the certificate
+// clients authenticated the TLS connection of their requests with, if any, in
+// the audit entries of the requests.
+func EnableAuditTLSClientCert() {
+	atomic.StoreInt32(&auditTLSClientCert, 1)
+}
+
+// tlsClientCert - returns the TLS client certificate of r, nil if it has none
+// or audit entries do not record it.
+func tlsClientCert(r *http.Request) *audit.TLSClientCert {
+	if atomic.LoadInt32(&auditTLSClientCert) == 0 || r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return nil
+	}
+	cert := r.TLS.PeerCertificates[0]
+	return &audit.TLSClientCert{
+		Subject: cert.Subject.String(),
+		Issuer:  cert.Issuer.String(),
+	}
+}
+
+// EnableAuditFailClosed - enables the fail-closed audit mode, requests
+// must be rejected when no audit target is ready to accept an entry
+// before they are served, see EnvAuditFailClosed.
+func EnableAuditFailClosed() {
+	atomic.StoreInt32(&auditFailClosed, 1)
+}
+
+// IsAuditFailClosed - returns true if fail-closed audit mode is enabled
+func IsAuditFailClosed() bool {
+	return atomic.LoadInt32(&auditFailClosed) == 1
+}
+
+// CheckAuditAvailable - in fail-closed audit mode, returns ErrAuditUnavailable
+// unless at least one audit target is able to accept an audit entry, always
+// returns nil otherwise. Targets which cannot report whether they are ready
+// are assumed to be. Without any audit target there is nothing to fail for,
+// requests are served, rejecting all of them would take the server down.
+func CheckAuditAvailable() error {
+	if !IsAuditFailClosed() {
+		return nil
+	}
+	targets := AuditTargets()
+	if len(targets) == 0 {
+		return nil
+	}
+	for _, t := range targets {
+		if rt, ok := t.(interface{ Ready() bool }); !ok || rt.Ready() {
+			return nil
+		}
+	}
+	return ErrAuditUnavailable
+}
+
 // ResponseWriter - is a wrapper to trap the http response status code.
 type ResponseWriter struct {
 	http.ResponseWriter
@@ -222,6 +296,7 @@ func AuditLog(ctx context.Context, w http.ResponseWriter, r *http.Request, reqCl
 		entry.API.OutputBytes = outputBytes
 		entry.API.TimeToResponse = strconv.FormatInt(timeToResponse.Nanoseconds(), 10) + "ns"
 		entry.Tags = reqInfo.GetTagsMap()
+		entry.TLSClient = tlsClientCert(r)
 		// ttfb will be recorded only for GET requests, Ignore such cases where ttfb will be empty.
 		if timeToFirstByte != 0 {
 			entry.API.TimeToFirstByte = strconv.FormatInt(timeToFirstByte.Nanoseconds(), 10) + "ns"
@@ -236,7 +311,9 @@ func AuditLog(ctx context.Context, w http.ResponseWriter, r *http.Request, reqCl
 	// Send audit logs only to http targets.
 	for _, t := range AuditTargets() {
 		if err := t.Send(entry, string(All)); err != nil {
-			LogAlwaysIf(context.Background(), fmt.Errorf("event(%v) was not sent to Audit target (%v): %v", entry, t, err), All)
+			if ok, suppressed := targetSendFailures.allow(t, err, time.Now()); ok {
+				LogAlwaysIf(context.Background(), fmt.Errorf("event(%v) was not sent to Audit target (%v): %v%s", entry, t, err, suppressedSuffix(suppressed)), All)
+			}
 		}
 	}
 }
@@ -0,0 +1,117 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package logger
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/minio/minio/internal/logger/message/audit"
+	"github.com/minio/minio/internal/logger/target/types"
+)
+
+type testAuditTarget struct {
+	ready   bool
+	entries []interface{}
+}
+
+func (t *testAuditTarget) String() string         { return "test" }
+func (t *testAuditTarget) Endpoint() string       { return "" }
+func (t *testAuditTarget) Init() error            { return nil }
+func (t *testAuditTarget) Cancel()                {}
+func (t *testAuditTarget) Type() types.TargetType { return types.TargetHTTP }
+func (t *testAuditTarget) Ready() bool            { return t.ready }
+
+func (t *testAuditTarget) Send(entry interface{}, _ string) error {
+	t.entries = append(t.entries, entry)
+	return nil
+}
+
+func setTestAuditTargets(tgts ...Target) {
+	swapMu.Lock()
+	auditTargets = tgts
+	atomic.StoreInt32(&nAuditTargets, int32(len(tgts)))
+	swapMu.Unlock()
+}
+
+func TestCheckAuditAvailable(t *testing.T) {
+	defer setTestAuditTargets()
+	defer atomic.StoreInt32(&auditFailClosed, 0)
+
+	down, up := &testAuditTarget{}, &testAuditTarget{ready: true}
+
+	// Fail open by default.
+	setTestAuditTargets(down)
+	if err := CheckAuditAvailable(); err != nil {
+		t.Fatalf("expected audit to fail open, got %v", err)
+	}
+
+	EnableAuditFailClosed()
+	testCases := []struct {
+		targets  []Target
+		expected error
+	}{
+		// Nothing to audit to, requests are served.
+		{nil, nil},
+		{[]Target{down}, ErrAuditUnavailable},
+		{[]Target{down, up}, nil},
+	}
+	for i, testCase := range testCases {
+		setTestAuditTargets(testCase.targets...)
+		if err := CheckAuditAvailable(); err != testCase.expected {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, err)
+		}
+	}
+}
+
+func TestAuditLogTLSClientCert(t *testing.T) {
+	defer setTestAuditTargets()
+	defer atomic.StoreInt32(&auditTLSClientCert, 0)
+
+	tgt := &testAuditTarget{}
+	setTestAuditTargets(tgt)
+
+	r := httptest.NewRequest(http.MethodGet, "/bucket/object", nil)
+	r.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{
+		Subject: pkix.Name{CommonName: "app1", Organization: []string{"Example"}},
+		Issuer:  pkix.Name{CommonName: "Example CA"},
+	}}}
+	ctx := SetReqInfo(context.Background(), NewReqInfo("", "", "", "", "GetObject", "bucket", "object"))
+
+	// Left out by default.
+	AuditLog(ctx, httptest.NewRecorder(), r, nil)
+	EnableAuditTLSClientCert()
+	AuditLog(ctx, httptest.NewRecorder(), r, nil)
+
+	if len(tgt.entries) != 2 {
+		t.Fatalf("expected 2 audit entries, got %d", len(tgt.entries))
+	}
+	if cert := tgt.entries[0].(audit.Entry).TLSClient; cert != nil {
+		t.Fatalf("expected no client certificate by default, got %#v", cert)
+	}
+	expected := audit.TLSClientCert{Subject: "CN=app1,O=Example", Issuer: "CN=Example CA"}
+	if cert := tgt.entries[1].(audit.Entry).TLSClient; cert == nil || *cert != expected {
+		t.Fatalf("expected client certificate %#v, got %#v", expected, cert)
+	}
+}
@@ -18,17 +18,30 @@
 package logger
 
 import (
+	"context"
 	"crypto/tls"
 	"errors"
+	"net"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/minio/pkg/env"
 	xnet "github.com/minio/pkg/net"
+	"golang.org/x/net/http/httpguts"
 
 	"github.com/minio/minio/internal/config"
+	"github.com/minio/minio/internal/logger/message/cef"
+	"github.com/minio/minio/internal/logger/target/amqp"
+	"github.com/minio/minio/internal/logger/target/file"
+	"github.com/minio/minio/internal/logger/target/filter"
 	"github.com/minio/minio/internal/logger/target/http"
 	"github.com/minio/minio/internal/logger/target/kafka"
+	"github.com/minio/minio/internal/logger/target/loki"
+	"github.com/minio/minio/internal/logger/target/splunk"
+	"github.com/minio/minio/internal/logger/target/websocket"
 )
 
 // Console logger target
@@ -38,52 +51,156 @@ type Console struct {
 
 // Audit/Logger constants
 const (
-	Endpoint   = "endpoint"
-	AuthToken  = "auth_token"
-	ClientCert = "client_cert"
-	ClientKey  = "client_key"
-	QueueSize  = "queue_size"
-
-	KafkaBrokers       = "brokers"
-	KafkaTopic         = "topic"
-	KafkaTLS           = "tls"
-	KafkaTLSSkipVerify = "tls_skip_verify"
-	KafkaTLSClientAuth = "tls_client_auth"
-	KafkaSASL          = "sasl"
-	KafkaSASLUsername  = "sasl_username"
-	KafkaSASLPassword  = "sasl_password"
-	KafkaSASLMechanism = "sasl_mechanism"
-	KafkaClientTLSCert = "client_tls_cert"
-	KafkaClientTLSKey  = "client_tls_key"
-	KafkaVersion       = "version"
-
-	EnvLoggerWebhookEnable     = "MINIO_LOGGER_WEBHOOK_ENABLE"
-	EnvLoggerWebhookEndpoint   = "MINIO_LOGGER_WEBHOOK_ENDPOINT"
-	EnvLoggerWebhookAuthToken  = "MINIO_LOGGER_WEBHOOK_AUTH_TOKEN"
-	EnvLoggerWebhookClientCert = "MINIO_LOGGER_WEBHOOK_CLIENT_CERT"
-	EnvLoggerWebhookClientKey  = "MINIO_LOGGER_WEBHOOK_CLIENT_KEY"
-	EnvLoggerWebhookQueueSize  = "MINIO_LOGGER_WEBHOOK_QUEUE_SIZE"
-
-	EnvAuditWebhookEnable     = "MINIO_AUDIT_WEBHOOK_ENABLE"
-	EnvAuditWebhookEndpoint   = "MINIO_AUDIT_WEBHOOK_ENDPOINT"
-	EnvAuditWebhookAuthToken  = "MINIO_AUDIT_WEBHOOK_AUTH_TOKEN"
-	EnvAuditWebhookClientCert = "MINIO_AUDIT_WEBHOOK_CLIENT_CERT"
-	EnvAuditWebhookClientKey  = "MINIO_AUDIT_WEBHOOK_CLIENT_KEY"
-	EnvAuditWebhookQueueSize  = "MINIO_AUDIT_WEBHOOK_QUEUE_SIZE"
-
-	EnvKafkaEnable        = "MINIO_AUDIT_KAFKA_ENABLE"
-	EnvKafkaBrokers       = "MINIO_AUDIT_KAFKA_BROKERS"
-	EnvKafkaTopic         = "MINIO_AUDIT_KAFKA_TOPIC"
-	EnvKafkaTLS           = "MINIO_AUDIT_KAFKA_TLS"
-	EnvKafkaTLSSkipVerify = "MINIO_AUDIT_KAFKA_TLS_SKIP_VERIFY"
-	EnvKafkaTLSClientAuth = "MINIO_AUDIT_KAFKA_TLS_CLIENT_AUTH"
-	EnvKafkaSASLEnable    = "MINIO_AUDIT_KAFKA_SASL"
-	EnvKafkaSASLUsername  = "MINIO_AUDIT_KAFKA_SASL_USERNAME"
-	EnvKafkaSASLPassword  = "MINIO_AUDIT_KAFKA_SASL_PASSWORD"
-	EnvKafkaSASLMechanism = "MINIO_AUDIT_KAFKA_SASL_MECHANISM"
-	EnvKafkaClientTLSCert = "MINIO_AUDIT_KAFKA_CLIENT_TLS_CERT"
-	EnvKafkaClientTLSKey  = "MINIO_AUDIT_KAFKA_CLIENT_TLS_KEY"
-	EnvKafkaVersion       = "MINIO_AUDIT_KAFKA_VERSION"
+	Endpoint        = "endpoint"
+	AuthToken       = "auth_token"
+	ClientCert      = "client_cert"
+	ClientKey       = "client_key"
+	QueueSize       = "queue_size"
+	Filter          = "filter"
+	RequestIDHeader = "request_id_header"
+	Format          = "format"
+	CEFFields       = "cef_fields"
+	PriorityFilter  = "priority_filter"
+	APIFilter       = "api_filter"
+	APIFilterDrop   = "api_filter_drop_missing"
+	PinnedCerts     = "pinned_server_cert_sha256"
+	Ordering        = "ordering"
+	Backpressure    = "backpressure_header"
+
+	AllowInsecureAuth = "allow_insecure_auth"
+	FollowRedirects   = "follow_redirects"
+
+	KafkaBrokers                 = "brokers"
+	KafkaTopic                   = "topic"
+	KafkaTLS                     = "tls"
+	KafkaTLSSkipVerify           = "tls_skip_verify"
+	KafkaTLSClientAuth           = "tls_client_auth"
+	KafkaTLSCA                   = "tls_ca"
+	KafkaSASL                    = "sasl"
+	KafkaSASLUsername            = "sasl_username"
+	KafkaSASLPassword            = "sasl_password"
+	KafkaSASLMechanism           = "sasl_mechanism"
+	KafkaSASLKerberosServiceName = "sasl_kerberos_service_name"
+	KafkaSASLKerberosRealm       = "sasl_kerberos_realm"
+	KafkaSASLKerberosKeytab      = "sasl_kerberos_keytab"
+	KafkaSASLKerberosConfig      = "sasl_kerberos_config"
+	KafkaSASLOAuthTokenURL       = "sasl_oauth_token_url"
+	KafkaSASLOAuthClientID       = "sasl_oauth_client_id"
+	KafkaSASLOAuthClientSecret   = "sasl_oauth_client_secret"
+	KafkaSASLOAuthScopes         = "sasl_oauth_scopes"
+	KafkaClientTLSCert           = "client_tls_cert"
+	KafkaClientTLSKey            = "client_tls_key"
+	KafkaVersion                 = "version"
+	KafkaFallbackEndpoint        = "fallback_endpoint"
+
+	AMQPURL               = "url"
+	AMQPExchange          = "exchange"
+	AMQPExchangeType      = "exchange_type"
+	AMQPRoutingKey        = "routing_key"
+	AMQPDeliveryMode      = "delivery_mode"
+	AMQPPublisherConfirms = "publisher_confirms"
+	AMQPTLSSkipVerify     = "tls_skip_verify"
+	AMQPClientTLSCert     = "client_tls_cert"
+	AMQPClientTLSKey      = "client_tls_key"
+
+	EnvLoggerWebhookEnable          = "MINIO_LOGGER_WEBHOOK_ENABLE"
+	EnvLoggerWebhookEndpoint        = "MINIO_LOGGER_WEBHOOK_ENDPOINT"
+	EnvLoggerWebhookAuthToken       = "MINIO_LOGGER_WEBHOOK_AUTH_TOKEN"
+	EnvLoggerWebhookClientCert      = "MINIO_LOGGER_WEBHOOK_CLIENT_CERT"
+	EnvLoggerWebhookClientKey       = "MINIO_LOGGER_WEBHOOK_CLIENT_KEY"
+	EnvLoggerWebhookQueueSize       = "MINIO_LOGGER_WEBHOOK_QUEUE_SIZE"
+	EnvLoggerWebhookFilter          = "MINIO_LOGGER_WEBHOOK_FILTER"
+	EnvLoggerWebhookRequestIDHeader = "MINIO_LOGGER_WEBHOOK_REQUEST_ID_HEADER"
+
+	EnvLoggerWebhookAllowInsecureAuth = "MINIO_LOGGER_WEBHOOK_ALLOW_INSECURE_AUTH"
+	EnvLoggerWebhookFollowRedirects   = "MINIO_LOGGER_WEBHOOK_FOLLOW_REDIRECTS"
+
+	EnvAuditWebhookEnable          = "MINIO_AUDIT_WEBHOOK_ENABLE"
+	EnvAuditWebhookEndpoint        = "MINIO_AUDIT_WEBHOOK_ENDPOINT"
+	EnvAuditWebhookAuthToken       = "MINIO_AUDIT_WEBHOOK_AUTH_TOKEN"
+	EnvAuditWebhookClientCert      = "MINIO_AUDIT_WEBHOOK_CLIENT_CERT"
+	EnvAuditWebhookClientKey       = "MINIO_AUDIT_WEBHOOK_CLIENT_KEY"
+	EnvAuditWebhookQueueSize       = "MINIO_AUDIT_WEBHOOK_QUEUE_SIZE"
+	EnvAuditWebhookFilter          = "MINIO_AUDIT_WEBHOOK_FILTER"
+	EnvAuditWebhookRequestIDHeader = "MINIO_AUDIT_WEBHOOK_REQUEST_ID_HEADER"
+	EnvAuditWebhookFormat          = "MINIO_AUDIT_WEBHOOK_FORMAT"
+	EnvAuditWebhookCEFFields       = "MINIO_AUDIT_WEBHOOK_CEF_FIELDS"
+	EnvAuditWebhookPriorityFilter  = "MINIO_AUDIT_WEBHOOK_PRIORITY_FILTER"
+	EnvAuditWebhookAPIFilter       = "MINIO_AUDIT_WEBHOOK_API_FILTER"
+	EnvAuditWebhookAPIFilterDrop   = "MINIO_AUDIT_WEBHOOK_API_FILTER_DROP_MISSING"
+	EnvAuditWebhookPinnedCerts     = "MINIO_AUDIT_WEBHOOK_PINNED_SERVER_CERT_SHA256"
+	EnvAuditWebhookOrdering        = "MINIO_AUDIT_WEBHOOK_ORDERING"
+	EnvAuditWebhookBackpressure    = "MINIO_AUDIT_WEBHOOK_BACKPRESSURE_HEADER"
+
+	EnvAuditWebhookAllowInsecureAuth = "MINIO_AUDIT_WEBHOOK_ALLOW_INSECURE_AUTH"
+	EnvAuditWebhookFollowRedirects   = "MINIO_AUDIT_WEBHOOK_FOLLOW_REDIRECTS"
+
+	EnvLoggerFileEnable       = "MINIO_LOGGER_FILE_ENABLE"
+	EnvLoggerFilePath         = "MINIO_LOGGER_FILE_PATH"
+	EnvLoggerFileMaxSize      = "MINIO_LOGGER_FILE_MAX_SIZE"
+	EnvLoggerFileMaxFiles     = "MINIO_LOGGER_FILE_MAX_FILES"
+	EnvLoggerFileSyncInterval = "MINIO_LOGGER_FILE_SYNC_INTERVAL"
+
+	EnvLoggerLokiEnable        = "MINIO_LOGGER_LOKI_ENABLE"
+	EnvLoggerLokiEndpoint      = "MINIO_LOGGER_LOKI_ENDPOINT"
+	EnvLoggerLokiLabels        = "MINIO_LOGGER_LOKI_LABELS"
+	EnvLoggerLokiUsername      = "MINIO_LOGGER_LOKI_USERNAME"
+	EnvLoggerLokiPassword      = "MINIO_LOGGER_LOKI_PASSWORD"
+	EnvLoggerLokiBearerToken   = "MINIO_LOGGER_LOKI_BEARER_TOKEN"
+	EnvLoggerLokiTenantID      = "MINIO_LOGGER_LOKI_TENANT_ID"
+	EnvLoggerLokiQueueSize     = "MINIO_LOGGER_LOKI_QUEUE_SIZE"
+	EnvLoggerLokiBatchSize     = "MINIO_LOGGER_LOKI_BATCH_SIZE"
+	EnvLoggerLokiBatchInterval = "MINIO_LOGGER_LOKI_BATCH_INTERVAL"
+
+	EnvLoggerWebSocketEnable    = "MINIO_LOGGER_WEBSOCKET_ENABLE"
+	EnvLoggerWebSocketEndpoint  = "MINIO_LOGGER_WEBSOCKET_ENDPOINT"
+	EnvLoggerWebSocketAuthToken = "MINIO_LOGGER_WEBSOCKET_AUTH_TOKEN"
+	EnvLoggerWebSocketQueueSize = "MINIO_LOGGER_WEBSOCKET_QUEUE_SIZE"
+
+	EnvAuditSplunkEnable        = "MINIO_AUDIT_SPLUNK_ENABLE"
+	EnvAuditSplunkURL           = "MINIO_AUDIT_SPLUNK_URL"
+	EnvAuditSplunkToken         = "MINIO_AUDIT_SPLUNK_TOKEN"
+	EnvAuditSplunkIndex         = "MINIO_AUDIT_SPLUNK_INDEX"
+	EnvAuditSplunkSourceType    = "MINIO_AUDIT_SPLUNK_SOURCETYPE"
+	EnvAuditSplunkSource        = "MINIO_AUDIT_SPLUNK_SOURCE"
+	EnvAuditSplunkQueueSize     = "MINIO_AUDIT_SPLUNK_QUEUE_SIZE"
+	EnvAuditSplunkBatchInterval = "MINIO_AUDIT_SPLUNK_BATCH_INTERVAL"
+
+	EnvKafkaEnable                  = "MINIO_AUDIT_KAFKA_ENABLE"
+	EnvKafkaBrokers                 = "MINIO_AUDIT_KAFKA_BROKERS"
+	EnvKafkaTopic                   = "MINIO_AUDIT_KAFKA_TOPIC"
+	EnvKafkaTLS                     = "MINIO_AUDIT_KAFKA_TLS"
+	EnvKafkaTLSSkipVerify           = "MINIO_AUDIT_KAFKA_TLS_SKIP_VERIFY"
+	EnvKafkaTLSClientAuth           = "MINIO_AUDIT_KAFKA_TLS_CLIENT_AUTH"
+	EnvKafkaTLSCA                   = "MINIO_AUDIT_KAFKA_TLS_CA"
+	EnvKafkaSASLEnable              = "MINIO_AUDIT_KAFKA_SASL"
+	EnvKafkaSASLUsername            = "MINIO_AUDIT_KAFKA_SASL_USERNAME"
+	EnvKafkaSASLPassword            = "MINIO_AUDIT_KAFKA_SASL_PASSWORD"
+	EnvKafkaSASLMechanism           = "MINIO_AUDIT_KAFKA_SASL_MECHANISM"
+	EnvKafkaSASLKerberosServiceName = "MINIO_AUDIT_KAFKA_SASL_KERBEROS_SERVICE_NAME"
+	EnvKafkaSASLKerberosRealm       = "MINIO_AUDIT_KAFKA_SASL_KERBEROS_REALM"
+	EnvKafkaSASLKerberosKeytab      = "MINIO_AUDIT_KAFKA_SASL_KERBEROS_KEYTAB"
+	EnvKafkaSASLKerberosConfig      = "MINIO_AUDIT_KAFKA_SASL_KERBEROS_CONFIG"
+	EnvKafkaSASLOAuthTokenURL       = "MINIO_AUDIT_KAFKA_SASL_OAUTH_TOKEN_URL"
+	EnvKafkaSASLOAuthClientID       = "MINIO_AUDIT_KAFKA_SASL_OAUTH_CLIENT_ID"
+	EnvKafkaSASLOAuthClientSecret   = "MINIO_AUDIT_KAFKA_SASL_OAUTH_CLIENT_SECRET"
+	EnvKafkaSASLOAuthScopes         = "MINIO_AUDIT_KAFKA_SASL_OAUTH_SCOPES"
+	EnvKafkaClientTLSCert           = "MINIO_AUDIT_KAFKA_CLIENT_TLS_CERT"
+	EnvKafkaClientTLSKey            = "MINIO_AUDIT_KAFKA_CLIENT_TLS_KEY"
+	EnvKafkaVersion                 = "MINIO_AUDIT_KAFKA_VERSION"
+	EnvKafkaFallbackEndpoint        = "MINIO_AUDIT_KAFKA_FALLBACK_ENDPOINT"
+
+	EnvAMQPEnable            = "MINIO_AUDIT_AMQP_ENABLE"
+	EnvAMQPURL               = "MINIO_AUDIT_AMQP_URL"
+	EnvAMQPExchange          = "MINIO_AUDIT_AMQP_EXCHANGE"
+	EnvAMQPExchangeType      = "MINIO_AUDIT_AMQP_EXCHANGE_TYPE"
+	EnvAMQPRoutingKey        = "MINIO_AUDIT_AMQP_ROUTING_KEY"
+	EnvAMQPDeliveryMode      = "MINIO_AUDIT_AMQP_DELIVERY_MODE"
+	EnvAMQPPublisherConfirms = "MINIO_AUDIT_AMQP_PUBLISHER_CONFIRMS"
+	EnvAMQPQueueSize         = "MINIO_AUDIT_AMQP_QUEUE_SIZE"
+	EnvAMQPTLSSkipVerify     = "MINIO_AUDIT_AMQP_TLS_SKIP_VERIFY"
+	EnvAMQPClientTLSCert     = "MINIO_AUDIT_AMQP_CLIENT_TLS_CERT"
+	EnvAMQPClientTLSKey      = "MINIO_AUDIT_AMQP_CLIENT_TLS_KEY"
 )
 
 // Default KVS for loggerHTTP and loggerAuditHTTP
@@ -113,6 +230,22 @@ var (
 			Key:   QueueSize,
 			Value: "100000",
 		},
+		config.KV{
+			Key:   Filter,
+			Value: "",
+		},
+		config.KV{
+			Key:   RequestIDHeader,
+			Value: "",
+		},
+		config.KV{
+			Key:   AllowInsecureAuth,
+			Value: config.EnableOff,
+		},
+		config.KV{
+			Key:   FollowRedirects,
+			Value: config.EnableOn,
+		},
 	}
 
 	DefaultAuditWebhookKVS = config.KVS{
@@ -140,6 +273,54 @@ var (
 			Key:   QueueSize,
 			Value: "100000",
 		},
+		config.KV{
+			Key:   Filter,
+			Value: "",
+		},
+		config.KV{
+			Key:   RequestIDHeader,
+			Value: "",
+		},
+		config.KV{
+			Key:   Format,
+			Value: http.FormatJSON,
+		},
+		config.KV{
+			Key:   CEFFields,
+			Value: "",
+		},
+		config.KV{
+			Key:   PriorityFilter,
+			Value: "",
+		},
+		config.KV{
+			Key:   APIFilter,
+			Value: "",
+		},
+		config.KV{
+			Key:   APIFilterDrop,
+			Value: config.EnableOff,
+		},
+		config.KV{
+			Key:   PinnedCerts,
+			Value: "",
+		},
+		config.KV{
+			Key:   Ordering,
+			Value: http.OrderingUnordered,
+		},
+		config.KV{
+			Key:   Backpressure,
+			Value: "",
+		},
+		config.KV{
+			Key:   AllowInsecureAuth,
+			Value: config.EnableOff,
+		},
+		config.KV{
+			Key:   FollowRedirects,
+			Value: config.EnableOn,
+		},
 	}
 
 	DefaultAuditKafkaKVS = config.KVS{
@@ -167,6 +348,38 @@ var (
 			Key:   KafkaSASLMechanism,
 			Value: "plain",
 		},
+		config.KV{
+			Key:   KafkaSASLKerberosServiceName,
+			Value: "",
+		},
+		config.KV{
+			Key:   KafkaSASLKerberosRealm,
+			Value: "",
+		},
+		config.KV{
+			Key:   KafkaSASLKerberosKeytab,
+			Value: "",
+		},
+		config.KV{
+			Key:   KafkaSASLKerberosConfig,
+			Value: "",
+		},
+		config.KV{
+			Key:   KafkaSASLOAuthTokenURL,
+			Value: "",
+		},
+		config.KV{
+			Key:   KafkaSASLOAuthClientID,
+			Value: "",
+		},
+		config.KV{
+			Key:   KafkaSASLOAuthClientSecret,
+			Value: "",
+		},
+		config.KV{
+			Key:   KafkaSASLOAuthScopes,
+			Value: "",
+		},
 		config.KV{
 			Key:   KafkaClientTLSCert,
 			Value: "",
@@ -191,19 +404,79 @@ var (
 			Key:   KafkaTLSSkipVerify,
 			Value: config.EnableOff,
 		},
+		config.KV{
+			Key:   KafkaTLSCA,
+			Value: "",
+		},
 		config.KV{
 			Key:   KafkaVersion,
 			Value: "",
 		},
+		config.KV{
+			Key:   KafkaFallbackEndpoint,
+			Value: "",
+		},
+	}
+
+	DefaultAuditAMQPKVS = config.KVS{
+		config.KV{
+			Key:   config.Enable,
+			Value: config.EnableOff,
+		},
+		config.KV{
+			Key:   AMQPURL,
+			Value: "",
+		},
+		config.KV{
+			Key:   AMQPExchange,
+			Value: "",
+		},
+		config.KV{
+			Key:   AMQPExchangeType,
+			Value: "",
+		},
+		config.KV{
+			Key:   AMQPRoutingKey,
+			Value: "",
+		},
+		config.KV{
+			Key:   AMQPDeliveryMode,
+			Value: amqp.DeliveryModePersistent,
+		},
+		config.KV{
+			Key:   AMQPPublisherConfirms,
+			Value: config.EnableOff,
+		},
+		config.KV{
+			Key:   QueueSize,
+			Value: "10000",
+		},
+		config.KV{
+			Key:   AMQPTLSSkipVerify,
+			Value: config.EnableOff,
+		},
+		config.KV{
+			Key:   AMQPClientTLSCert,
+			Value: "",
+		},
+		config.KV{
+			Key:   AMQPClientTLSKey,
+			Value: "",
+		},
 	}
 )
 
 // Config console and http logger targets
 type Config struct {
-	Console      Console                 `json:"console"`
-	HTTP         map[string]http.Config  `json:"http"`
-	AuditWebhook map[string]http.Config  `json:"audit"`
-	AuditKafka   map[string]kafka.Config `json:"audit_kafka"`
+	Console      Console                     `json:"console"`
+	HTTP         map[string]http.Config      `json:"http"`
+	AuditWebhook map[string]http.Config      `json:"audit"`
+	AuditKafka   map[string]kafka.Config     `json:"audit_kafka"`
+	AuditAMQP    map[string]amqp.Config      `json:"audit_amqp"`
+	File         map[string]file.Config      `json:"file"`
+	AuditSplunk  map[string]splunk.Config    `json:"audit_splunk"`
+	Loki         map[string]loki.Config      `json:"loki"`
+	WebSocket    map[string]websocket.Config `json:"websocket"`
 }
 
 // NewConfig - initialize new logger config.
@@ -216,6 +489,11 @@ func NewConfig() Config {
 		HTTP:         make(map[string]http.Config),
 		AuditWebhook: make(map[string]http.Config),
 		AuditKafka:   make(map[string]kafka.Config),
+		AuditAMQP:    make(map[string]amqp.Config),
+		File:         make(map[string]file.Config),
+		AuditSplunk:  make(map[string]splunk.Config),
+		Loki:         make(map[string]loki.Config),
+		WebSocket:    make(map[string]websocket.Config),
 	}
 
 	return cfg
@@ -282,119 +560,354 @@ func lookupLegacyConfigForSubSys(subSys string) Config {
 	return cfg
 }
 
+// defaultQueueSize is the queue size of webhook targets set up from the environment.
+const defaultQueueSize = "100000"
+
+// EnvLoggerMaxTargets caps the number of enabled targets of each logger
+// sub-system, each of them allocating its queue.
+const EnvLoggerMaxTargets = "MINIO_LOGGER_MAX_TARGETS"
+
+// defaultMaxTargets is the default of EnvLoggerMaxTargets.
+const defaultMaxTargets = "100"
+
+// checkMaxTargets returns an error if more than EnvLoggerMaxTargets
+// targets of subSys are enabled, e.g. set up by a mistyped environment.
+func checkMaxTargets(subSys string, n int) error {
+	max, err := strconv.Atoi(env.Get(EnvLoggerMaxTargets, defaultMaxTargets))
+	if err != nil || max <= 0 {
+		return config.Errorf("invalid %s value, expected a positive number", EnvLoggerMaxTargets)
+	}
+	if n > max {
+		return config.Errorf("%d %s targets are enabled, more than the maximum of %d, set %s to allow more",
+			n, subSys, max, EnvLoggerMaxTargets)
+	}
+	return nil
+}
+
+// getCfgVal returns the value of the environment variable envName for
+// target, suffixed with the target name unless it is the default one,
+// or defaultValue if it is not set.
+func getCfgVal(envName, target, defaultValue string) string {
+	if target != config.Default {
+		envName = envName + config.Default + target
+	}
+	return env.Get(envName, defaultValue)
+}
+
+// getIntCfg - same as getCfgVal for integer values.
+func getIntCfg(envName, target, defaultValue string) (int, error) {
+	return strconv.Atoi(getCfgVal(envName, target, defaultValue))
+}
+
+// getBoolCfg - same as getCfgVal for boolean values.
+func getBoolCfg(envName, target, defaultValue string) (bool, error) {
+	return config.ParseBool(getCfgVal(envName, target, defaultValue))
+}
+
+// getDurationCfg - same as getCfgVal for duration values.
+func getDurationCfg(envName, target, defaultValue string) (time.Duration, error) {
+	return time.ParseDuration(getCfgVal(envName, target, defaultValue))
+}
+
+// getQueueSizeCfg - same as getIntCfg for queue sizes, which must be positive.
+func getQueueSizeCfg(envName, target, defaultValue string) (int, error) {
+	return parseQueueSize(getCfgVal(envName, target, defaultValue))
+}
+
+func parseQueueSize(value string) (int, error) {
+	queueSize, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+	if queueSize <= 0 {
+		return 0, errors.New("invalid queue_size value")
+	}
+	return queueSize, nil
+}
+
+// parseFilter validates a filter expression, empty to send every entry.
+func parseFilter(value string) (string, error) {
+	if value != "" {
+		if _, err := filter.Parse(value); err != nil {
+			return "", config.Errorf("%v", err)
+		}
+	}
+	return value, nil
+}
+
+// parseAPIFilter parses a comma separated list of API name
+// patterns, empty to send the entries of every API.
+func parseAPIFilter(value string) ([]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+	patterns := strings.Split(value, config.ValueSeparator)
+	if err := http.ValidateAPIFilter(patterns); err != nil {
+		return nil, config.Errorf("%v", err)
+	}
+	return patterns, nil
+}
+
+// parsePinnedCerts parses a comma separated list of SHA-256
+// certificate fingerprints, empty to not pin the certificate.
+func parsePinnedCerts(value string) ([]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+	pins := strings.Split(value, config.ValueSeparator)
+	if _, err := http.ParseCertPins(pins); err != nil {
+		return nil, config.Errorf("%v", err)
+	}
+	return pins, nil
+}
+
+// parseOrdering validates the ordering mode of the entries,
+// which cannot be ordered with a priority filter.
+func parseOrdering(ordering, priorityFilter string) (string, error) {
+	switch ordering {
+	case "", http.OrderingUnordered:
+	case http.OrderingOrdered:
+		if priorityFilter != "" {
+			return "", config.Errorf("ordered delivery cannot be used with a priority_filter")
+		}
+	default:
+		return "", config.Errorf("invalid ordering value %q, expected unordered or ordered", ordering)
+	}
+	return ordering, nil
+}
+
+// checkInsecureAuth refuses sending an auth token to a plaintext
+// http endpoint, which leaks it on the wire, unless allowed.
+func checkInsecureAuth(endpoint, authToken string, allowInsecureAuth bool) error {
+	if authToken != "" && !allowInsecureAuth && http.IsPlaintextEndpoint(endpoint) {
+		return config.Errorf("auth_token cannot be sent to a plaintext http endpoint, use https or set allow_insecure_auth for testing")
+	}
+	return nil
+}
+
+// parseRequestIDHeader validates the name of the header request IDs
+// are sent in, empty to not send them.
+func parseRequestIDHeader(value string) (string, error) {
+	if value != "" && !httpguts.ValidHeaderFieldName(value) {
+		return "", config.Errorf("invalid request_id_header value %q", value)
+	}
+	return value, nil
+}
+
+// parseBackpressureHeader validates the name of the header the
+// endpoint signals backpressure in, empty to not slow down.
+func parseBackpressureHeader(value string) (string, error) {
+	if value != "" && !httpguts.ValidHeaderFieldName(value) {
+		return "", config.Errorf("invalid backpressure_header value %q", value)
+	}
+	return value, nil
+}
+
+// parseFormat validates the format of the payloads and
+// the extension mapping of CEF payloads.
+func parseFormat(format, cefFields string) (string, error) {
+	switch format {
+	case "", http.FormatJSON:
+	case http.FormatCEF:
+		if cefFields != "" {
+			if _, err := cef.ParseFields(cefFields); err != nil {
+				return "", config.Errorf("%v", err)
+			}
+		}
+	default:
+		return "", config.Errorf("invalid format value %q, expected json or cef", format)
+	}
+	return format, nil
+}
+
+// kafkaSRVPrefix prefixes the brokers discovered with the SRV
+// records of a name, e.g. srv://_kafka._tcp.example.com
+const kafkaSRVPrefix = "srv://"
+
+// kafkaSRVTimeout bounds the lookup of the SRV records of the brokers.
+const kafkaSRVTimeout = 5 * time.Second
+
+// lookupSRV is replaced by the tests.
+var lookupSRV = net.DefaultResolver.LookupSRV
+
+// parseKafkaBrokers parses a comma separated list of broker addresses,
+// resolving the srv:// entries to the targets of their SRV records.
+func parseKafkaBrokers(ctx context.Context, value string) ([]xnet.Host, error) {
+	var brokers []xnet.Host
+	for _, s := range strings.Split(value, config.ValueSeparator) {
+		if !strings.HasPrefix(s, kafkaSRVPrefix) {
+			host, err := xnet.ParseHost(s)
+			if err != nil {
+				return nil, err
+			}
+			brokers = append(brokers, *host)
+			continue
+		}
+		name := strings.TrimPrefix(s, kafkaSRVPrefix)
+		_, addrs, err := lookupSRV(ctx, "", "", name)
+		if err == nil && len(addrs) == 0 {
+			err = errors.New("no records found")
+		}
+		if err != nil {
+			return nil, config.Errorf("unable to resolve kafka brokers of %q: %v", name, err)
+		}
+		for _, addr := range addrs {
+			target := strings.TrimSuffix(addr.Target, ".")
+			host, err := xnet.ParseHost(net.JoinHostPort(target, strconv.Itoa(int(addr.Port))))
+			if err != nil {
+				return nil, config.Errorf("invalid kafka broker %q in the SRV records of %q: %v", addr.Target, name, err)
+			}
+			brokers = append(brokers, *host)
+		}
+	}
+	return brokers, nil
+}
+
 // GetAuditKafka - returns a map of registered notification 'kafka' targets
 func GetAuditKafka(kafkaKVS map[string]config.KVS) (map[string]kafka.Config, error) {
 	kafkaTargets := make(map[string]kafka.Config)
 	for k, kv := range config.Merge(kafkaKVS, EnvKafkaEnable, DefaultAuditKafkaKVS) {
-		enableEnv := EnvKafkaEnable
-		if k != config.Default {
-			enableEnv = enableEnv + config.Default + k
-		}
-		enabled, err := config.ParseBool(env.Get(enableEnv, kv.Get(config.Enable)))
+		enabled, err := getBoolCfg(EnvKafkaEnable, k, kv.Get(config.Enable))
 		if err != nil {
 			return nil, err
 		}
 		if !enabled {
 			continue
 		}
-		var brokers []xnet.Host
-		brokersEnv := EnvKafkaBrokers
-		if k != config.Default {
-			brokersEnv = brokersEnv + config.Default + k
-		}
-		kafkaBrokers := env.Get(brokersEnv, kv.Get(KafkaBrokers))
+		kafkaBrokers := getCfgVal(EnvKafkaBrokers, k, kv.Get(KafkaBrokers))
 		if len(kafkaBrokers) == 0 {
 			return nil, config.Errorf("kafka 'brokers' cannot be empty")
 		}
-		for _, s := range strings.Split(kafkaBrokers, config.ValueSeparator) {
-			var host *xnet.Host
-			host, err = xnet.ParseHost(s)
-			if err != nil {
-				break
-			}
-			brokers = append(brokers, *host)
-		}
+		ctx, cancel := context.WithTimeout(context.Background(), kafkaSRVTimeout)
+		brokers, err := parseKafkaBrokers(ctx, kafkaBrokers)
+		cancel()
 		if err != nil {
 			return nil, err
 		}
 
-		clientAuthEnv := EnvKafkaTLSClientAuth
-		if k != config.Default {
-			clientAuthEnv = clientAuthEnv + config.Default + k
-		}
-		clientAuth, err := strconv.Atoi(env.Get(clientAuthEnv, kv.Get(KafkaTLSClientAuth)))
+		clientAuth, err := getIntCfg(EnvKafkaTLSClientAuth, k, kv.Get(KafkaTLSClientAuth))
 		if err != nil {
 			return nil, err
 		}
 
-		topicEnv := EnvKafkaTopic
-		if k != config.Default {
-			topicEnv = topicEnv + config.Default + k
-		}
-
-		versionEnv := EnvKafkaVersion
-		if k != config.Default {
-			versionEnv = versionEnv + config.Default + k
-		}
-
 		kafkaArgs := kafka.Config{
 			Enabled: enabled,
 			Brokers: brokers,
-			Topic:   env.Get(topicEnv, kv.Get(KafkaTopic)),
-			Version: env.Get(versionEnv, kv.Get(KafkaVersion)),
-		}
+			Topic:   getCfgVal(EnvKafkaTopic, k, kv.Get(KafkaTopic)),
+			Version: getCfgVal(EnvKafkaVersion, k, kv.Get(KafkaVersion)),
 
-		tlsEnableEnv := EnvKafkaTLS
-		if k != config.Default {
-			tlsEnableEnv = tlsEnableEnv + config.Default + k
+			FallbackEndpoint: getCfgVal(EnvKafkaFallbackEndpoint, k, kv.Get(KafkaFallbackEndpoint)),
+		}
+		if strings.Contains(kafkaBrokers, kafkaSRVPrefix) {
+			// The records may have changed by the time the target starts.
+			kafkaArgs.ResolveBrokers = func(ctx context.Context) ([]xnet.Host, error) {
+				return parseKafkaBrokers(ctx, kafkaBrokers)
+			}
 		}
-		tlsSkipVerifyEnv := EnvKafkaTLSSkipVerify
-		if k != config.Default {
-			tlsSkipVerifyEnv = tlsSkipVerifyEnv + config.Default + k
+
+		kafkaArgs.TLS.Enable = getCfgVal(EnvKafkaTLS, k, kv.Get(KafkaTLS)) == config.EnableOn
+		kafkaArgs.TLS.SkipVerify = getCfgVal(EnvKafkaTLSSkipVerify, k, kv.Get(KafkaTLSSkipVerify)) == config.EnableOn
+		kafkaArgs.TLS.ClientAuth = tls.ClientAuthType(clientAuth)
+		if kafkaArgs.TLS.CA = getCfgVal(EnvKafkaTLSCA, k, kv.Get(KafkaTLSCA)); kafkaArgs.TLS.CA != "" {
+			if kafkaArgs.TLS.RootCAs, err = config.LoadRootCAs(kafkaArgs.TLS.CA); err != nil {
+				return nil, config.Errorf("kafka 'tls_ca': %v", err)
+			}
 		}
 
-		tlsClientTLSCertEnv := EnvKafkaClientTLSCert
-		if k != config.Default {
-			tlsClientTLSCertEnv = tlsClientTLSCertEnv + config.Default + k
+		kafkaArgs.TLS.ClientTLSCert = getCfgVal(EnvKafkaClientTLSCert, k, kv.Get(KafkaClientTLSCert))
+		kafkaArgs.TLS.ClientTLSKey = getCfgVal(EnvKafkaClientTLSKey, k, kv.Get(KafkaClientTLSKey))
+		if config.IsPEM(kafkaArgs.TLS.ClientTLSCert) || config.IsPEM(kafkaArgs.TLS.ClientTLSKey) {
+			if err = config.EnsureCertAndKey(kafkaArgs.TLS.ClientTLSCert, kafkaArgs.TLS.ClientTLSKey); err != nil {
+				return nil, err
+			}
 		}
 
-		tlsClientTLSKeyEnv := EnvKafkaClientTLSKey
-		if k != config.Default {
-			tlsClientTLSKeyEnv = tlsClientTLSKeyEnv + config.Default + k
+		kafkaArgs.SASL.Enable = getCfgVal(EnvKafkaSASLEnable, k, kv.Get(KafkaSASL)) == config.EnableOn
+		kafkaArgs.SASL.User = getCfgVal(EnvKafkaSASLUsername, k, kv.Get(KafkaSASLUsername))
+		kafkaArgs.SASL.Password = getCfgVal(EnvKafkaSASLPassword, k, kv.Get(KafkaSASLPassword))
+		kafkaArgs.SASL.Mechanism = strings.ToLower(getCfgVal(EnvKafkaSASLMechanism, k, kv.Get(KafkaSASLMechanism)))
+
+		if kafkaArgs.SASL.Enable && kafkaArgs.SASL.Mechanism == kafka.SASLMechanismGSSAPI {
+			kafkaArgs.SASL.KerberosServiceName = getCfgVal(EnvKafkaSASLKerberosServiceName, k, kv.Get(KafkaSASLKerberosServiceName))
+			kafkaArgs.SASL.KerberosRealm = getCfgVal(EnvKafkaSASLKerberosRealm, k, kv.Get(KafkaSASLKerberosRealm))
+			kafkaArgs.SASL.KerberosKeytab = getCfgVal(EnvKafkaSASLKerberosKeytab, k, kv.Get(KafkaSASLKerberosKeytab))
+			kafkaArgs.SASL.KerberosConfig = getCfgVal(EnvKafkaSASLKerberosConfig, k, kv.Get(KafkaSASLKerberosConfig))
+			if err = kafkaArgs.SASL.ValidateKerberos(); err != nil {
+				return nil, config.Errorf("kafka %s", err)
+			}
+		}
+		if kafkaArgs.SASL.Enable && kafkaArgs.SASL.Mechanism == kafka.SASLMechanismOAuthBearer {
+			kafkaArgs.SASL.OAuthTokenURL = getCfgVal(EnvKafkaSASLOAuthTokenURL, k, kv.Get(KafkaSASLOAuthTokenURL))
+			kafkaArgs.SASL.OAuthClientID = getCfgVal(EnvKafkaSASLOAuthClientID, k, kv.Get(KafkaSASLOAuthClientID))
+			kafkaArgs.SASL.OAuthClientSecret = getCfgVal(EnvKafkaSASLOAuthClientSecret, k, kv.Get(KafkaSASLOAuthClientSecret))
+			if scopes := getCfgVal(EnvKafkaSASLOAuthScopes, k, kv.Get(KafkaSASLOAuthScopes)); scopes != "" {
+				kafkaArgs.SASL.OAuthScopes = strings.Split(scopes, config.ValueSeparator)
+			}
+			if err = kafkaArgs.SASL.ValidateOAuth(); err != nil {
+				return nil, config.Errorf("kafka %s", err)
+			}
 		}
 
-		kafkaArgs.TLS.Enable = env.Get(tlsEnableEnv, kv.Get(KafkaTLS)) == config.EnableOn
-		kafkaArgs.TLS.SkipVerify = env.Get(tlsSkipVerifyEnv, kv.Get(KafkaTLSSkipVerify)) == config.EnableOn
-		kafkaArgs.TLS.ClientAuth = tls.ClientAuthType(clientAuth)
+		kafkaTargets[k] = kafkaArgs
+	}
 
-		kafkaArgs.TLS.ClientTLSCert = env.Get(tlsClientTLSCertEnv, kv.Get(KafkaClientTLSCert))
-		kafkaArgs.TLS.ClientTLSKey = env.Get(tlsClientTLSKeyEnv, kv.Get(KafkaClientTLSKey))
+	return kafkaTargets, nil
+}
 
-		saslEnableEnv := EnvKafkaSASLEnable
-		if k != config.Default {
-			saslEnableEnv = saslEnableEnv + config.Default + k
+// lookupAuditAMQPConfig - returns a map of registered audit 'amqp' targets
+func lookupAuditAMQPConfig(amqpKVS map[string]config.KVS) (map[string]amqp.Config, error) {
+	amqpTargets := make(map[string]amqp.Config)
+	for k, kv := range config.Merge(amqpKVS, EnvAMQPEnable, DefaultAuditAMQPKVS) {
+		enabled, err := getBoolCfg(EnvAMQPEnable, k, kv.Get(config.Enable))
+		if err != nil {
+			return nil, err
+		}
+		if !enabled {
+			continue
+		}
+		rawURL := getCfgVal(EnvAMQPURL, k, kv.Get(AMQPURL))
+		if rawURL == "" {
+			return nil, config.Errorf("amqp 'url' cannot be empty")
+		}
+		u, err := xnet.ParseURL(rawURL)
+		if err != nil {
+			return nil, config.Errorf("invalid amqp 'url': %v", err)
 		}
-		saslUsernameEnv := EnvKafkaSASLUsername
-		if k != config.Default {
-			saslUsernameEnv = saslUsernameEnv + config.Default + k
+		publisherConfirms, err := getBoolCfg(EnvAMQPPublisherConfirms, k, kv.Get(AMQPPublisherConfirms))
+		if err != nil {
+			return nil, err
 		}
-		saslPasswordEnv := EnvKafkaSASLPassword
-		if k != config.Default {
-			saslPasswordEnv = saslPasswordEnv + config.Default + k
+		queueSize, err := getQueueSizeCfg(EnvAMQPQueueSize, k, kv.Get(QueueSize))
+		if err != nil {
+			return nil, err
 		}
-		saslMechanismEnv := EnvKafkaSASLMechanism
-		if k != config.Default {
-			saslMechanismEnv = saslMechanismEnv + config.Default + k
+		skipVerify, err := getBoolCfg(EnvAMQPTLSSkipVerify, k, kv.Get(AMQPTLSSkipVerify))
+		if err != nil {
+			return nil, err
 		}
-		kafkaArgs.SASL.Enable = env.Get(saslEnableEnv, kv.Get(KafkaSASL)) == config.EnableOn
-		kafkaArgs.SASL.User = env.Get(saslUsernameEnv, kv.Get(KafkaSASLUsername))
-		kafkaArgs.SASL.Password = env.Get(saslPasswordEnv, kv.Get(KafkaSASLPassword))
-		kafkaArgs.SASL.Mechanism = env.Get(saslMechanismEnv, kv.Get(KafkaSASLMechanism))
 
-		kafkaTargets[k] = kafkaArgs
+		amqpArgs := amqp.Config{
+			Enabled:      enabled,
+			URL:          *u,
+			Exchange:     getCfgVal(EnvAMQPExchange, k, kv.Get(AMQPExchange)),
+			ExchangeType: getCfgVal(EnvAMQPExchangeType, k, kv.Get(AMQPExchangeType)),
+			RoutingKey:   getCfgVal(EnvAMQPRoutingKey, k, kv.Get(AMQPRoutingKey)),
+			DeliveryMode: getCfgVal(EnvAMQPDeliveryMode, k, kv.Get(AMQPDeliveryMode)),
+
+			PublisherConfirms: publisherConfirms,
+			QueueSize:         queueSize,
+		}
+		amqpArgs.TLS.SkipVerify = skipVerify
+		amqpArgs.TLS.ClientTLSCert = getCfgVal(EnvAMQPClientTLSCert, k, kv.Get(AMQPClientTLSCert))
+		amqpArgs.TLS.ClientTLSKey = getCfgVal(EnvAMQPClientTLSKey, k, kv.Get(AMQPClientTLSKey))
+		if err = amqpArgs.Validate(); err != nil {
+			return nil, config.Errorf("amqp %s", err)
+		}
+
+		amqpTargets[k] = amqpArgs
 	}
 
-	return kafkaTargets, nil
+	return amqpTargets, nil
 }
 
 func lookupLoggerWebhookConfig(scfg config.Config, cfg Config) (Config, error) {
@@ -415,52 +928,58 @@ func lookupLoggerWebhookConfig(scfg config.Config, cfg Config) (Config, error) {
 			// legacy environment variables, ignore.
 			continue
 		}
-		enableEnv := EnvLoggerWebhookEnable
-		if target != config.Default {
-			enableEnv = EnvLoggerWebhookEnable + config.Default + target
-		}
-		enable, err := config.ParseBool(env.Get(enableEnv, ""))
+		enable, err := getBoolCfg(EnvLoggerWebhookEnable, target, "")
 		if err != nil || !enable {
 			continue
 		}
-		endpointEnv := EnvLoggerWebhookEndpoint
-		if target != config.Default {
-			endpointEnv = EnvLoggerWebhookEndpoint + config.Default + target
-		}
-		authTokenEnv := EnvLoggerWebhookAuthToken
-		if target != config.Default {
-			authTokenEnv = EnvLoggerWebhookAuthToken + config.Default + target
+		clientCert := getCfgVal(EnvLoggerWebhookClientCert, target, "")
+		clientKey := getCfgVal(EnvLoggerWebhookClientKey, target, "")
+		if err = config.EnsureCertAndKey(clientCert, clientKey); err != nil {
+			return cfg, err
 		}
-		clientCertEnv := EnvLoggerWebhookClientCert
-		if target != config.Default {
-			clientCertEnv = EnvLoggerWebhookClientCert + config.Default + target
+		queueSize, err := getQueueSizeCfg(EnvAuditWebhookQueueSize, target, defaultQueueSize)
+		if err != nil {
+			return cfg, err
 		}
-		clientKeyEnv := EnvLoggerWebhookClientKey
-		if target != config.Default {
-			clientKeyEnv = EnvLoggerWebhookClientKey + config.Default + target
+		expr, err := parseFilter(getCfgVal(EnvLoggerWebhookFilter, target, ""))
+		if err != nil {
+			return cfg, err
 		}
-		err = config.EnsureCertAndKey(env.Get(clientCertEnv, ""), env.Get(clientKeyEnv, ""))
+		requestIDHeader, err := parseRequestIDHeader(getCfgVal(EnvLoggerWebhookRequestIDHeader, target, ""))
 		if err != nil {
 			return cfg, err
 		}
-		queueSizeEnv := EnvAuditWebhookQueueSize
-		if target != config.Default {
-			queueSizeEnv = EnvAuditWebhookQueueSize + config.Default + target
+		endpoint := getCfgVal(EnvLoggerWebhookEndpoint, target, "")
+		authToken := getCfgVal(EnvLoggerWebhookAuthToken, target, "")
+		allowInsecureAuth, err := getBoolCfg(EnvLoggerWebhookAllowInsecureAuth, target, config.EnableOff)
+		if err != nil {
+			return cfg, err
 		}
-		queueSize, err := strconv.Atoi(env.Get(queueSizeEnv, "100000"))
+		followRedirects, err := getBoolCfg(EnvLoggerWebhookFollowRedirects, target, config.EnableOn)
 		if err != nil {
 			return cfg, err
 		}
-		if queueSize <= 0 {
-			return cfg, errors.New("invalid queue_size value")
+		if err = checkInsecureAuth(endpoint, authToken, allowInsecureAuth); err != nil {
+			return cfg, err
 		}
 		cfg.HTTP[target] = http.Config{
-			Enabled:    true,
-			Endpoint:   env.Get(endpointEnv, ""),
-			AuthToken:  env.Get(authTokenEnv, ""),
-			ClientCert: env.Get(clientCertEnv, ""),
-			ClientKey:  env.Get(clientKeyEnv, ""),
-			QueueSize:  queueSize,
+			Enabled:           true,
+			Endpoint:          endpoint,
+			AuthToken:         authToken,
+			ClientCert:        clientCert,
+			ClientKey:         clientKey,
+			QueueSize:         queueSize,
+			Filter:            expr,
+			RequestIDHeader:   requestIDHeader,
+			AllowInsecureAuth: allowInsecureAuth,
+			DisableRedirects:  !followRedirects,
+		}
+	}
+
+	// Load HTTP logger from the config file if set
+	if configFile := env.Get(EnvLoggerWebhookConfigFile, ""); configFile != "" {
+		if err := lookupWebhookConfigFile(configFile, cfg.HTTP); err != nil {
+			return cfg, err
 		}
 	}
 
@@ -489,20 +1008,40 @@ func lookupLoggerWebhookConfig(scfg config.Config, cfg Config) (Config, error) {
 		if err != nil {
 			return cfg, err
 		}
-		queueSize, err := strconv.Atoi(kv.Get(QueueSize))
+		queueSize, err := parseQueueSize(kv.Get(QueueSize))
+		if err != nil {
+			return cfg, err
+		}
+		expr, err := parseFilter(kv.Get(Filter))
+		if err != nil {
+			return cfg, err
+		}
+		requestIDHeader, err := parseRequestIDHeader(kv.Get(RequestIDHeader))
+		if err != nil {
+			return cfg, err
+		}
+		allowInsecureAuth, err := config.ParseBool(kv.Get(AllowInsecureAuth))
 		if err != nil {
 			return cfg, err
 		}
-		if queueSize <= 0 {
-			return cfg, errors.New("invalid queue_size value")
+		followRedirects, err := config.ParseBool(kv.Get(FollowRedirects))
+		if err != nil {
+			return cfg, err
+		}
+		if err = checkInsecureAuth(kv.Get(Endpoint), kv.Get(AuthToken), allowInsecureAuth); err != nil {
+			return cfg, err
 		}
 		cfg.HTTP[starget] = http.Config{
-			Enabled:    true,
-			Endpoint:   kv.Get(Endpoint),
-			AuthToken:  kv.Get(AuthToken),
-			ClientCert: kv.Get(ClientCert),
-			ClientKey:  kv.Get(ClientKey),
-			QueueSize:  queueSize,
+			Enabled:           true,
+			Endpoint:          kv.Get(Endpoint),
+			AuthToken:         kv.Get(AuthToken),
+			ClientCert:        kv.Get(ClientCert),
+			ClientKey:         kv.Get(ClientKey),
+			QueueSize:         queueSize,
+			Filter:            expr,
+			RequestIDHeader:   requestIDHeader,
+			AllowInsecureAuth: allowInsecureAuth,
+			DisableRedirects:  !followRedirects,
 		}
 	}
 
@@ -526,52 +1065,96 @@ func lookupAuditWebhookConfig(scfg config.Config, cfg Config) (Config, error) {
 			// legacy environment variables, ignore.
 			continue
 		}
-		enableEnv := EnvAuditWebhookEnable
-		if target != config.Default {
-			enableEnv = EnvAuditWebhookEnable + config.Default + target
-		}
-		enable, err := config.ParseBool(env.Get(enableEnv, ""))
+		enable, err := getBoolCfg(EnvAuditWebhookEnable, target, "")
 		if err != nil || !enable {
 			continue
 		}
-		endpointEnv := EnvAuditWebhookEndpoint
-		if target != config.Default {
-			endpointEnv = EnvAuditWebhookEndpoint + config.Default + target
+		clientCert := getCfgVal(EnvAuditWebhookClientCert, target, "")
+		clientKey := getCfgVal(EnvAuditWebhookClientKey, target, "")
+		if err = config.EnsureCertAndKey(clientCert, clientKey); err != nil {
+			return cfg, err
 		}
-		authTokenEnv := EnvAuditWebhookAuthToken
-		if target != config.Default {
-			authTokenEnv = EnvAuditWebhookAuthToken + config.Default + target
+		queueSize, err := getQueueSizeCfg(EnvAuditWebhookQueueSize, target, defaultQueueSize)
+		if err != nil {
+			return cfg, err
 		}
-		clientCertEnv := EnvAuditWebhookClientCert
-		if target != config.Default {
-			clientCertEnv = EnvAuditWebhookClientCert + config.Default + target
+		expr, err := parseFilter(getCfgVal(EnvAuditWebhookFilter, target, ""))
+		if err != nil {
+			return cfg, err
 		}
-		clientKeyEnv := EnvAuditWebhookClientKey
-		if target != config.Default {
-			clientKeyEnv = EnvAuditWebhookClientKey + config.Default + target
+		requestIDHeader, err := parseRequestIDHeader(getCfgVal(EnvAuditWebhookRequestIDHeader, target, ""))
+		if err != nil {
+			return cfg, err
+		}
+		priorityExpr, err := parseFilter(getCfgVal(EnvAuditWebhookPriorityFilter, target, ""))
+		if err != nil {
+			return cfg, err
+		}
+		apiFilter, err := parseAPIFilter(getCfgVal(EnvAuditWebhookAPIFilter, target, ""))
+		if err != nil {
+			return cfg, err
+		}
+		dropMissingAPI, err := getBoolCfg(EnvAuditWebhookAPIFilterDrop, target, config.EnableOff)
+		if err != nil {
+			return cfg, err
+		}
+		pinnedCerts, err := parsePinnedCerts(getCfgVal(EnvAuditWebhookPinnedCerts, target, ""))
+		if err != nil {
+			return cfg, err
+		}
+		ordering, err := parseOrdering(getCfgVal(EnvAuditWebhookOrdering, target, ""), getCfgVal(EnvAuditWebhookPriorityFilter, target, ""))
+		if err != nil {
+			return cfg, err
+		}
+		backpressureHeader, err := parseBackpressureHeader(getCfgVal(EnvAuditWebhookBackpressure, target, ""))
+		if err != nil {
+			return cfg, err
 		}
-		err = config.EnsureCertAndKey(env.Get(clientCertEnv, ""), env.Get(clientKeyEnv, ""))
+		cefFields := getCfgVal(EnvAuditWebhookCEFFields, target, "")
+		format, err := parseFormat(getCfgVal(EnvAuditWebhookFormat, target, ""), cefFields)
 		if err != nil {
 			return cfg, err
 		}
-		queueSizeEnv := EnvAuditWebhookQueueSize
-		if target != config.Default {
-			queueSizeEnv = EnvAuditWebhookQueueSize + config.Default + target
+		endpoint := getCfgVal(EnvAuditWebhookEndpoint, target, "")
+		authToken := getCfgVal(EnvAuditWebhookAuthToken, target, "")
+		allowInsecureAuth, err := getBoolCfg(EnvAuditWebhookAllowInsecureAuth, target, config.EnableOff)
+		if err != nil {
+			return cfg, err
 		}
-		queueSize, err := strconv.Atoi(env.Get(queueSizeEnv, "100000"))
+		followRedirects, err := getBoolCfg(EnvAuditWebhookFollowRedirects, target, config.EnableOn)
 		if err != nil {
 			return cfg, err
 		}
-		if queueSize <= 0 {
-			return cfg, errors.New("invalid queue_size value")
+		if err = checkInsecureAuth(endpoint, authToken, allowInsecureAuth); err != nil {
+			return cfg, err
 		}
 		cfg.AuditWebhook[target] = http.Config{
-			Enabled:    true,
-			Endpoint:   env.Get(endpointEnv, ""),
-			AuthToken:  env.Get(authTokenEnv, ""),
-			ClientCert: env.Get(clientCertEnv, ""),
-			ClientKey:  env.Get(clientKeyEnv, ""),
-			QueueSize:  queueSize,
+			Enabled:         true,
+			Endpoint:        endpoint,
+			AuthToken:       authToken,
+			ClientCert:      clientCert,
+			ClientKey:       clientKey,
+			QueueSize:       queueSize,
+			Filter:          expr,
+			RequestIDHeader: requestIDHeader,
+			Format:          format,
+			CEFFields:       cefFields,
+			PriorityFilter:  priorityExpr,
+			APIFilter:       apiFilter,
+			DropMissingAPI:  dropMissingAPI,
+			OrderingMode:    ordering,
+
+			PinnedServerCertSHA256: pinnedCerts,
+			AllowInsecureAuth:      allowInsecureAuth,
+			BackpressureHeader:     backpressureHeader,
+			DisableRedirects:       !followRedirects,
+		}
+	}
+
+	// Load HTTP audit targets from the config file if set
+	if configFile := env.Get(EnvAuditWebhookConfigFile, ""); configFile != "" {
+		if err := lookupWebhookConfigFile(configFile, cfg.AuditWebhook); err != nil {
+			return cfg, err
 		}
 	}
 
@@ -600,24 +1183,263 @@ func lookupAuditWebhookConfig(scfg config.Config, cfg Config) (Config, error) {
 		if err != nil {
 			return cfg, err
 		}
-		queueSize, err := strconv.Atoi(kv.Get(QueueSize))
+		queueSize, err := parseQueueSize(kv.Get(QueueSize))
 		if err != nil {
 			return cfg, err
 		}
-		if queueSize <= 0 {
-			return cfg, errors.New("invalid queue_size value")
+		expr, err := parseFilter(kv.Get(Filter))
+		if err != nil {
+			return cfg, err
+		}
+		requestIDHeader, err := parseRequestIDHeader(kv.Get(RequestIDHeader))
+		if err != nil {
+			return cfg, err
+		}
+		priorityExpr, err := parseFilter(kv.Get(PriorityFilter))
+		if err != nil {
+			return cfg, err
+		}
+		apiFilter, err := parseAPIFilter(kv.Get(APIFilter))
+		if err != nil {
+			return cfg, err
+		}
+		dropMissingAPI, err := config.ParseBool(kv.Get(APIFilterDrop))
+		if err != nil {
+			return cfg, err
+		}
+		pinnedCerts, err := parsePinnedCerts(kv.Get(PinnedCerts))
+		if err != nil {
+			return cfg, err
+		}
+		ordering, err := parseOrdering(kv.Get(Ordering), kv.Get(PriorityFilter))
+		if err != nil {
+			return cfg, err
+		}
+		backpressureHeader, err := parseBackpressureHeader(kv.Get(Backpressure))
+		if err != nil {
+			return cfg, err
+		}
+		format, err := parseFormat(kv.Get(Format), kv.Get(CEFFields))
+		if err != nil {
+			return cfg, err
+		}
+		allowInsecureAuth, err := config.ParseBool(kv.Get(AllowInsecureAuth))
+		if err != nil {
+			return cfg, err
+		}
+		followRedirects, err := config.ParseBool(kv.Get(FollowRedirects))
+		if err != nil {
+			return cfg, err
+		}
+		if err = checkInsecureAuth(kv.Get(Endpoint), kv.Get(AuthToken), allowInsecureAuth); err != nil {
+			return cfg, err
 		}
-
 		cfg.AuditWebhook[starget] = http.Config{
-			Enabled:    true,
-			Endpoint:   kv.Get(Endpoint),
-			AuthToken:  kv.Get(AuthToken),
-			ClientCert: kv.Get(ClientCert),
-			ClientKey:  kv.Get(ClientKey),
-			QueueSize:  queueSize,
+			Enabled:         true,
+			Endpoint:        kv.Get(Endpoint),
+			AuthToken:       kv.Get(AuthToken),
+			ClientCert:      kv.Get(ClientCert),
+			ClientKey:       kv.Get(ClientKey),
+			QueueSize:       queueSize,
+			Filter:          expr,
+			RequestIDHeader: requestIDHeader,
+			Format:          format,
+			CEFFields:       kv.Get(CEFFields),
+			PriorityFilter:  priorityExpr,
+			APIFilter:       apiFilter,
+			DropMissingAPI:  dropMissingAPI,
+			OrderingMode:    ordering,
+
+			PinnedServerCertSHA256: pinnedCerts,
+			AllowInsecureAuth:      allowInsecureAuth,
+			BackpressureHeader:     backpressureHeader,
+			DisableRedirects:       !followRedirects,
+		}
+	}
+
+	return cfg, nil
+}
+
+// lookupLoggerFileConfig - loads the file logger targets from the environment,
+// MINIO_LOGGER_FILE_PATH[_<target>] enables them unless explicitly disabled.
+func lookupLoggerFileConfig(cfg Config) (Config, error) {
+	for _, k := range env.List(EnvLoggerFilePath) {
+		target := strings.TrimPrefix(k, EnvLoggerFilePath+config.Default)
+		if target == EnvLoggerFilePath {
+			target = config.Default
+		}
+		enable, err := getBoolCfg(EnvLoggerFileEnable, target, config.EnableOn)
+		if err != nil {
+			return cfg, err
+		}
+		if !enable {
+			continue
+		}
+		maxSize, err := getIntCfg(EnvLoggerFileMaxSize, target, "0")
+		if err != nil {
+			return cfg, err
+		}
+		maxFiles, err := getIntCfg(EnvLoggerFileMaxFiles, target, "0")
+		if err != nil {
+			return cfg, err
+		}
+		syncInterval, err := getDurationCfg(EnvLoggerFileSyncInterval, target, "0s")
+		if err != nil {
+			return cfg, err
+		}
+		cfg.File[target] = file.Config{
+			Enabled:      true,
+			Name:         target,
+			Path:         getCfgVal(EnvLoggerFilePath, target, ""),
+			MaxSize:      int64(maxSize),
+			MaxFiles:     maxFiles,
+			SyncInterval: syncInterval,
+		}
+	}
+	return cfg, nil
+}
+
+// lookupLoggerLokiConfig - loads the Loki logger targets from the environment,
+// MINIO_LOGGER_LOKI_ENDPOINT[_<target>] enables them unless explicitly disabled.
+func lookupLoggerLokiConfig(cfg Config) (Config, error) {
+	for _, k := range env.List(EnvLoggerLokiEndpoint) {
+		target := strings.TrimPrefix(k, EnvLoggerLokiEndpoint+config.Default)
+		if target == EnvLoggerLokiEndpoint {
+			target = config.Default
+		}
+		enable, err := getBoolCfg(EnvLoggerLokiEnable, target, config.EnableOn)
+		if err != nil {
+			return cfg, err
+		}
+		if !enable {
+			continue
+		}
+		labels, err := parseLabels(getCfgVal(EnvLoggerLokiLabels, target, ""))
+		if err != nil {
+			return cfg, err
+		}
+		queueSize, err := getQueueSizeCfg(EnvLoggerLokiQueueSize, target, defaultQueueSize)
+		if err != nil {
+			return cfg, err
+		}
+		batchSize, err := getIntCfg(EnvLoggerLokiBatchSize, target, "0")
+		if err != nil {
+			return cfg, err
 		}
+		batchInterval, err := getDurationCfg(EnvLoggerLokiBatchInterval, target, "0s")
+		if err != nil {
+			return cfg, err
+		}
+		lokiCfg := loki.Config{
+			Enabled:       true,
+			Name:          target,
+			Endpoint:      getCfgVal(EnvLoggerLokiEndpoint, target, ""),
+			Labels:        labels,
+			Username:      getCfgVal(EnvLoggerLokiUsername, target, ""),
+			Password:      getCfgVal(EnvLoggerLokiPassword, target, ""),
+			BearerToken:   getCfgVal(EnvLoggerLokiBearerToken, target, ""),
+			TenantID:      getCfgVal(EnvLoggerLokiTenantID, target, ""),
+			QueueSize:     queueSize,
+			BatchSize:     batchSize,
+			BatchInterval: batchInterval,
+		}
+		if err = lokiCfg.Validate(); err != nil {
+			return cfg, config.Errorf("loki target %s: %v", target, err)
+		}
+		cfg.Loki[target] = lokiCfg
 	}
+	return cfg, nil
+}
 
+// lookupLoggerWebSocketConfig - loads the WebSocket logger targets from the environment,
+// MINIO_LOGGER_WEBSOCKET_ENDPOINT[_<target>] enables them unless explicitly disabled.
+func lookupLoggerWebSocketConfig(cfg Config) (Config, error) {
+	for _, k := range env.List(EnvLoggerWebSocketEndpoint) {
+		target := strings.TrimPrefix(k, EnvLoggerWebSocketEndpoint+config.Default)
+		if target == EnvLoggerWebSocketEndpoint {
+			target = config.Default
+		}
+		enable, err := getBoolCfg(EnvLoggerWebSocketEnable, target, config.EnableOn)
+		if err != nil {
+			return cfg, err
+		}
+		if !enable {
+			continue
+		}
+		queueSize, err := getQueueSizeCfg(EnvLoggerWebSocketQueueSize, target, defaultQueueSize)
+		if err != nil {
+			return cfg, err
+		}
+		wsCfg := websocket.Config{
+			Enabled:   true,
+			Name:      target,
+			Endpoint:  getCfgVal(EnvLoggerWebSocketEndpoint, target, ""),
+			AuthToken: getCfgVal(EnvLoggerWebSocketAuthToken, target, ""),
+			QueueSize: queueSize,
+		}
+		if err = wsCfg.Validate(); err != nil {
+			return cfg, config.Errorf("websocket target %s: %v", target, err)
+		}
+		cfg.WebSocket[target] = wsCfg
+	}
+	return cfg, nil
+}
+
+// parseLabels parses a comma separated list of name=value labels.
+func parseLabels(value string) (map[string]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+	labels := make(map[string]string)
+	for _, label := range strings.Split(value, config.ValueSeparator) {
+		kv := strings.SplitN(label, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+			return nil, config.Errorf("invalid label %q, expected name=value", label)
+		}
+		labels[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+	return labels, nil
+}
+
+// lookupAuditSplunkConfig - loads the Splunk audit targets from the environment,
+// MINIO_AUDIT_SPLUNK_URL[_<target>] enables them unless explicitly disabled.
+func lookupAuditSplunkConfig(cfg Config) (Config, error) {
+	for _, k := range env.List(EnvAuditSplunkURL) {
+		target := strings.TrimPrefix(k, EnvAuditSplunkURL+config.Default)
+		if target == EnvAuditSplunkURL {
+			target = config.Default
+		}
+		enable, err := getBoolCfg(EnvAuditSplunkEnable, target, config.EnableOn)
+		if err != nil {
+			return cfg, err
+		}
+		if !enable {
+			continue
+		}
+		queueSize, err := getQueueSizeCfg(EnvAuditSplunkQueueSize, target, defaultQueueSize)
+		if err != nil {
+			return cfg, err
+		}
+		batchInterval, err := getDurationCfg(EnvAuditSplunkBatchInterval, target, "0s")
+		if err != nil {
+			return cfg, err
+		}
+		splunkCfg := splunk.Config{
+			Enabled:       true,
+			Name:          target,
+			URL:           getCfgVal(EnvAuditSplunkURL, target, ""),
+			Token:         getCfgVal(EnvAuditSplunkToken, target, ""),
+			Index:         getCfgVal(EnvAuditSplunkIndex, target, ""),
+			SourceType:    getCfgVal(EnvAuditSplunkSourceType, target, ""),
+			Source:        getCfgVal(EnvAuditSplunkSource, target, ""),
+			QueueSize:     queueSize,
+			BatchInterval: batchInterval,
+		}
+		if err = splunkCfg.Validate(); err != nil {
+			return cfg, config.Errorf("splunk target %s: %v", target, err)
+		}
+		cfg.AuditSplunk[target] = splunkCfg
+	}
 	return cfg, nil
 }
 
@@ -629,22 +1451,149 @@ func LookupConfigForSubSys(scfg config.Config, subSys string) (cfg Config, err e
 		if cfg, err = lookupLoggerWebhookConfig(scfg, cfg); err != nil {
 			return cfg, err
 		}
+		if cfg, err = lookupLoggerFileConfig(cfg); err != nil {
+			return cfg, err
+		}
+		if cfg, err = lookupLoggerLokiConfig(cfg); err != nil {
+			return cfg, err
+		}
+		if cfg, err = lookupLoggerWebSocketConfig(cfg); err != nil {
+			return cfg, err
+		}
+		if err = checkFilePaths(cfg.File); err != nil {
+			return cfg, err
+		}
+		names := targetNames{}
+		for target, l := range cfg.HTTP {
+			names.add("webhook", target, l.Enabled)
+		}
+		for target, l := range cfg.File {
+			names.add("file", target, l.Enabled)
+		}
+		for target, l := range cfg.Loki {
+			names.add("loki", target, l.Enabled)
+		}
+		for target, l := range cfg.WebSocket {
+			names.add("websocket", target, l.Enabled)
+		}
+		if err = names.check(); err != nil {
+			return Config{}, err
+		}
+		if err = checkMaxTargets(subSys, len(names)); err != nil {
+			return Config{}, err
+		}
 	case config.AuditWebhookSubSys:
 		cfg = lookupLegacyConfigForSubSys(config.AuditWebhookSubSys)
 		if cfg, err = lookupAuditWebhookConfig(scfg, cfg); err != nil {
 			return cfg, err
 		}
+		if cfg, err = lookupAuditSplunkConfig(cfg); err != nil {
+			return cfg, err
+		}
+		names := targetNames{}
+		for target, l := range cfg.AuditWebhook {
+			names.add("webhook", target, l.Enabled)
+		}
+		for target, l := range cfg.AuditSplunk {
+			names.add("splunk", target, l.Enabled)
+		}
+		if err = names.check(); err != nil {
+			return Config{}, err
+		}
+		if err = checkMaxTargets(subSys, len(names)); err != nil {
+			return Config{}, err
+		}
 	case config.AuditKafkaSubSys:
-		if _, err = GetAuditKafka(scfg[config.AuditKafkaSubSys]); err != nil {
+		cfg = NewConfig()
+		if cfg.AuditKafka, err = GetAuditKafka(scfg[config.AuditKafkaSubSys]); err != nil {
+			return cfg, err
+		}
+		var n int
+		for _, l := range cfg.AuditKafka {
+			if l.Enabled {
+				n++
+			}
+		}
+		if err = checkMaxTargets(subSys, n); err != nil {
+			return Config{}, err
+		}
+	case config.AuditAMQPSubSys:
+		cfg = NewConfig()
+		if cfg.AuditAMQP, err = lookupAuditAMQPConfig(scfg[config.AuditAMQPSubSys]); err != nil {
 			return cfg, err
 		}
+		var n int
+		for _, l := range cfg.AuditAMQP {
+			if l.Enabled {
+				n++
+			}
+		}
+		if err = checkMaxTargets(subSys, n); err != nil {
+			return Config{}, err
+		}
 	}
 	return cfg, nil
 }
 
-// ValidateSubSysConfig - validates logger related config of given sub-system
-func ValidateSubSysConfig(scfg config.Config, subSys string) error {
+// targetNames - kinds of the enabled targets of a sub-system by name.
+type targetNames map[string][]string
+
+func (n targetNames) add(kind, target string, enabled bool) {
+	if enabled {
+		n[target] = append(n[target], kind)
+	}
+}
+
+// check - returns an error if targets of different kinds share a name,
+// they could not be told apart once running.
+func (n targetNames) check() error {
+	targets := make([]string, 0, len(n))
+	for target, kinds := range n {
+		if len(kinds) > 1 {
+			targets = append(targets, target)
+		}
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+	sort.Strings(targets)
+	kinds := n[targets[0]]
+	sort.Strings(kinds)
+	return config.Errorf("target name %s is used by the %s targets, target names must be unique",
+		targets[0], strings.Join(kinds, " and "))
+}
+
+// checkFilePaths - returns an error if enabled file targets share a path,
+// they would otherwise write and rotate each other's files.
+func checkFilePaths(targets map[string]file.Config) error {
+	paths := make(map[string]string, len(targets))
+	for target, l := range targets {
+		if !l.Enabled {
+			continue
+		}
+		path, err := filepath.Abs(l.Path)
+		if err != nil {
+			return config.Errorf("file target %s: %v", target, err)
+		}
+		if other, ok := paths[path]; ok {
+			if other > target {
+				other, target = target, other
+			}
+			return config.Errorf("file targets %s and %s are both set to the path %s", other, target, l.Path)
+		}
+		paths[path] = target
+	}
+	return nil
+}
+
+// ValidateSubSysConfig - validates logger related config of given sub-system,
+// along with the warnings of its targets if withWarnings is set, see
+// ConfigWarning. Warnings never fail the validation.
+func ValidateSubSysConfig(scfg config.Config, subSys string, withWarnings bool) ([]ConfigWarning, error) {
 	// Lookup for legacy environment variables first
-	_, err := LookupConfigForSubSys(scfg, subSys)
-	return err
+	cfg, err := LookupConfigForSubSys(scfg, subSys)
+	if err != nil || !withWarnings {
+		return nil, err
+	}
+	return configWarnings(scfg, cfg, subSys)
 }
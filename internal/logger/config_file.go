@@ -0,0 +1,143 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/minio/minio/internal/config"
+	"github.com/minio/minio/internal/logger/target/http"
+)
+
+// Environment variables pointing to a file with webhook target definitions.
+const (
+	EnvLoggerWebhookConfigFile = "MINIO_LOGGER_WEBHOOK_CONFIG_FILE"
+	EnvAuditWebhookConfigFile  = "MINIO_AUDIT_WEBHOOK_CONFIG_FILE"
+)
+
+// webhookFileTarget - webhook target as defined in a config file,
+// keys are the same as the ones of the logger/audit webhook sub-systems.
+type webhookFileTarget struct {
+	Enable          *bool  `json:"enable" yaml:"enable"`
+	Endpoint        string `json:"endpoint" yaml:"endpoint"`
+	AuthToken       string `json:"auth_token" yaml:"auth_token"`
+	ClientCert      string `json:"client_cert" yaml:"client_cert"`
+	ClientKey       string `json:"client_key" yaml:"client_key"`
+	QueueSize       int    `json:"queue_size" yaml:"queue_size"`
+	Proxy           string `json:"proxy" yaml:"proxy"`
+	NoProxy         string `json:"no_proxy" yaml:"no_proxy"`
+	Filter          string `json:"filter" yaml:"filter"`
+	RequestIDHeader string `json:"request_id_header" yaml:"request_id_header"`
+
+	AllowInsecureAuth bool  `json:"allow_insecure_auth" yaml:"allow_insecure_auth"`
+	FollowRedirects   *bool `json:"follow_redirects" yaml:"follow_redirects"`
+}
+
+// lookupWebhookConfigFile - loads the webhook targets defined in the
+// JSON or YAML file at path, a mapping of target names to their config.
+// Files with a `.json` extension are parsed as JSON, anything else as YAML.
+//
+// Targets found in the file take precedence over the ones stored in the
+// server config but not over the ones set in the environment, targets
+// already enabled in targets are hence left untouched.
+func lookupWebhookConfigFile(path string, targets map[string]http.Config) error {
+	fileTargets, err := readWebhookConfigFile(path)
+	if err != nil {
+		return err
+	}
+
+	for target, t := range fileTargets {
+		if v, ok := targets[target]; ok && v.Enabled {
+			// This target is already enabled using the
+			// environment variables, ignore.
+			continue
+		}
+		if t.Enable != nil && !*t.Enable {
+			continue
+		}
+		if t.Endpoint == "" {
+			return config.Errorf("webhook target %s: missing endpoint", target)
+		}
+		if err = config.EnsureCertAndKey(t.ClientCert, t.ClientKey); err != nil {
+			return err
+		}
+		if t.Proxy != "" {
+			if _, err = http.ValidateProxy(t.Proxy); err != nil {
+				return config.Errorf("webhook target %s: %v", target, err)
+			}
+		}
+		if _, err = parseFilter(t.Filter); err != nil {
+			return config.Errorf("webhook target %s: %v", target, err)
+		}
+		if _, err = parseRequestIDHeader(t.RequestIDHeader); err != nil {
+			return config.Errorf("webhook target %s: %v", target, err)
+		}
+		if err = checkInsecureAuth(t.Endpoint, t.AuthToken, t.AllowInsecureAuth); err != nil {
+			return config.Errorf("webhook target %s: %v", target, err)
+		}
+		if t.QueueSize == 0 {
+			t.QueueSize = 100000
+		}
+		if t.QueueSize < 0 {
+			return config.Errorf("webhook target %s: invalid queue_size value", target)
+		}
+		targets[target] = http.Config{
+			Enabled:         true,
+			Endpoint:        t.Endpoint,
+			AuthToken:       t.AuthToken,
+			ClientCert:      t.ClientCert,
+			ClientKey:       t.ClientKey,
+			QueueSize:       t.QueueSize,
+			Proxy:           t.Proxy,
+			NoProxy:         t.NoProxy,
+			Filter:          t.Filter,
+			RequestIDHeader: t.RequestIDHeader,
+
+			AllowInsecureAuth: t.AllowInsecureAuth,
+			DisableRedirects:  t.FollowRedirects != nil && !*t.FollowRedirects,
+		}
+	}
+	return nil
+}
+
+// readWebhookConfigFile - parses the webhook targets defined in the
+// JSON or YAML file at path.
+func readWebhookConfigFile(path string) (fileTargets map[string]webhookFileTarget, err error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, config.Errorf("unable to read webhook config file: %v", err)
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&fileTargets)
+	} else {
+		err = yaml.UnmarshalStrict(data, &fileTargets)
+	}
+	if err != nil {
+		return nil, config.Errorf("unable to parse webhook config file %s: %v", path, err)
+	}
+	return fileTargets, nil
+}
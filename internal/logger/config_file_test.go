@@ -0,0 +1,94 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package logger
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/minio/minio/internal/logger/target/http"
+)
+
+func TestLookupWebhookConfigFile(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+	}{
+		{"targets.yaml", `
+target1:
+  endpoint: http://file/target1
+  auth_token: token
+target2:
+  endpoint: http://file/target2
+  queue_size: 10
+target3:
+  enable: off
+  endpoint: http://file/target3
+`},
+		{"targets.json", `{
+  "target1": {"endpoint": "http://file/target1", "auth_token": "token"},
+  "target2": {"endpoint": "http://file/target2", "queue_size": 10},
+  "target3": {"enable": false, "endpoint": "http://file/target3"}
+}`},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), testCase.name)
+			if err := ioutil.WriteFile(path, []byte(testCase.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			// target1 is already enabled from the environment.
+			targets := map[string]http.Config{
+				"target1": {Enabled: true, Endpoint: "http://env/target1", QueueSize: 100},
+			}
+			if err := lookupWebhookConfigFile(path, targets); err != nil {
+				t.Fatal(err)
+			}
+			if len(targets) != 2 {
+				t.Fatalf("expected 2 targets, got %d", len(targets))
+			}
+			if targets["target1"].Endpoint != "http://env/target1" {
+				t.Errorf("expected environment target to take precedence, got %s", targets["target1"].Endpoint)
+			}
+			if target2 := targets["target2"]; !target2.Enabled || target2.Endpoint != "http://file/target2" || target2.QueueSize != 10 {
+				t.Errorf("unexpected target2 config %#v", target2)
+			}
+		})
+	}
+}
+
+func TestLookupWebhookConfigFileInvalid(t *testing.T) {
+	testCases := []string{
+		"target1:\n  endpoint: http://file/target1\n  unknown: value\n",
+		"target1:\n  auth_token: token\n",
+		"target1:\n  endpoint: http://file/target1\n  queue_size: -1\n",
+		"target1:\n  endpoint: http://file/target1\n  proxy: ftp://proxy:21\n",
+		"target1:\n  endpoint: http://file/target1\n  proxy: http://\n",
+	}
+	for i, content := range testCases {
+		path := filepath.Join(t.TempDir(), "targets.yaml")
+		if err := ioutil.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := lookupWebhookConfigFile(path, map[string]http.Config{}); err == nil {
+			t.Errorf("Test %d: expected an error", i+1)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// # This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package logger
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/minio/pkg/env"
+
+	"github.com/minio/minio/internal/config"
+)
+
+// Sources of the effective config values, see EffectiveConfig.
+const (
+	SourceLegacyEnv  = "legacy-env"
+	SourceEnv        = "env"
+	SourceConfigFile = "config-file"
+	SourceStore      = "store"
+	SourceDefault    = "default"
+)
+
+// EffectiveKV - value of a config key in effect for a target,
+// along with where it comes from.
+type EffectiveKV struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// webhookEnvs - environment variables of the webhook keys, as read
+// by lookupLoggerWebhookConfig and lookupAuditWebhookConfig.
+var (
+	loggerWebhookEnvs = map[string]string{
+		config.Enable:   EnvLoggerWebhookEnable,
+		Endpoint:        EnvLoggerWebhookEndpoint,
+		AuthToken:       EnvLoggerWebhookAuthToken,
+		ClientCert:      EnvLoggerWebhookClientCert,
+		ClientKey:       EnvLoggerWebhookClientKey,
+		QueueSize:       EnvAuditWebhookQueueSize,
+		Filter:          EnvLoggerWebhookFilter,
+		RequestIDHeader: EnvLoggerWebhookRequestIDHeader,
+
+		AllowInsecureAuth: EnvLoggerWebhookAllowInsecureAuth,
+		FollowRedirects:   EnvLoggerWebhookFollowRedirects,
+	}
+
+	auditWebhookEnvs = map[string]string{
+		config.Enable:   EnvAuditWebhookEnable,
+		Endpoint:        EnvAuditWebhookEndpoint,
+		AuthToken:       EnvAuditWebhookAuthToken,
+		ClientCert:      EnvAuditWebhookClientCert,
+		ClientKey:       EnvAuditWebhookClientKey,
+		QueueSize:       EnvAuditWebhookQueueSize,
+		Filter:          EnvAuditWebhookFilter,
+		RequestIDHeader: EnvAuditWebhookRequestIDHeader,
+		Format:          EnvAuditWebhookFormat,
+		CEFFields:       EnvAuditWebhookCEFFields,
+		PriorityFilter:  EnvAuditWebhookPriorityFilter,
+		APIFilter:       EnvAuditWebhookAPIFilter,
+		APIFilterDrop:   EnvAuditWebhookAPIFilterDrop,
+		PinnedCerts:     EnvAuditWebhookPinnedCerts,
+		Ordering:        EnvAuditWebhookOrdering,
+		Backpressure:    EnvAuditWebhookBackpressure,
+
+		AllowInsecureAuth: EnvAuditWebhookAllowInsecureAuth,
+		FollowRedirects:   EnvAuditWebhookFollowRedirects,
+	}
+)
+
+// EffectiveConfig - returns the config in effect for each enabled
+// webhook target of subSys, the logger or audit webhook sub-system,
+// with the source of each value: the legacy environment variables,
+// the environment variables, the config file, the config store or
+// the defaults, going by the precedence of LookupConfigForSubSys.
+// Secrets are redacted.
+func EffectiveConfig(scfg config.Config, subSys string) (map[string][]EffectiveKV, error) {
+	var (
+		defaults   config.KVS
+		envs       map[string]string
+		configFile string
+	)
+	switch subSys {
+	case config.LoggerWebhookSubSys:
+		defaults, envs = DefaultLoggerWebhookKVS, loggerWebhookEnvs
+		configFile = env.Get(EnvLoggerWebhookConfigFile, "")
+	case config.AuditWebhookSubSys:
+		defaults, envs = DefaultAuditWebhookKVS, auditWebhookEnvs
+		configFile = env.Get(EnvAuditWebhookConfigFile, "")
+	default:
+		return nil, config.Errorf("effective config is not supported for %s", subSys)
+	}
+
+	resolved, err := LookupConfigForSubSys(scfg, subSys)
+	if err != nil {
+		return nil, err
+	}
+	legacyCfg := lookupLegacyConfigForSubSys(subSys)
+	targets, legacy := resolved.HTTP, legacyCfg.HTTP
+	if subSys == config.AuditWebhookSubSys {
+		targets, legacy = resolved.AuditWebhook, legacyCfg.AuditWebhook
+	}
+
+	var fileTargets map[string]webhookFileTarget
+	if configFile != "" {
+		if fileTargets, err = readWebhookConfigFile(configFile); err != nil {
+			return nil, err
+		}
+	}
+
+	effective := make(map[string][]EffectiveKV, len(targets))
+	for target, t := range targets {
+		if !t.Enabled {
+			continue
+		}
+		var kvs []EffectiveKV
+		if l, ok := legacy[target]; ok && l.Enabled {
+			// Legacy targets only set their endpoint.
+			kvs = []EffectiveKV{
+				{Key: config.Enable, Value: config.EnableOn, Source: SourceLegacyEnv},
+				{Key: Endpoint, Value: l.Endpoint, Source: SourceLegacyEnv},
+			}
+		} else if enabled, err := getBoolCfg(envs[config.Enable], target, ""); err == nil && enabled && env.IsSet(envName(envs[Endpoint], target)) {
+			kvs = effectiveKVs(defaults, SourceEnv, func(key string) (string, bool) {
+				name, ok := envs[key]
+				if !ok || !env.IsSet(envName(name, target)) {
+					return "", false
+				}
+				return getCfgVal(name, target, ""), true
+			})
+		} else if ft, ok := fileTargets[target]; ok && (ft.Enable == nil || *ft.Enable) {
+			kvs = effectiveKVs(defaults, SourceConfigFile, ft.lookup)
+			for _, key := range []string{"proxy", "no_proxy"} {
+				if value, ok := ft.lookup(key); ok {
+					kvs = append(kvs, EffectiveKV{Key: key, Value: value, Source: SourceConfigFile})
+				}
+			}
+		} else {
+			kv := scfg[subSys][target]
+			kvs = effectiveKVs(defaults, SourceStore, func(key string) (string, bool) {
+				value, ok := kv.Lookup(key)
+				return value, ok && value != defaults.Get(key)
+			})
+		}
+		for i := range kvs {
+			if kvs[i].Key == AuthToken && kvs[i].Value != "" {
+				kvs[i].Value = "*REDACTED*"
+			}
+		}
+		sort.Slice(kvs, func(i, j int) bool { return kvs[i].Key < kvs[j].Key })
+		effective[target] = kvs
+	}
+	return effective, nil
+}
+
+// effectiveKVs - returns the value of each key of defaults found by
+// lookup, attributed to source, or its default value otherwise.
+func effectiveKVs(defaults config.KVS, source string, lookup func(key string) (string, bool)) []EffectiveKV {
+	kvs := make([]EffectiveKV, 0, len(defaults))
+	for _, d := range defaults {
+		if value, ok := lookup(d.Key); ok {
+			kvs = append(kvs, EffectiveKV{Key: d.Key, Value: value, Source: source})
+			continue
+		}
+		kvs = append(kvs, EffectiveKV{Key: d.Key, Value: d.Value, Source: SourceDefault})
+	}
+	return kvs
+}
+
+// envName - returns the name of the environment variable for target,
+// suffixed with the target name unless it is the default one.
+func envName(name, target string) string {
+	if target != config.Default {
+		return name + config.Default + target
+	}
+	return name
+}
+
+// lookup - returns the value of key set in the config file.
+func (t webhookFileTarget) lookup(key string) (string, bool) {
+	var value string
+	switch key {
+	case config.Enable:
+		// Targets of the file are enabled unless disabled.
+		return config.EnableOn, true
+	case Endpoint:
+		value = t.Endpoint
+	case AuthToken:
+		value = t.AuthToken
+	case ClientCert:
+		value = t.ClientCert
+	case ClientKey:
+		value = t.ClientKey
+	case QueueSize:
+		if t.QueueSize != 0 {
+			value = strconv.Itoa(t.QueueSize)
+		}
+	case "proxy":
+		value = t.Proxy
+	case "no_proxy":
+		value = t.NoProxy
+	case Filter:
+		value = t.Filter
+	case RequestIDHeader:
+		value = t.RequestIDHeader
+	case AllowInsecureAuth:
+		if t.AllowInsecureAuth {
+			value = config.EnableOn
+		}
+	case FollowRedirects:
+		if t.FollowRedirects != nil && !*t.FollowRedirects {
+			value = config.EnableOff
+		}
+	}
+	return value, value != ""
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package logger
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/minio/minio/internal/config"
+)
+
+func TestEffectiveConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "targets.yaml")
+	if err := ioutil.WriteFile(path, []byte(`
+file:
+  endpoint: http://file/file
+  queue_size: 10
+  follow_redirects: false
+env:
+  endpoint: http://file/env
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	envs := map[string]string{
+		"MINIO_AUDIT_LOGGER_HTTP_ENDPOINT_legacy":  "http://legacy/legacy",
+		"MINIO_AUDIT_WEBHOOK_ENABLE_env":           "on",
+		"MINIO_AUDIT_WEBHOOK_ENDPOINT_env":         "http://env/env",
+		"MINIO_AUDIT_WEBHOOK_FORMAT_env":           "cef",
+		"MINIO_AUDIT_WEBHOOK_FOLLOW_REDIRECTS_env": "off",
+		"MINIO_AUDIT_WEBHOOK_CONFIG_FILE":          path,
+	}
+	for k, v := range envs {
+		os.Setenv(k, v)
+	}
+	defer func() {
+		for k := range envs {
+			os.Unsetenv(k)
+		}
+	}()
+
+	// Stored targets hold every key.
+	store := DefaultAuditWebhookKVS.Clone()
+	store.Set(config.Enable, config.EnableOn)
+	store.Set(Endpoint, "http://store/store")
+	store.Set(AuthToken, "secret")
+	store.Set(AllowInsecureAuth, config.EnableOn)
+	env := DefaultAuditWebhookKVS.Clone()
+	env.Set(config.Enable, config.EnableOn)
+	env.Set(Endpoint, "http://store/env")
+	scfg := config.Config{
+		config.AuditWebhookSubSys: map[string]config.KVS{
+			"store": store,
+			"env":   env,
+		},
+	}
+	effective, err := EffectiveConfig(scfg, config.AuditWebhookSubSys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		target, key, value, source string
+	}{
+		{"legacy", Endpoint, "http://legacy/legacy", SourceLegacyEnv},
+		{"env", Endpoint, "http://env/env", SourceEnv},
+		{"env", Format, "cef", SourceEnv},
+		{"env", Filter, "", SourceDefault},
+		{"file", Endpoint, "http://file/file", SourceConfigFile},
+		{"file", QueueSize, "10", SourceConfigFile},
+		{"file", Format, "json", SourceDefault},
+		{"file", FollowRedirects, config.EnableOff, SourceConfigFile},
+		{"store", Endpoint, "http://store/store", SourceStore},
+		{"store", AuthToken, "*REDACTED*", SourceStore},
+		{"store", QueueSize, "100000", SourceDefault},
+		{"store", FollowRedirects, config.EnableOn, SourceDefault},
+		{"env", FollowRedirects, config.EnableOff, SourceEnv},
+	}
+	for _, testCase := range testCases {
+		var found bool
+		for _, kv := range effective[testCase.target] {
+			if kv.Key != testCase.key {
+				continue
+			}
+			found = true
+			if kv.Value != testCase.value || kv.Source != testCase.source {
+				t.Errorf("%s %s: expected %q from %s, got %q from %s", testCase.target, testCase.key,
+					testCase.value, testCase.source, kv.Value, kv.Source)
+			}
+		}
+		if !found {
+			t.Errorf("%s %s: not found in %v", testCase.target, testCase.key, effective[testCase.target])
+		}
+	}
+	if len(effective) != 4 {
+		t.Errorf("expected 4 targets, got %d", len(effective))
+	}
+
+	cfg, err := LookupConfigForSubSys(scfg, config.AuditWebhookSubSys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for target, disabled := range map[string]bool{"env": true, "file": true, "store": false} {
+		if cfg.AuditWebhook[target].DisableRedirects != disabled {
+			t.Errorf("%s: expected redirects disabled to be %v", target, disabled)
+		}
+	}
+
+	if _, err = EffectiveConfig(scfg, config.AuditKafkaSubSys); err == nil {
+		t.Error("expected an unsupported sub-system to be rejected")
+	}
+}
@@ -247,6 +247,8 @@ const (
 	Application Kind = "APPLICATION"
 	// All errors
 	All Kind = "ALL"
+	// Telemetry entries, see StartTelemetry
+	Telemetry Kind = "TELEMETRY"
 )
 
 // LogAlwaysIf prints a detailed error message during
@@ -360,8 +362,11 @@ func logIf(ctx context.Context, err error, errKind ...interface{}) {
 	// Iterate over all logger targets to send the log entry
 	for _, t := range SystemTargets() {
 		if err := t.Send(entry, entry.LogKind); err != nil {
-			if consoleTgt != nil {
-				entry.Trace.Message = fmt.Sprintf("event(%#v) was not sent to Logger target (%#v): %#v", entry, t, err)
+			if consoleTgt == nil {
+				continue
+			}
+			if ok, suppressed := targetSendFailures.allow(t, err, time.Now()); ok {
+				entry.Trace.Message = fmt.Sprintf("event(%#v) was not sent to Logger target (%#v): %#v%s", entry, t, err, suppressedSuffix(suppressed))
 				consoleTgt.Send(entry, entry.LogKind)
 			}
 		}
@@ -35,6 +35,12 @@ type ObjectVersion struct {
 	VersionID  string `json:"versionId,omitempty"`
 }
 
+// TLSClientCert - certificate a client authenticated the TLS connection of a request with.
+type TLSClientCert struct {
+	Subject string `json:"subject"`
+	Issuer  string `json:"issuer"`
+}
+
 // Entry - audit entry logs.
 type Entry struct {
 	Version      string    `json:"version"`
@@ -56,6 +62,7 @@ type Entry struct {
 	RemoteHost string                 `json:"remotehost,omitempty"`
 	RequestID  string                 `json:"requestID,omitempty"`
 	UserAgent  string                 `json:"userAgent,omitempty"`
+	TLSClient  *TLSClientCert         `json:"tlsClientCert,omitempty"`
 	ReqClaims  map[string]interface{} `json:"requestClaims,omitempty"`
 	ReqQuery   map[string]string      `json:"requestQuery,omitempty"`
 	ReqHeader  map[string]string      `json:"requestHeader,omitempty"`
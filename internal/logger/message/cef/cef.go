@@ -0,0 +1,196 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package cef formats log and audit entries in the ArcSight Common
+// Event Format (CEF), for SIEMs not ingesting JSON.
+package cef
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/buger/jsonparser"
+)
+
+// Header fields identifying MinIO as the device.
+const (
+	deviceVendor  = "MinIO"
+	deviceProduct = "MinIO"
+)
+
+// DefaultFields is the extension mapping of audit entries
+// used unless another one is configured.
+const DefaultFields = "rt=time,src=remotehost,suser=requestClaims.accessKey,requestClientApplication=userAgent," +
+	"externalId=requestID,cs1=api.bucket,cs2=api.object,cn1=api.statusCode,in=api.rx,out=api.tx"
+
+// Valid extension keys
+var keyRegexp = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9]*$`)
+
+// Custom extensions, labeled by a <key>Label extension.
+var customKeyRegexp = regexp.MustCompile(`^(cs[1-6]|cn[1-3]|cfp[1-4]|flexString[1-2])$`)
+
+// Field maps the entry field at Path to the Key extension.
+type Field struct {
+	Key  string
+	Path []string
+}
+
+// ParseFields parses a comma separated list of key=path extension
+// mappings, path being the dot separated path of an entry field.
+func ParseFields(value string) ([]Field, error) {
+	var fields []Field
+	for _, mapping := range strings.Split(value, ",") {
+		kv := strings.SplitN(mapping, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid cef field %q, expected key=path", mapping)
+		}
+		key, path := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		if !keyRegexp.MatchString(key) || path == "" {
+			return nil, fmt.Errorf("invalid cef field %q, expected key=path", mapping)
+		}
+		fields = append(fields, Field{Key: key, Path: strings.Split(path, ".")})
+	}
+	return fields, nil
+}
+
+// Formatter formats JSON encoded entries as CEF events.
+type Formatter struct {
+	version string
+	fields  []Field
+	// Labels of the custom extensions, by key.
+	labels map[string]string
+}
+
+// NewFormatter returns a Formatter reporting version as the device
+// version and mapping fields to extensions, DefaultFields if none.
+func NewFormatter(version string, fields []Field) *Formatter {
+	if len(fields) == 0 {
+		fields, _ = ParseFields(DefaultFields)
+	}
+	// Label the custom extensions after their field unless
+	// a label is mapped explicitly.
+	mapped := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		mapped[field.Key] = true
+	}
+	labels := make(map[string]string)
+	for _, field := range fields {
+		if customKeyRegexp.MatchString(field.Key) && !mapped[field.Key+"Label"] {
+			labels[field.Key] = field.Path[len(field.Path)-1]
+		}
+	}
+	return &Formatter{version: version, fields: fields, labels: labels}
+}
+
+// Format returns the CEF event of a JSON encoded entry. The API name of
+// audit entries is the signature and name of the event, its severity
+// follows the status code of the response.
+func (f *Formatter) Format(logJSON []byte) ([]byte, error) {
+	if _, dataType, _, err := jsonparser.Get(logJSON); err != nil || dataType != jsonparser.Object {
+		return nil, fmt.Errorf("unable to format entry as cef: not a JSON object")
+	}
+
+	signature, _ := jsonparser.GetString(logJSON, "api", "name")
+	if signature == "" {
+		signature = "unknown"
+	}
+	statusCode, _ := jsonparser.GetInt(logJSON, "api", "statusCode")
+
+	var b strings.Builder
+	b.WriteString("CEF:0|")
+	for _, field := range []string{deviceVendor, deviceProduct, f.version, signature, signature} {
+		b.WriteString(escapeHeader(field))
+		b.WriteByte('|')
+	}
+	b.WriteString(strconv.Itoa(severity(statusCode)))
+	b.WriteByte('|')
+
+	sep := ""
+	extension := func(key, value string) {
+		b.WriteString(sep)
+		b.WriteString(key)
+		b.WriteByte('=')
+		b.WriteString(escapeExtension(value))
+		sep = " "
+	}
+	for _, field := range f.fields {
+		value, ok := lookup(logJSON, field)
+		if !ok {
+			continue
+		}
+		extension(field.Key, value)
+		if label, ok := f.labels[field.Key]; ok {
+			extension(field.Key+"Label", label)
+		}
+	}
+	return []byte(b.String()), nil
+}
+
+// lookup returns the value of the field as an extension value, times
+// of the rt extension in milliseconds since the epoch.
+func lookup(logJSON []byte, field Field) (string, bool) {
+	value, dataType, _, err := jsonparser.Get(logJSON, field.Path...)
+	if err != nil || dataType == jsonparser.Null {
+		return "", false
+	}
+	if dataType != jsonparser.String {
+		// Numbers and booleans as is, objects and arrays in JSON.
+		return string(value), true
+	}
+	s, err := jsonparser.ParseString(value)
+	if err != nil {
+		return "", false
+	}
+	if field.Key == "rt" {
+		if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+			s = strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10)
+		}
+	}
+	return s, s != ""
+}
+
+// severity returns the severity, from 0 to 10, of
+// an entry by the status code of its response.
+func severity(statusCode int64) int {
+	switch {
+	case statusCode >= 500:
+		return 7
+	case statusCode >= 400:
+		return 5
+	default:
+		return 3
+	}
+}
+
+// Header fields escape pipes and backslashes and
+// cannot span several lines.
+var headerEscaper = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\r\n", " ", "\n", " ", "\r", " ")
+
+func escapeHeader(s string) string {
+	return headerEscaper.Replace(s)
+}
+
+// Extension values escape equal signs, backslashes
+// and line breaks, pipes are left as is.
+var extensionEscaper = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\r\n", `\n`, "\n", `\n`, "\r", `\r`)
+
+func escapeExtension(s string) string {
+	return extensionEscaper.Replace(s)
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cef
+
+import (
+	"testing"
+)
+
+func TestParseFields(t *testing.T) {
+	testCases := []struct {
+		value     string
+		shouldErr bool
+	}{
+		{DefaultFields, false},
+		{"src=remotehost, cs1=api.bucket", false},
+		{"src", true},
+		{"src=", true},
+		{"=remotehost", true},
+		{"1src=remotehost", true},
+		{"s-rc=remotehost", true},
+	}
+	for i, testCase := range testCases {
+		_, err := ParseFields(testCase.value)
+		if testCase.shouldErr != (err != nil) {
+			t.Errorf("Test %d: expected error %v, got %v", i+1, testCase.shouldErr, err)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	testCases := []struct {
+		version  string
+		fields   string
+		entry    string
+		expected string
+	}{
+		// Default mapping of an audit entry.
+		{
+			"RELEASE.2022",
+			"",
+			`{"time":"2022-05-01T10:00:00.5Z","api":{"name":"PutObject","bucket":"photos","object":"a.jpg","statusCode":200,"rx":10,"tx":0},"remotehost":"10.0.0.1","requestID":"16F","userAgent":"aws-cli","requestClaims":{"accessKey":"minio"}}`,
+			`CEF:0|MinIO|MinIO|RELEASE.2022|PutObject|PutObject|3|rt=1651399200500 src=10.0.0.1 suser=minio requestClientApplication=aws-cli externalId=16F cs1=photos cs1Label=bucket cs2=a.jpg cs2Label=object cn1=200 cn1Label=statusCode in=10 out=0`,
+		},
+		// Missing fields are left out, server errors are severe.
+		{
+			"v1",
+			"cs1=api.bucket,src=remotehost",
+			`{"api":{"name":"ListBuckets","statusCode":503},"remotehost":null}`,
+			`CEF:0|MinIO|MinIO|v1|ListBuckets|ListBuckets|7|`,
+		},
+		// Pipes and backslashes are escaped in the header, equal
+		// signs, backslashes and line breaks in the extension.
+		{
+			`v|1\`,
+			"cs1=api.object,msg=message",
+			`{"api":{"name":"Get|Object","object":"a=b\\c|d","statusCode":404},"message":"line1\nline2\r\nline3"}`,
+			`CEF:0|MinIO|MinIO|v\|1\\|Get\|Object|Get\|Object|5|cs1=a\=b\\c|d cs1Label=object msg=line1\nline2\nline3`,
+		},
+		// Explicit labels, objects are kept in JSON.
+		{
+			"v1",
+			"cs1=tags,cs1Label=trigger",
+			`{"trigger":"incoming","tags":{"k":"v=1"}}`,
+			`CEF:0|MinIO|MinIO|v1|unknown|unknown|3|cs1={"k":"v\=1"} cs1Label=incoming`,
+		},
+	}
+	for i, testCase := range testCases {
+		var fields []Field
+		if testCase.fields != "" {
+			var err error
+			if fields, err = ParseFields(testCase.fields); err != nil {
+				t.Fatal(err)
+			}
+		}
+		event, err := NewFormatter(testCase.version, fields).Format([]byte(testCase.entry))
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if string(event) != testCase.expected {
+			t.Errorf("Test %d: expected\n%s\ngot\n%s", i+1, testCase.expected, event)
+		}
+	}
+
+	if _, err := NewFormatter("v1", nil).Format([]byte(`"entry"`)); err == nil {
+		t.Fatal("expected an entry which is not an object to be rejected")
+	}
+}
@@ -52,17 +52,27 @@ type API struct {
 
 // Entry - defines fields and values of each log entry.
 type Entry struct {
-	DeploymentID string    `json:"deploymentid,omitempty"`
-	Level        string    `json:"level"`
-	LogKind      string    `json:"errKind"`
-	Time         time.Time `json:"time"`
-	API          *API      `json:"api,omitempty"`
-	RemoteHost   string    `json:"remotehost,omitempty"`
-	Host         string    `json:"host,omitempty"`
-	RequestID    string    `json:"requestID,omitempty"`
-	UserAgent    string    `json:"userAgent,omitempty"`
-	Message      string    `json:"message,omitempty"`
-	Trace        *Trace    `json:"error,omitempty"`
+	DeploymentID string     `json:"deploymentid,omitempty"`
+	Level        string     `json:"level"`
+	LogKind      string     `json:"errKind"`
+	Time         time.Time  `json:"time"`
+	API          *API       `json:"api,omitempty"`
+	RemoteHost   string     `json:"remotehost,omitempty"`
+	Host         string     `json:"host,omitempty"`
+	RequestID    string     `json:"requestID,omitempty"`
+	UserAgent    string     `json:"userAgent,omitempty"`
+	Message      string     `json:"message,omitempty"`
+	Trace        *Trace     `json:"error,omitempty"`
+	Telemetry    *Telemetry `json:"telemetry,omitempty"`
+}
+
+// Telemetry - defines the resource usage of a node.
+type Telemetry struct {
+	Goroutines       int    `json:"goroutines"`
+	HeapAlloc        uint64 `json:"heapAlloc"`
+	Sys              uint64 `json:"sys"`
+	OpenFDs          int    `json:"openFDs,omitempty"`
+	RequestsInFlight int    `json:"requestsInFlight"`
 }
 
 // Info holds console log messages
@@ -0,0 +1,218 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// # This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+
+	"github.com/minio/minio/internal/logger/target/amqp"
+	"github.com/minio/minio/internal/logger/target/file"
+	"github.com/minio/minio/internal/logger/target/http"
+	"github.com/minio/minio/internal/logger/target/kafka"
+	"github.com/minio/minio/internal/logger/target/loki"
+	"github.com/minio/minio/internal/logger/target/types"
+	"github.com/minio/minio/internal/logger/target/websocket"
+)
+
+// replacedTarget is a target started by ReplaceConfig along
+// with the config it was started with.
+type replacedTarget struct {
+	target Target
+	config []byte
+}
+
+var (
+	// replaceMu serializes ReplaceConfig calls.
+	replaceMu sync.Mutex
+
+	// replaced are the targets started by ReplaceConfig by their
+	// kind and name, see configTarget.key.
+	replaced = map[string]replacedTarget{}
+)
+
+// configTarget is a target of a Config, started on demand.
+type configTarget struct {
+	key    string
+	audit  bool
+	config []byte
+	start  func() Target
+}
+
+// configTargets returns the enabled targets of cfg.
+func configTargets(cfg Config) []configTarget {
+	var tgts []configTarget
+	add := func(kind, name string, audit bool, config interface{}, newTarget func() Target) {
+		// Configs only differ by the callbacks and transports
+		// excluded from their encoding when changed otherwise.
+		data, err := json.Marshal(config)
+		if err != nil {
+			// Never considered unchanged.
+			data = nil
+		}
+		tgts = append(tgts, configTarget{key: kind + ":" + name, audit: audit, config: data, start: newTarget})
+	}
+	for name, l := range cfg.HTTP {
+		l := l
+		if l.Enabled {
+			add("http", name, false, l, func() Target { return http.New(l) })
+		}
+	}
+	for name, l := range cfg.File {
+		l := l
+		if l.Enabled {
+			add("file", name, false, l, func() Target { return file.New(l) })
+		}
+	}
+	for name, l := range cfg.Loki {
+		l := l
+		if l.Enabled {
+			add("loki", name, false, l, func() Target { return loki.New(l) })
+		}
+	}
+	for name, l := range cfg.WebSocket {
+		l := l
+		if l.Enabled {
+			add("websocket", name, false, l, func() Target { return websocket.New(l) })
+		}
+	}
+	for name, l := range cfg.AuditWebhook {
+		l := l
+		if l.Enabled {
+			add("audit_webhook", name, true, l, func() Target { return http.New(l) })
+		}
+	}
+	for name, l := range cfg.AuditSplunk {
+		l := l
+		if l.Enabled {
+			add("audit_splunk", name, true, l, func() Target { return http.New(l.HTTPConfig()) })
+		}
+	}
+	for name, l := range cfg.AuditKafka {
+		l := l
+		if l.Enabled {
+			add("audit_kafka", name, true, l, func() Target { return kafka.New(l) })
+		}
+	}
+	for name, l := range cfg.AuditAMQP {
+		l := l
+		if l.Enabled {
+			add("audit_amqp", name, true, l, func() Target { return amqp.New(l) })
+		}
+	}
+	return tgts
+}
+
+// ReplaceConfig swaps the system and audit targets for the ones of
+// cfg without any gap in logging: targets whose config is unchanged
+// keep running, added and changed ones are started before the swap
+// and the replaced ones are only canceled after it, once their queued
+// entries are delivered. The console target is always preserved.
+//
+// When a target fails to start, the running targets are left as is
+// and the error is returned. ReplaceConfig waits for the replaced
+// targets to be drained until ctx is done, they are drained in the
+// background past it and ctx.Err() is returned.
+func ReplaceConfig(ctx context.Context, cfg Config) error {
+	replaceMu.Lock()
+	defer replaceMu.Unlock()
+
+	swapMu.Lock()
+	running := make(map[Target]bool, len(systemTargets)+len(auditTargets))
+	for _, tgt := range systemTargets {
+		running[tgt] = true
+	}
+	for _, tgt := range auditTargets {
+		running[tgt] = true
+	}
+	swapMu.Unlock()
+
+	var (
+		systemTgts, auditTgts []Target
+		started               []Target
+		kept                  = make(map[Target]bool)
+		next                  = make(map[string]replacedTarget)
+	)
+	for _, c := range configTargets(cfg) {
+		r, ok := replaced[c.key]
+		tgt := r.target
+		if ok && c.config != nil && string(r.config) == string(c.config) && running[tgt] {
+			kept[tgt] = true
+		} else {
+			tgt = c.start()
+			if err := tgt.Init(); err != nil {
+				for _, t := range started {
+					t.Cancel()
+				}
+				return err
+			}
+			started = append(started, tgt)
+		}
+		next[c.key] = replacedTarget{target: tgt, config: c.config}
+		if c.audit {
+			auditTgts = append(auditTgts, tgt)
+		} else {
+			systemTgts = append(systemTgts, tgt)
+		}
+	}
+
+	var removed []Target
+	swapMu.Lock()
+	for _, tgt := range systemTargets {
+		switch {
+		case tgt.Type() == types.TargetConsole:
+			// Console target is always present.
+			systemTgts = append(systemTgts, tgt)
+		case !kept[tgt]:
+			removed = append(removed, tgt)
+		}
+	}
+	for _, tgt := range auditTargets {
+		if !kept[tgt] {
+			removed = append(removed, tgt)
+		}
+	}
+	systemTargets = systemTgts
+	auditTargets = auditTgts
+	atomic.StoreInt32(&nTargets, int32(len(systemTgts)))
+	atomic.StoreInt32(&nAuditTargets, int32(len(auditTgts)))
+	swapMu.Unlock()
+	replaced = next
+
+	drained := make(chan struct{})
+	go func() {
+		defer close(drained)
+		var wg sync.WaitGroup
+		for _, tgt := range removed {
+			wg.Add(1)
+			go func(tgt Target) {
+				defer wg.Done()
+				tgt.Cancel()
+			}(tgt)
+		}
+		wg.Wait()
+	}()
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
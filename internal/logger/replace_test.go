@@ -0,0 +1,110 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package logger
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	xhttp "github.com/minio/minio/internal/logger/target/http"
+	"github.com/minio/minio/internal/logger/target/types"
+)
+
+type testConsoleTarget struct{ testAuditTarget }
+
+func (t *testConsoleTarget) Type() types.TargetType { return types.TargetConsole }
+
+func TestReplaceConfig(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	console := &testConsoleTarget{}
+	swapMu.Lock()
+	systemTargets = []Target{console}
+	atomic.StoreInt32(&nTargets, 1)
+	swapMu.Unlock()
+	defer func() {
+		ReplaceConfig(context.Background(), NewConfig())
+		swapMu.Lock()
+		systemTargets = []Target{}
+		atomic.StoreInt32(&nTargets, 0)
+		swapMu.Unlock()
+	}()
+
+	webhook := func(path string) xhttp.Config {
+		return xhttp.Config{
+			Enabled:   true,
+			Endpoint:  srv.URL + path,
+			QueueSize: 10,
+			LogOnce:   func(context.Context, error, interface{}, ...interface{}) {},
+		}
+	}
+	target := func(key string) *xhttp.Target {
+		return replaced[key].target.(*xhttp.Target)
+	}
+
+	cfg := NewConfig()
+	cfg.HTTP["a"] = webhook("/a")
+	cfg.HTTP["b"] = webhook("/b")
+	cfg.AuditWebhook["c"] = webhook("/c")
+	if err := ReplaceConfig(context.Background(), cfg); err != nil {
+		t.Fatal(err)
+	}
+	if n, m := len(SystemTargets()), len(AuditTargets()); n != 3 || m != 1 {
+		t.Fatalf("expected 3 system and 1 audit targets, got %d and %d", n, m)
+	}
+	a, b, c := target("http:a"), target("http:b"), target("audit_webhook:c")
+
+	cfg = NewConfig()
+	cfg.HTTP["a"] = webhook("/a")
+	cfg.HTTP["b"] = webhook("/b2")
+	cfg.HTTP["d"] = webhook("/d")
+	if err := ReplaceConfig(context.Background(), cfg); err != nil {
+		t.Fatal(err)
+	}
+	if target("http:a") != a {
+		t.Fatal("expected the unchanged target to keep running")
+	}
+	if target("http:b") == b || !target("http:b").IsOnline() {
+		t.Fatal("expected the changed target to be replaced")
+	}
+	if b.IsOnline() || c.IsOnline() {
+		t.Fatal("expected the replaced and removed targets to be canceled")
+	}
+	tgts := SystemTargets()
+	if len(tgts) != 4 || tgts[len(tgts)-1] != console {
+		t.Fatalf("expected 3 targets and the console target, got %v", tgts)
+	}
+	if n := len(AuditTargets()); n != 0 {
+		t.Fatalf("expected no audit targets, got %d", n)
+	}
+
+	// A target failing to start leaves the running ones as is.
+	cfg.HTTP["e"] = xhttp.Config{Enabled: true, Endpoint: "http://127.0.0.1:0", QueueSize: 10}
+	if err := ReplaceConfig(context.Background(), cfg); err == nil {
+		t.Fatal("expected the failing target to be reported")
+	}
+	if len(SystemTargets()) != 4 || !a.IsOnline() {
+		t.Fatal("expected the running targets to be left as is")
+	}
+}
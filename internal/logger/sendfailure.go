@@ -0,0 +1,91 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package logger
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// sendFailureInterval is the minimum interval between two reports
+// of the same error returned by the same target.
+const sendFailureInterval = time.Minute
+
+type sendFailureKey struct {
+	target Target
+	err    string
+}
+
+type sendFailure struct {
+	reported   time.Time
+	suppressed int
+}
+
+// sendFailures rate limits the reports of the entries targets failed
+// to accept, e.g. a target with a full queue fails every entry and
+// reporting each of them, possibly through the same targets, would
+// only add to the load.
+type sendFailures struct {
+	mu       sync.Mutex
+	interval time.Duration
+	failures map[sendFailureKey]*sendFailure
+}
+
+func newSendFailures(interval time.Duration) *sendFailures {
+	return &sendFailures{
+		interval: interval,
+		failures: make(map[sendFailureKey]*sendFailure),
+	}
+}
+
+// allow returns true if the failure of t with err should be reported
+// now, along with the number of identical failures suppressed since
+// it was last reported.
+func (s *sendFailures) allow(t Target, err error, now time.Time) (bool, int) {
+	key := sendFailureKey{target: t, err: err.Error()}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, ok := s.failures[key]
+	if !ok {
+		if len(s.failures) >= 1000 {
+			// Forget about the targets replaced since.
+			s.failures = make(map[sendFailureKey]*sendFailure)
+		}
+		s.failures[key] = &sendFailure{reported: now}
+		return true, 0
+	}
+	if now.Sub(f.reported) < s.interval {
+		f.suppressed++
+		return false, 0
+	}
+	suppressed := f.suppressed
+	f.reported, f.suppressed = now, 0
+	return true, suppressed
+}
+
+var targetSendFailures = newSendFailures(sendFailureInterval)
+
+// suppressedSuffix describes the number of suppressed failures, if any.
+func suppressedSuffix(suppressed int) string {
+	if suppressed == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%d similar errors suppressed)", suppressed)
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package logger
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/internal/logger/target/file"
+	"github.com/minio/minio/internal/logger/target/types"
+)
+
+func TestSendFailures(t *testing.T) {
+	s := newSendFailures(time.Minute)
+	tgt1, tgt2 := file.New(file.Config{Name: "t1"}), file.New(file.Config{Name: "t2"})
+	now := time.Now()
+
+	if ok, _ := s.allow(tgt1, types.ErrLogBufferFull, now); !ok {
+		t.Fatal("expected the first failure to be reported")
+	}
+	for i := 0; i < 5; i++ {
+		if ok, _ := s.allow(tgt1, types.ErrLogBufferFull, now.Add(time.Second)); ok {
+			t.Fatal("expected the same failure to be suppressed within the interval")
+		}
+	}
+	// Other targets and errors are reported independently.
+	if ok, _ := s.allow(tgt2, types.ErrLogBufferFull, now); !ok {
+		t.Fatal("expected the failure of another target to be reported")
+	}
+	if ok, _ := s.allow(tgt1, errors.New("remote offline"), now); !ok {
+		t.Fatal("expected another failure to be reported")
+	}
+
+	ok, suppressed := s.allow(tgt1, types.ErrLogBufferFull, now.Add(time.Minute))
+	if !ok || suppressed != 5 {
+		t.Fatalf("expected the failure to be reported again with 5 suppressed, got %v, %d", ok, suppressed)
+	}
+	if ok, _ = s.allow(tgt1, types.ErrLogBufferFull, now.Add(time.Minute+time.Second)); ok {
+		t.Fatal("expected the failure to be suppressed again")
+	}
+}
@@ -0,0 +1,410 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package amqp
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/streadway/amqp"
+
+	"github.com/minio/minio/internal/config"
+	"github.com/minio/minio/internal/logger/message/audit"
+	"github.com/minio/minio/internal/logger/target/queued"
+	"github.com/minio/minio/internal/logger/target/throttle"
+	"github.com/minio/minio/internal/logger/target/types"
+	xnet "github.com/minio/pkg/net"
+)
+
+// Delivery modes of the messages, see Config.DeliveryMode
+const (
+	DeliveryModePersistent = "persistent"
+	DeliveryModeTransient  = "transient"
+)
+
+const (
+	// Default number of entries queued
+	defaultQueueSize = 10000
+
+	// Times an entry is published before being counted as failed
+	// when publisher confirms are enabled.
+	maxPublishAttempts = 3
+)
+
+// Time the broker is given to confirm a message
+var confirmTimeout = 10 * time.Second
+
+// Config - AMQP target arguments.
+type Config struct {
+	Enabled      bool     `json:"enable"`
+	URL          xnet.URL `json:"url"`
+	Exchange     string   `json:"exchange"`
+	ExchangeType string   `json:"exchangeType"`
+	RoutingKey   string   `json:"routingKey"`
+
+	// DeliveryMode of the messages, DeliveryModePersistent by default
+	// for the broker to store them on disk, DeliveryModeTransient to
+	// only keep them in memory.
+	DeliveryMode string `json:"deliveryMode"`
+
+	// PublisherConfirms when set, waits for the broker to confirm each
+	// message, publishing it again when it is not confirmed, for at
+	// least once delivery. Otherwise messages are published without
+	// waiting for the broker.
+	PublisherConfirms bool `json:"publisherConfirms"`
+
+	// QueueSize is the number of entries queued while they are
+	// published, 10000 by default.
+	QueueSize int `json:"queueSize"`
+
+	// TLS settings used by amqps:// URLs.
+	TLS struct {
+		RootCAs       *x509.CertPool `json:"-"`
+		SkipVerify    bool           `json:"skipVerify"`
+		ClientTLSCert string         `json:"clientTLSCert"`
+		ClientTLSKey  string         `json:"clientTLSKey"`
+	} `json:"tls"`
+
+	// Custom logger
+	LogOnce func(ctx context.Context, err error, id interface{}, errKind ...interface{}) `json:"-"`
+}
+
+// Validate validates the arguments of an enabled target.
+func (c Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if _, err := amqp.ParseURI(c.URL.String()); err != nil {
+		return err
+	}
+	switch c.DeliveryMode {
+	case "", DeliveryModePersistent, DeliveryModeTransient:
+	default:
+		return fmt.Errorf("invalid delivery mode %s, expected persistent or transient", c.DeliveryMode)
+	}
+	if (c.TLS.ClientTLSCert == "") != (c.TLS.ClientTLSKey == "") {
+		return errors.New("a client certificate and key must be set together")
+	}
+	return nil
+}
+
+// Redacted returns a copy of the config with its secrets redacted.
+func (c Config) Redacted() Config {
+	u := url.URL(c.URL)
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), "*REDACTED*")
+		c.URL = xnet.URL(u)
+	}
+	return c
+}
+
+// Target - AMQP target.
+type Target struct {
+	// These fields must be first, they are accessed atomically
+	totalMessages  int64
+	failedMessages int64
+
+	status int32
+	wg     sync.WaitGroup
+
+	// Channel of log entries
+	logCh chan interface{}
+
+	config Config
+
+	// Only used by the logger routine once initialized.
+	tlsConfig *tls.Config
+	conn      *amqp.Connection
+	ch        *amqp.Channel
+	confirms  chan amqp.Confirmation
+
+	// Why the target is not online, nil once it is.
+	offlineMu  sync.Mutex
+	offlineErr error
+}
+
+// New initializes a new logger target which
+// publishes log entries to an AMQP exchange.
+func New(config Config) *Target {
+	queueSize := config.QueueSize
+	if queueSize <= 0 {
+		queueSize = defaultQueueSize
+	}
+	return &Target{
+		logCh:  make(chan interface{}, queueSize),
+		config: config,
+	}
+}
+
+// Endpoint - return amqp target
+func (h *Target) Endpoint() string {
+	return h.Config().URL.String()
+}
+
+// String - amqp string
+func (h *Target) String() string {
+	return "amqp"
+}
+
+// Type - returns type of the target
+func (h *Target) Type() types.TargetType {
+	return types.TargetAMQP
+}
+
+// Init initialize amqp target
+func (h *Target) Init() error {
+	err := h.init()
+	h.offlineMu.Lock()
+	h.offlineErr = err
+	h.offlineMu.Unlock()
+	return err
+}
+
+func (h *Target) init() error {
+	if !h.config.Enabled {
+		return nil
+	}
+	if err := h.config.Validate(); err != nil {
+		return err
+	}
+	if h.config.URL.Scheme == "amqps" {
+		h.tlsConfig = &tls.Config{
+			MinVersion:         tls.VersionTLS12,
+			RootCAs:            h.config.TLS.RootCAs,
+			InsecureSkipVerify: h.config.TLS.SkipVerify,
+		}
+		if h.config.TLS.ClientTLSCert != "" {
+			cert, err := config.LoadClientKeyPair(h.config.TLS.ClientTLSCert, h.config.TLS.ClientTLSKey)
+			if err != nil {
+				return err
+			}
+			h.tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+	}
+	// Fails early when the broker is unreachable.
+	if err := h.connect(); err != nil {
+		return err
+	}
+
+	h.status = 1
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+		for entry := range h.logCh {
+			queued.Release(1)
+			h.logEntry(entry)
+		}
+		h.disconnect()
+	}()
+	return nil
+}
+
+// connect opens the connection and channel messages are
+// published on and declares the exchange, if any.
+func (h *Target) connect() (err error) {
+	if h.tlsConfig != nil {
+		h.conn, err = amqp.DialTLS(h.config.URL.String(), h.tlsConfig)
+	} else {
+		h.conn, err = amqp.Dial(h.config.URL.String())
+	}
+	if err != nil {
+		return err
+	}
+	if h.ch, err = h.conn.Channel(); err != nil {
+		h.disconnect()
+		return err
+	}
+	if h.config.Exchange != "" {
+		exchangeType := h.config.ExchangeType
+		if exchangeType == "" {
+			exchangeType = amqp.ExchangeDirect
+		}
+		if err = h.ch.ExchangeDeclare(h.config.Exchange, exchangeType, true, false, false, false, nil); err != nil {
+			h.disconnect()
+			return err
+		}
+	}
+	if h.config.PublisherConfirms {
+		h.confirms = h.ch.NotifyPublish(make(chan amqp.Confirmation, 1))
+		if err = h.ch.Confirm(false); err != nil {
+			h.disconnect()
+			return err
+		}
+	}
+	return nil
+}
+
+// disconnect closes the connection, it is opened again
+// by the next entry.
+func (h *Target) disconnect() {
+	if h.conn != nil {
+		// Closes its channels as well.
+		h.conn.Close()
+	}
+	h.conn, h.ch, h.confirms = nil, nil, nil
+}
+
+func (h *Target) logEntry(entry interface{}) {
+	logJSON, err := json.Marshal(&entry)
+	if err != nil {
+		return
+	}
+
+	throttle.Wait(context.Background(), 1)
+	atomic.AddInt64(&h.totalMessages, 1)
+	msg := amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		Timestamp:    time.Now().UTC(),
+		Body:         logJSON,
+	}
+	if h.config.DeliveryMode == DeliveryModeTransient {
+		msg.DeliveryMode = amqp.Transient
+	}
+	if ae, ok := entry.(audit.Entry); ok {
+		msg.MessageId = ae.RequestID
+	}
+
+	attempts := 1
+	if h.config.PublisherConfirms {
+		attempts = maxPublishAttempts
+	}
+	for i := 0; i < attempts; i++ {
+		if err = h.publish(msg); err == nil {
+			return
+		}
+		// The connection may be broken, it is opened
+		// again for the next attempt or entry.
+		h.disconnect()
+	}
+	atomic.AddInt64(&h.failedMessages, 1)
+	h.config.LogOnce(context.Background(), err, h.String())
+}
+
+// publish publishes msg, waiting for the broker to
+// confirm it when publisher confirms are enabled.
+func (h *Target) publish(msg amqp.Publishing) error {
+	if h.conn == nil || h.conn.IsClosed() {
+		h.disconnect()
+		if err := h.connect(); err != nil {
+			return err
+		}
+	}
+	if err := h.ch.Publish(h.config.Exchange, h.config.RoutingKey, false, false, msg); err != nil {
+		return err
+	}
+	if !h.config.PublisherConfirms {
+		return nil
+	}
+	timer := time.NewTimer(confirmTimeout)
+	defer timer.Stop()
+	select {
+	case confirmed, ok := <-h.confirms:
+		if !ok {
+			return amqp.ErrClosed
+		}
+		if !confirmed.Ack {
+			return fmt.Errorf("message %d was not confirmed by the broker", confirmed.DeliveryTag)
+		}
+		return nil
+	case <-timer.C:
+		return errors.New("timed out waiting for the broker to confirm the message")
+	}
+}
+
+// Send log message 'e' to amqp target.
+func (h *Target) Send(entry interface{}, errKind string) error {
+	if err := queued.Acquire(context.Background()); err != nil {
+		return err
+	}
+	select {
+	case h.logCh <- entry:
+	default:
+		queued.Release(1)
+		// log channel is full, do not wait and return
+		// an error immediately to the caller
+		return types.ErrLogBufferFull
+	}
+
+	return nil
+}
+
+// Cancel - cancels the target once its queued entries are published.
+func (h *Target) Cancel() {
+	if atomic.CompareAndSwapInt32(&h.status, 1, 0) {
+		close(h.logCh)
+
+		h.offlineMu.Lock()
+		h.offlineErr = types.ErrTargetCanceled
+		h.offlineMu.Unlock()
+	}
+	h.wg.Wait()
+}
+
+// IsOnline returns true if the target is initialized and not canceled.
+func (h *Target) IsOnline() bool {
+	return atomic.LoadInt32(&h.status) == 1
+}
+
+// OnlineStatus returns whether the target is online and if
+// not, why: the error of its initialization or the fact it
+// was not initialized yet or was canceled.
+func (h *Target) OnlineStatus() (bool, error) {
+	if h.IsOnline() {
+		return true, nil
+	}
+	h.offlineMu.Lock()
+	defer h.offlineMu.Unlock()
+	if h.offlineErr == nil {
+		return false, types.ErrTargetNotInitialized
+	}
+	return false, h.offlineErr
+}
+
+// Ready returns true if the target is online and its queue is
+// able to accept more entries, within the cap of all the queues.
+func (h *Target) Ready() bool {
+	return h.IsOnline() && len(h.logCh) < cap(h.logCh) && !queued.Full()
+}
+
+// Stats returns the delivery statistics of the target.
+func (h *Target) Stats() types.TargetStats {
+	stats := types.TargetStats{
+		Enabled:        true,
+		TotalMessages:  atomic.LoadInt64(&h.totalMessages),
+		FailedMessages: atomic.LoadInt64(&h.failedMessages),
+		QueueLength:    len(h.logCh),
+	}
+	if _, err := h.OnlineStatus(); err != nil {
+		stats.OfflineReason = err.Error()
+	}
+	return stats
+}
+
+// Config returns the target config with its secrets redacted.
+func (h *Target) Config() Config {
+	return h.config.Redacted()
+}
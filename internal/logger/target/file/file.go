@@ -0,0 +1,283 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/internal/logger/target/types"
+)
+
+// Defaults of the file target
+const (
+	defaultMaxSize      = 100 << 20 // 100MiB
+	defaultMaxFiles     = 10
+	defaultSyncInterval = time.Second
+	defaultQueueSize    = 10000
+)
+
+// Config file logger target
+type Config struct {
+	Enabled bool   `json:"enabled"`
+	Name    string `json:"name"`
+	Path    string `json:"path"`
+
+	// MaxSize is the size in bytes the file is rotated at,
+	// defaults to 100MiB. MaxFiles is the number of rotated
+	// files kept next to it as path.1 (newest) to path.N,
+	// defaults to 10.
+	MaxSize  int64 `json:"maxSize"`
+	MaxFiles int   `json:"maxFiles"`
+
+	// SyncInterval is how often written entries are synced
+	// to disk, defaults to 1s.
+	SyncInterval time.Duration `json:"syncInterval"`
+
+	// QueueSize is the number of entries queued while they
+	// are written, defaults to 10000.
+	QueueSize int `json:"queueSize"`
+
+	// Custom logger
+	LogOnce func(ctx context.Context, err error, id interface{}, errKind ...interface{}) `json:"-"`
+}
+
+// Target implements logger.Target and appends entries as
+// newline delimited JSON to a local file, rotated by size.
+type Target struct {
+	// mu guards the closing of logCh, open is false
+	// before Init and after Cancel.
+	mu    sync.RWMutex
+	open  bool
+	logCh chan interface{}
+
+	// Only used by the routine writing the queued entries.
+	file  *os.File
+	size  int64
+	dirty bool
+
+	wg sync.WaitGroup
+
+	config Config
+}
+
+// New initializes a new file target, Init must be called
+// before any entry is sent.
+func New(config Config) *Target {
+	if config.MaxSize <= 0 {
+		config.MaxSize = defaultMaxSize
+	}
+	if config.MaxFiles <= 0 {
+		config.MaxFiles = defaultMaxFiles
+	}
+	if config.SyncInterval <= 0 {
+		config.SyncInterval = defaultSyncInterval
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = defaultQueueSize
+	}
+	return &Target{
+		config: config,
+		logCh:  make(chan interface{}, config.QueueSize),
+	}
+}
+
+// Endpoint returns the path of the file
+func (t *Target) Endpoint() string {
+	return t.config.Path
+}
+
+// String returns the name of the target
+func (t *Target) String() string {
+	return t.config.Name
+}
+
+// Config returns the config of the target.
+func (t *Target) Config() Config {
+	return t.config
+}
+
+// Init opens the file, creating it and its directory if needed,
+// and starts writing the queued entries, syncing them on the
+// configured interval.
+func (t *Target) Init() error {
+	if t.config.Path == "" {
+		return errors.New("file logger target: path cannot be empty")
+	}
+	if err := os.MkdirAll(filepath.Dir(t.config.Path), 0o755); err != nil {
+		return fmt.Errorf("file logger target: %w", err)
+	}
+	if err := t.openFile(); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	t.open = true
+	t.mu.Unlock()
+
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		ticker := time.NewTicker(t.config.SyncInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case entry, ok := <-t.logCh:
+				if !ok {
+					t.closeFile()
+					return
+				}
+				if err := t.write(entry); err != nil && t.config.LogOnce != nil {
+					t.config.LogOnce(context.Background(), err, t.config.Path)
+				}
+			case <-ticker.C:
+				t.sync()
+			}
+		}
+	}()
+	return nil
+}
+
+// openFile opens the file for appending.
+func (t *Target) openFile() error {
+	f, err := os.OpenFile(t.config.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o640)
+	if err != nil {
+		return fmt.Errorf("file logger target: %w", err)
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("file logger target: %w", err)
+	}
+	t.file = f
+	t.size = fi.Size()
+	return nil
+}
+
+// closeFile syncs and closes the file.
+func (t *Target) closeFile() {
+	if t.file != nil {
+		t.sync()
+		t.file.Close()
+		t.file = nil
+	}
+}
+
+// sync flushes the written entries to disk.
+func (t *Target) sync() {
+	if t.file != nil && t.dirty {
+		t.file.Sync()
+		t.dirty = false
+	}
+}
+
+// rotate renames path.i to path.i+1 starting from the oldest, which
+// replaces path.N, then path to path.1 and starts a new file. Each
+// step is a rename, hence atomic. If one fails the file is opened
+// again to keep appending to it, its rotation is then tried again
+// with the next entry.
+func (t *Target) rotate() error {
+	t.closeFile()
+
+	path := t.config.Path
+	err := func() error {
+		for i := t.config.MaxFiles - 1; i > 0; i-- {
+			err := os.Rename(fmt.Sprintf("%s.%d", path, i), fmt.Sprintf("%s.%d", path, i+1))
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+		return os.Rename(path, path+".1")
+	}()
+	if oerr := t.openFile(); oerr != nil {
+		return oerr
+	}
+	if err != nil {
+		return fmt.Errorf("file logger target: %w", err)
+	}
+	return nil
+}
+
+// write appends the entry to the file as a single line of JSON,
+// rotating the file first if the entry would exceed MaxSize.
+func (t *Target) write(entry interface{}) error {
+	logJSON, err := json.Marshal(&entry)
+	if err != nil {
+		return err
+	}
+	logJSON = append(logJSON, '\n')
+
+	if t.file == nil {
+		// Opening it failed after a rotation.
+		if err = t.openFile(); err != nil {
+			return err
+		}
+	}
+	var rotateErr error
+	if t.size > 0 && t.size+int64(len(logJSON)) > t.config.MaxSize {
+		if rotateErr = t.rotate(); t.file == nil {
+			return rotateErr
+		}
+	}
+	n, err := t.file.Write(logJSON)
+	t.size += int64(n)
+	t.dirty = true
+	if err != nil {
+		return err
+	}
+	return rotateErr
+}
+
+// Send queues the entry to be appended to the file, it
+// fails right away if the queue is full.
+func (t *Target) Send(entry interface{}, errKind string) error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if !t.open {
+		return errors.New("file logger target: file is not open")
+	}
+	select {
+	case t.logCh <- entry:
+		return nil
+	default:
+		return types.ErrLogBufferFull
+	}
+}
+
+// Cancel writes the queued entries, syncs and closes the file.
+func (t *Target) Cancel() {
+	t.mu.Lock()
+	if t.open {
+		t.open = false
+		close(t.logCh)
+	}
+	t.mu.Unlock()
+	t.wg.Wait()
+}
+
+// Type returns the type of the target
+func (t *Target) Type() types.TargetType {
+	return types.TargetFile
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package file
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+func readEntries(t *testing.T, path string) []map[string]int {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var entries []map[string]int
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry map[string]int
+		if err = json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestTargetRotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "minio.log")
+	// Each entry is 12 bytes, `{"entry":N}\n`, a file holds two of them.
+	tgt := New(Config{Enabled: true, Path: path, MaxSize: 24, MaxFiles: 2})
+	if err := tgt.Init(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 7; i++ {
+		if err := tgt.Send(map[string]int{"entry": i}, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tgt.Cancel()
+	tgt.Cancel()
+
+	for name, expected := range map[string][]int{
+		path:        {6},
+		path + ".1": {4, 5},
+		path + ".2": {2, 3},
+	} {
+		entries := readEntries(t, name)
+		if len(entries) != len(expected) {
+			t.Fatalf("%s: expected %d entries, got %d", name, len(expected), len(entries))
+		}
+		for i, entry := range entries {
+			if entry["entry"] != expected[i] {
+				t.Fatalf("%s: expected entry %d, got %d", name, expected[i], entry["entry"])
+			}
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Fatalf("expected at most 2 rotated files, got %v", err)
+	}
+	if err := tgt.Send(map[string]int{"entry": 7}, ""); err == nil {
+		t.Fatal("expected send after cancel to fail")
+	}
+}
+
+func TestTargetAppend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "minio.log")
+	for i := 0; i < 2; i++ {
+		tgt := New(Config{Enabled: true, Path: path})
+		if err := tgt.Init(); err != nil {
+			t.Fatal(err)
+		}
+		if err := tgt.Send(map[string]int{"entry": i}, ""); err != nil {
+			t.Fatal(err)
+		}
+		tgt.Cancel()
+	}
+	if entries := readEntries(t, path); len(entries) != 2 {
+		t.Fatalf("expected entries to be appended to the existing file, got %d", len(entries))
+	}
+}
+
+func TestTargetRotateFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "minio.log")
+	// A non-empty directory in place of path.1 fails the rotation.
+	if err := os.MkdirAll(filepath.Join(path+".1", "busy"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	var failures int32
+	tgt := New(Config{
+		Enabled:  true,
+		Path:     path,
+		MaxSize:  24,
+		MaxFiles: 1,
+		LogOnce: func(_ context.Context, err error, _ interface{}, _ ...interface{}) {
+			atomic.AddInt32(&failures, 1)
+		},
+	})
+	if err := tgt.Init(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 4; i++ {
+		if err := tgt.Send(map[string]int{"entry": i}, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tgt.Cancel()
+
+	if atomic.LoadInt32(&failures) == 0 {
+		t.Fatal("expected the failed rotations to be logged")
+	}
+	if entries := readEntries(t, path); len(entries) != 4 {
+		t.Fatalf("expected entries to be appended after a failed rotation, got %d", len(entries))
+	}
+}
@@ -0,0 +1,347 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package filter implements the expressions selecting the entries
+// sent by a logger target, e.g.
+//
+//	api.bucket == 'sensitive' OR api.statusCode >= 500
+//
+// Operands are either fields, dot separated paths in the JSON
+// encoding of an entry, or string, number, true, false and null
+// literals. They are compared with ==, !=, <, <=, > and >=, and
+// combined with AND, OR, NOT (or &&, ||, !) and parentheses. A
+// field alone holds if it is set to anything but false, null, ""
+// or 0. A comparison only holds between values of the same type,
+// a missing field being null, and != is always the opposite of ==.
+package filter
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/buger/jsonparser"
+)
+
+// Expr is a parsed filter expression, safe for concurrent use.
+type Expr struct {
+	text string
+	root node
+}
+
+// String returns the text the expression was parsed from.
+func (e *Expr) String() string {
+	return e.text
+}
+
+// Match returns true if the JSON encoded entry satisfies the expression.
+func (e *Expr) Match(entry []byte) bool {
+	return e.root.eval(entry)
+}
+
+// Parse parses a filter expression.
+func Parse(text string) (*Expr, error) {
+	p := &parser{lex: lexer{input: text}}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, p.errorf("unexpected %s", p.tok)
+	}
+	return &Expr{text: text, root: root}, nil
+}
+
+// node is a node of the expression tree.
+type node interface {
+	eval(entry []byte) bool
+}
+
+type orNode struct{ left, right node }
+
+func (n orNode) eval(entry []byte) bool { return n.left.eval(entry) || n.right.eval(entry) }
+
+type andNode struct{ left, right node }
+
+func (n andNode) eval(entry []byte) bool { return n.left.eval(entry) && n.right.eval(entry) }
+
+type notNode struct{ n node }
+
+func (n notNode) eval(entry []byte) bool { return !n.n.eval(entry) }
+
+// truthNode holds if its operand is set to anything but
+// false, null, "" or 0.
+type truthNode struct{ o operand }
+
+func (n truthNode) eval(entry []byte) bool {
+	v := n.o.value(entry)
+	switch v.kind {
+	case kindString:
+		return len(v.str) > 0
+	case kindNumber:
+		return v.num != 0
+	case kindBool:
+		return v.b
+	case kindOther:
+		return true
+	}
+	return false
+}
+
+type compareNode struct {
+	op          string
+	left, right operand
+}
+
+func (n compareNode) eval(entry []byte) bool {
+	l, r := n.left.value(entry), n.right.value(entry)
+	if n.op == "!=" {
+		return !l.equal(r)
+	}
+	if n.op == "==" {
+		return l.equal(r)
+	}
+	var cmp int
+	switch {
+	case l.kind == kindNumber && r.kind == kindNumber:
+		switch {
+		case l.num < r.num:
+			cmp = -1
+		case l.num > r.num:
+			cmp = 1
+		}
+	case l.kind == kindString && r.kind == kindString:
+		cmp = bytes.Compare(l.str, r.str)
+	default:
+		return false
+	}
+	switch n.op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	default:
+		return cmp >= 0
+	}
+}
+
+// Kinds of values.
+const (
+	kindNull = iota
+	kindString
+	kindNumber
+	kindBool
+	kindOther // objects and arrays, only equal to themselves.
+)
+
+type value struct {
+	kind int
+	str  []byte
+	num  float64
+	b    bool
+}
+
+func (v value) equal(o value) bool {
+	if v.kind != o.kind {
+		return false
+	}
+	switch v.kind {
+	case kindString, kindOther:
+		return bytes.Equal(v.str, o.str)
+	case kindNumber:
+		return v.num == o.num
+	case kindBool:
+		return v.b == o.b
+	}
+	return true
+}
+
+// operand is a field or a literal.
+type operand struct {
+	path    []string // field path, nil for a literal.
+	literal value
+}
+
+func (o operand) value(entry []byte) value {
+	if o.path == nil {
+		return o.literal
+	}
+	data, typ, _, err := jsonparser.Get(entry, o.path...)
+	if err != nil {
+		return value{kind: kindNull}
+	}
+	switch typ {
+	case jsonparser.String:
+		if bytes.IndexByte(data, '\\') >= 0 {
+			s, err := jsonparser.ParseString(data)
+			if err != nil {
+				return value{kind: kindNull}
+			}
+			data = []byte(s)
+		}
+		return value{kind: kindString, str: data}
+	case jsonparser.Number:
+		f, err := jsonparser.ParseFloat(data)
+		if err != nil {
+			return value{kind: kindNull}
+		}
+		return value{kind: kindNumber, num: f}
+	case jsonparser.Boolean:
+		return value{kind: kindBool, b: data[0] == 't'}
+	case jsonparser.Object, jsonparser.Array:
+		return value{kind: kindOther, str: data}
+	}
+	return value{kind: kindNull}
+}
+
+// parser is a recursive descent parser of expressions:
+//
+//	or      = and { OR and }
+//	and     = not { AND not }
+//	not     = NOT not | primary
+//	primary = '(' or ')' | operand [ op operand ]
+type parser struct {
+	lex lexer
+	tok token
+}
+
+func (p *parser) next() (err error) {
+	p.tok, err = p.lex.next()
+	return err
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("invalid filter %q at offset %d: %s", p.lex.input, p.tok.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok.kind == tokOr {
+		if err = p.next(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok.kind == tokAnd {
+		if err = p.next(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (node, error) {
+	if p.tok.kind != tokNot {
+		return p.parsePrimary()
+	}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	n, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	return notNode{n}, nil
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	if p.tok.kind == tokLParen {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokRParen {
+			return nil, p.errorf("expected ')', got %s", p.tok)
+		}
+		return n, p.next()
+	}
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokOp {
+		return truthNode{left}, nil
+	}
+	op := p.tok.text
+	if err = p.next(); err != nil {
+		return nil, err
+	}
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	return compareNode{op: op, left: left, right: right}, nil
+}
+
+func (p *parser) parseOperand() (o operand, err error) {
+	switch p.tok.kind {
+	case tokField:
+		switch strings.ToLower(p.tok.text) {
+		case "true", "false":
+			o.literal = value{kind: kindBool, b: strings.EqualFold(p.tok.text, "true")}
+		case "null":
+			o.literal = value{kind: kindNull}
+		default:
+			o.path = strings.Split(p.tok.text, ".")
+			for _, key := range o.path {
+				if key == "" {
+					return o, p.errorf("invalid field %s", p.tok.text)
+				}
+			}
+		}
+	case tokString:
+		o.literal = value{kind: kindString, str: []byte(p.tok.text)}
+	case tokNumber:
+		f, err := strconv.ParseFloat(p.tok.text, 64)
+		if err != nil {
+			return o, p.errorf("invalid number %s", p.tok.text)
+		}
+		o.literal = value{kind: kindNumber, num: f}
+	default:
+		return o, p.errorf("expected a field or a value, got %s", p.tok)
+	}
+	return o, p.next()
+}
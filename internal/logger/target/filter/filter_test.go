@@ -0,0 +1,98 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package filter
+
+import "testing"
+
+func TestExprMatch(t *testing.T) {
+	entry := []byte(`{"api":{"name":"PutObject","bucket":"sensitive","statusCode":200,"timeToResponse":"1.5ms"},` +
+		`"remotehost":"10.0.0.1","tags":{"quoted":"a\"b"},"error":"","debug":false,"count":0,"list":[1,2]}`)
+	testCases := []struct {
+		expr     string
+		expected bool
+	}{
+		{`api.bucket == 'sensitive'`, true},
+		{`api.bucket == "other"`, false},
+		{`api.bucket == 'other' OR api.statusCode >= 500`, false},
+		{`api.bucket == 'sensitive' OR api.statusCode >= 500`, true},
+		{`api.bucket == 'sensitive' AND api.statusCode >= 500`, false},
+		{`api.statusCode < 300 && api.statusCode >= 200`, true},
+		{`api.statusCode == 200.0`, true},
+		{`api.statusCode == '200'`, false},
+		{`api.statusCode != '200'`, true},
+		{`NOT api.bucket == 'sensitive'`, false},
+		{`!(api.bucket == 'sensitive' || api.name == 'GetObject')`, false},
+		{`not (api.name == 'GetObject')`, true},
+		{`api.name > 'GetObject'`, true},
+		{`tags.quoted == 'a"b'`, true},
+		{`tags.quoted == "a\"b"`, true},
+		// Truthiness of fields.
+		{`api.bucket`, true},
+		{`error`, false},
+		{`debug`, false},
+		{`count`, false},
+		{`missing`, false},
+		{`list`, true},
+		// Missing fields are null.
+		{`missing == null`, true},
+		{`missing != null`, false},
+		{`missing != 'x'`, true},
+		{`missing < 1`, false},
+		{`debug == false`, true},
+		{`api.name == api.name`, true},
+	}
+	for i, testCase := range testCases {
+		expr, err := Parse(testCase.expr)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if got := expr.Match(entry); got != testCase.expected {
+			t.Errorf("Test %d: %s: expected %v, got %v", i+1, testCase.expr, testCase.expected, got)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, expr := range []string{
+		``,
+		`api.bucket ==`,
+		`api.bucket == 'x' AND`,
+		`(api.bucket == 'x'`,
+		`api.bucket == 'x')`,
+		`api.bucket = 'x'`,
+		`api..bucket`,
+		`api.bucket == 'x`,
+		`api.bucket == 1 == 2`,
+	} {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("expected %q to be rejected", expr)
+		}
+	}
+}
+
+func BenchmarkExprMatch(b *testing.B) {
+	entry := []byte(`{"api":{"name":"PutObject","bucket":"sensitive","statusCode":200}}`)
+	expr, err := Parse(`api.bucket == 'sensitive' OR api.statusCode >= 500`)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		expr.Match(entry)
+	}
+}
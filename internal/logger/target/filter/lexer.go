@@ -0,0 +1,151 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package filter
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokField
+	tokString
+	tokNumber
+	tokOp
+	tokAnd
+	tokOr
+	tokNot
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of expression"
+	case tokString:
+		return fmt.Sprintf("%q", t.text)
+	}
+	return fmt.Sprintf("'%s'", t.text)
+}
+
+// lexer splits an expression into tokens.
+type lexer struct {
+	input string
+	pos   int
+}
+
+func isFieldChar(c byte, first bool) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '_':
+		return true
+	case c >= '0' && c <= '9', c == '.', c == '-':
+		return !first
+	}
+	return false
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func (l *lexer) next() (token, error) {
+	for l.pos < len(l.input) && strings.IndexByte(" \t\r\n", l.input[l.pos]) >= 0 {
+		l.pos++
+	}
+	start := l.pos
+	if start == len(l.input) {
+		return token{kind: tokEOF, pos: start}, nil
+	}
+	c := l.input[start]
+	switch {
+	case c == '(':
+		l.pos++
+		return token{kind: tokLParen, text: "(", pos: start}, nil
+	case c == ')':
+		l.pos++
+		return token{kind: tokRParen, text: ")", pos: start}, nil
+	case c == '\'' || c == '"':
+		return l.lexString(c)
+	case isDigit(c) || (c == '-' && start+1 < len(l.input) && isDigit(l.input[start+1])):
+		l.pos++
+		for l.pos < len(l.input) && (isDigit(l.input[l.pos]) || strings.IndexByte(".eE+-", l.input[l.pos]) >= 0) {
+			l.pos++
+		}
+		return token{kind: tokNumber, text: l.input[start:l.pos], pos: start}, nil
+	case isFieldChar(c, true):
+		for l.pos < len(l.input) && isFieldChar(l.input[l.pos], false) {
+			l.pos++
+		}
+		text := l.input[start:l.pos]
+		switch strings.ToUpper(text) {
+		case "AND":
+			return token{kind: tokAnd, text: text, pos: start}, nil
+		case "OR":
+			return token{kind: tokOr, text: text, pos: start}, nil
+		case "NOT":
+			return token{kind: tokNot, text: text, pos: start}, nil
+		}
+		return token{kind: tokField, text: text, pos: start}, nil
+	}
+	for _, op := range []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!"} {
+		if strings.HasPrefix(l.input[start:], op) {
+			l.pos += len(op)
+			switch op {
+			case "&&":
+				return token{kind: tokAnd, text: op, pos: start}, nil
+			case "||":
+				return token{kind: tokOr, text: op, pos: start}, nil
+			case "!":
+				return token{kind: tokNot, text: op, pos: start}, nil
+			}
+			return token{kind: tokOp, text: op, pos: start}, nil
+		}
+	}
+	return token{}, fmt.Errorf("invalid filter %q at offset %d: unexpected character %q", l.input, start, c)
+}
+
+// lexString lexes a string quoted with quote, a backslash escapes
+// the following character.
+func (l *lexer) lexString(quote byte) (token, error) {
+	start := l.pos
+	var b strings.Builder
+	for l.pos++; l.pos < len(l.input); l.pos++ {
+		c := l.input[l.pos]
+		switch {
+		case c == '\\' && l.pos+1 < len(l.input):
+			l.pos++
+			b.WriteByte(l.input[l.pos])
+		case c == quote:
+			l.pos++
+			return token{kind: tokString, text: b.String(), pos: start}, nil
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return token{}, fmt.Errorf("invalid filter %q at offset %d: unterminated string", l.input, start)
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// defaultQueueGrowLatency is the delivery latency above which
+// an adaptive queue grows, see Config.QueueMaxSize.
+const defaultQueueGrowLatency = time.Second
+
+// adaptive returns true if the queue is sized adaptively.
+func (h *Target) adaptive() bool {
+	return h.config.QueueMaxSize > h.config.QueueSize && h.config.QueueSize > 0
+}
+
+// queueSize returns the number of entries the queue currently holds at most.
+func (h *Target) queueSize() int {
+	if h.adaptive() {
+		return int(atomic.LoadInt64(&h.queueLimit))
+	}
+	return cap(h.logCh)
+}
+
+// adaptQueue resizes an adaptive queue after a delivery that took
+// latency. The queue doubles, up to QueueMaxSize, while the average
+// latency is above QueueGrowLatency, to absorb the bursts the endpoint
+// is too slow for, and halves, down to QueueSize, once the latency
+// dropped below half of it and the queue is mostly empty.
+func (h *Target) adaptQueue(latency time.Duration) {
+	if !h.adaptive() {
+		return
+	}
+	threshold := h.config.QueueGrowLatency
+	if threshold <= 0 {
+		threshold = defaultQueueGrowLatency
+	}
+	// Smooth out the occasional slow delivery.
+	if h.avgLatency == 0 {
+		h.avgLatency = latency
+	} else {
+		h.avgLatency = (4*h.avgLatency + latency) / 5
+	}
+
+	limit := atomic.LoadInt64(&h.queueLimit)
+	switch {
+	case h.avgLatency >= threshold:
+		limit *= 2
+		if max := int64(h.config.QueueMaxSize); limit > max {
+			limit = max
+		}
+	case h.avgLatency < threshold/2 && int64(len(h.logCh)) < limit/4:
+		limit /= 2
+		if min := int64(h.config.QueueSize); limit < min {
+			limit = min
+		}
+	}
+	atomic.StoreInt64(&h.queueLimit, limit)
+}
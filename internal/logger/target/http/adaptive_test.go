@@ -0,0 +1,78 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"testing"
+	"time"
+
+	"github.com/minio/minio/internal/logger/target/types"
+)
+
+func TestTargetAdaptiveQueue(t *testing.T) {
+	tgt := New(Config{QueueSize: 2, QueueMaxSize: 8, QueueGrowLatency: time.Second})
+	tgt.status = 1
+
+	if size := tgt.Stats().QueueSize; size != 2 {
+		t.Fatalf("expected an initial queue size of 2, got %d", size)
+	}
+	for i := 0; i < 2; i++ {
+		if err := tgt.Send(i, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tgt.Send(2, ""); err != types.ErrLogBufferFull {
+		t.Fatalf("expected the queue to be full, got %v", err)
+	}
+	if tgt.Ready() {
+		t.Fatal("expected the target not to be ready with a full queue")
+	}
+
+	// A slow endpoint grows the queue, up to its maximum size.
+	for _, expected := range []int{4, 8, 8} {
+		tgt.adaptQueue(2 * time.Second)
+		if size := tgt.Stats().QueueSize; size != expected {
+			t.Fatalf("expected a queue size of %d, got %d", expected, size)
+		}
+	}
+	if err := tgt.Send(2, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// It shrinks back once the endpoint is healthy and the queue drained.
+	tgt.adaptQueue(0)
+	if size := tgt.Stats().QueueSize; size != 8 {
+		t.Fatalf("expected the queue not to shrink while the latency is high, got %d", size)
+	}
+	for len(tgt.logCh) > 0 {
+		<-tgt.logCh
+	}
+	for i := 0; i < 20; i++ {
+		tgt.adaptQueue(0)
+	}
+	if size := tgt.Stats().QueueSize; size != 2 {
+		t.Fatalf("expected the queue to shrink back to 2, got %d", size)
+	}
+
+	// Fixed size queues are left as is.
+	tgt = New(Config{QueueSize: 2})
+	tgt.adaptQueue(time.Minute)
+	if size := tgt.Stats().QueueSize; size != 2 {
+		t.Fatalf("expected a fixed queue size of 2, got %d", size)
+	}
+}
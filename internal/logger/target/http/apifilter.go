@@ -0,0 +1,49 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/buger/jsonparser"
+)
+
+// ValidateAPIFilter checks the patterns of APIFilter are well formed.
+func ValidateAPIFilter(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid API filter pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// apiAllowed returns true if a json encoded entry is sent with APIFilter.
+func (h *Target) apiAllowed(logJSON []byte) bool {
+	name, err := jsonparser.GetString(logJSON, "api", "name")
+	if err != nil || name == "" {
+		return !h.config.DropMissingAPI
+	}
+	for _, pattern := range h.config.APIFilter {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestTargetAPIFilter(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		names []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var entry struct {
+			Message string `json:"message"`
+		}
+		json.NewDecoder(r.Body).Decode(&entry)
+		// Skips the probe sent by Init.
+		if entry.Message != "" {
+			mu.Lock()
+			names = append(names, entry.Message)
+			mu.Unlock()
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	entries := []map[string]interface{}{
+		{"message": "put", "api": map[string]string{"name": "PutObject"}},
+		{"message": "get", "api": map[string]string{"name": "GetObject"}},
+		{"message": "delete", "api": map[string]string{"name": "DeleteObject"}},
+		{"message": "missing"},
+	}
+	for _, c := range []struct {
+		dropMissing bool
+		expected    []string
+	}{
+		{false, []string{"put", "delete", "missing"}},
+		{true, []string{"put", "delete"}},
+	} {
+		names = nil
+		tgt := New(Config{
+			Endpoint:       srv.URL,
+			QueueSize:      10,
+			Transport:      http.DefaultTransport,
+			APIFilter:      []string{"Put*", "DeleteObject"},
+			DropMissingAPI: c.dropMissing,
+			LogOnce:        func(context.Context, error, interface{}, ...interface{}) {},
+		})
+		if err := tgt.Init(); err != nil {
+			t.Fatal(err)
+		}
+		for _, entry := range entries {
+			if err := tgt.Send(entry, ""); err != nil {
+				t.Fatal(err)
+			}
+		}
+		tgt.Cancel()
+		if !reflect.DeepEqual(names, c.expected) {
+			t.Fatalf("drop missing %v: expected %v, got %v", c.dropMissing, c.expected, names)
+		}
+	}
+
+	tgt := New(Config{Endpoint: srv.URL, APIFilter: []string{"[Put"}})
+	if err := tgt.Init(); err == nil {
+		t.Fatal("expected the malformed pattern to be rejected")
+	}
+}
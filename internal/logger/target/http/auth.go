@@ -0,0 +1,88 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ErrInsecureAuth is returned instead of sending the AuthToken to a
+// plaintext http endpoint, unless allowed with AllowInsecureAuth.
+var ErrInsecureAuth = errors.New("auth token cannot be sent to a plaintext http endpoint, use https or allow insecure auth")
+
+// IsPlaintextEndpoint returns true if the entries are sent to
+// endpoint without TLS.
+func IsPlaintextEndpoint(endpoint string) bool {
+	u, err := url.Parse(endpoint)
+	return err == nil && u.Scheme == "http"
+}
+
+// insecureAuth returns true if the AuthToken would be sent in
+// plaintext to endpoint.
+func (h *Target) insecureAuth(endpoint string) bool {
+	return h.config.AuthToken != "" && !h.config.AllowInsecureAuth && IsPlaintextEndpoint(endpoint)
+}
+
+// authHeader returns true if the AuthToken is sent in the
+// Authorization header.
+func (h *Target) authHeader() bool {
+	return h.config.AuthToken != "" && (h.config.AuthTokenField == "" || h.config.AuthTokenHeader)
+}
+
+// injectAuthToken sets the AuthToken at the AuthTokenField of a
+// JSON object payload, creating the intermediate objects.
+func (h *Target) injectAuthToken(payload []byte) ([]byte, error) {
+	if h.config.AuthTokenField == "" || h.config.AuthToken == "" {
+		return payload, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+	var root map[string]interface{}
+	if err := dec.Decode(&root); err != nil || root == nil {
+		return nil, errors.New("auth token field requires a JSON object payload")
+	}
+	keys := strings.Split(h.config.AuthTokenField, ".")
+	m := root
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := m[key].(map[string]interface{})
+		if !ok {
+			if _, exists := m[key]; exists {
+				return nil, fmt.Errorf("auth token field %s is not an object path of the payload", h.config.AuthTokenField)
+			}
+			next = make(map[string]interface{})
+			m[key] = next
+		}
+		m = next
+	}
+	m[keys[len(keys)-1]] = h.config.AuthToken
+	return h.marshal(root)
+}
+
+// redactAuthToken replaces the AuthToken in data, e.g. a response
+// echoing the payload, when it is sent in the payload.
+func (h *Target) redactAuthToken(data []byte) []byte {
+	if h.config.AuthTokenField == "" || h.config.AuthToken == "" {
+		return data
+	}
+	return bytes.ReplaceAll(data, []byte(h.config.AuthToken), []byte(redacted))
+}
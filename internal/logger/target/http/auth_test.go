@@ -0,0 +1,205 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestInjectAuthToken(t *testing.T) {
+	testCases := []struct {
+		field     string
+		payload   string
+		expected  string
+		shouldErr bool
+	}{
+		{"", `{"a":1}`, `{"a":1}`, false},
+		{"token", `{"a":1}`, `{"a":1,"token":"secret"}`, false},
+		{"auth.token", `{"a":12345678901234567890}`, `{"a":12345678901234567890,"auth":{"token":"secret"}}`, false},
+		{"auth.token", `{"auth":{"user":"u"}}`, `{"auth":{"token":"secret","user":"u"}}`, false},
+		{"a.token", `{"a":1}`, "", true},
+		{"token", `[1,2]`, "", true},
+	}
+	for i, testCase := range testCases {
+		h := &Target{config: Config{AuthToken: "secret", AuthTokenField: testCase.field}}
+		got, err := h.injectAuthToken([]byte(testCase.payload))
+		if testCase.shouldErr {
+			if err == nil {
+				t.Errorf("Test %d: expected an error, got %s", i+1, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if string(got) != testCase.expected {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.expected, got)
+		}
+	}
+}
+
+func TestTargetAuthTokenField(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		tokens  []string
+		headers []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		var payload struct {
+			Token  string            `json:"token"`
+			Events map[string]string `json:"events"`
+		}
+		if err = json.Unmarshal(body, &payload); err != nil {
+			t.Error(err)
+		}
+		mu.Lock()
+		tokens = append(tokens, payload.Token)
+		headers = append(headers, r.Header.Get("Authorization"))
+		mu.Unlock()
+		if payload.Events["reject"] != "" {
+			// Echo the payload, as some receivers do on errors.
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write(body)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	var logged []error
+	tgt := New(Config{
+		Endpoint:        srv.URL,
+		QueueSize:       10,
+		Transport:       http.DefaultTransport,
+		AuthToken:       "secret",
+		AuthTokenField:  "token",
+		PayloadTemplate: `{"events": {{json .Entry}}}`,
+
+		AllowInsecureAuth: true,
+		LogOnce: func(_ context.Context, err error, _ interface{}, _ ...interface{}) {
+			mu.Lock()
+			logged = append(logged, err)
+			mu.Unlock()
+		},
+	})
+	if err := tgt.Init(); err != nil {
+		t.Fatal(err)
+	}
+	if err := tgt.Send(map[string]string{"a": "b"}, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := tgt.Send(map[string]string{"reject": "yes"}, ""); err != nil {
+		t.Fatal(err)
+	}
+	tgt.Cancel()
+
+	mu.Lock()
+	defer mu.Unlock()
+	// The first request is the probe sent by Init.
+	if len(tokens) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(tokens))
+	}
+	for i := range tokens {
+		if tokens[i] != "secret" || headers[i] != "" {
+			t.Fatalf("request %d: expected the token in the body only, got %q in the body and %q in the header", i+1, tokens[i], headers[i])
+		}
+	}
+	if len(logged) != 1 {
+		t.Fatalf("expected 1 delivery error, got %v", logged)
+	}
+	if msg := logged[0].Error(); strings.Contains(msg, "secret") || !strings.Contains(msg, redacted) {
+		t.Fatalf("expected the token to be redacted from %q", msg)
+	}
+	if stats := tgt.Stats(); strings.Contains(stats.LastErrorMsg, "secret") {
+		t.Fatalf("expected the token to be redacted from %q", stats.LastErrorMsg)
+	}
+}
+
+func TestTargetInsecureAuth(t *testing.T) {
+	testCases := []struct {
+		endpoint          string
+		authToken         string
+		allowInsecureAuth bool
+		expectedErr       error
+	}{
+		{"https://localhost:8080/logs", "token", false, nil},
+		{"http://localhost:8080/logs", "", false, nil},
+		{"http://localhost:8080/logs", "token", false, ErrInsecureAuth},
+		{"http://localhost:8080/logs", "token", true, nil},
+	}
+	for i, testCase := range testCases {
+		tgt := New(Config{
+			Endpoint:          testCase.endpoint,
+			AuthToken:         testCase.authToken,
+			AllowInsecureAuth: testCase.allowInsecureAuth,
+			Transport:         http.DefaultTransport,
+			DisableProbe:      true,
+		})
+		err := tgt.Init()
+		if !errors.Is(err, testCase.expectedErr) {
+			t.Fatalf("Test %d: expected error %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		if err == nil {
+			tgt.Cancel()
+		}
+	}
+}
+
+func TestTargetInsecureAuthRedirect(t *testing.T) {
+	var plaintext int32
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&plaintext, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer plain.Close()
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, plain.URL, http.StatusTemporaryRedirect)
+	}))
+	defer srv.Close()
+
+	tgt := New(Config{
+		Endpoint:     srv.URL,
+		AuthToken:    "secret",
+		Transport:    srv.Client().Transport,
+		DisableProbe: true,
+	})
+	if err := tgt.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer tgt.Cancel()
+	err := tgt.do(context.Background(), srv.URL, strings.NewReader(`{}`), "application/json", "", false)
+	if !errors.Is(err, ErrInsecureAuth) {
+		t.Fatalf("expected %v, got %v", ErrInsecureAuth, err)
+	}
+	if atomic.LoadInt32(&plaintext) != 0 {
+		t.Fatal("expected the auth token not to be sent to the plaintext endpoint")
+	}
+}
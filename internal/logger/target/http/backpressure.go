@@ -0,0 +1,82 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// BackpressureHigh is the value of the BackpressureHeader with which
+// the endpoint signals it is near capacity.
+const BackpressureHigh = "high"
+
+const (
+	// backpressureMinDelay is the delay first applied between the
+	// deliveries once the endpoint signals backpressure.
+	backpressureMinDelay = 50 * time.Millisecond
+
+	// defaultBackpressureMaxDelay is the longest delay applied
+	// between the deliveries, see Config.BackpressureMaxDelay.
+	defaultBackpressureMaxDelay = 5 * time.Second
+)
+
+// updateBackpressure adapts the delay between the deliveries to the
+// BackpressureHeader of a response: it doubles, up to
+// BackpressureMaxDelay, while the endpoint signals backpressure and
+// halves once it cleared, ramping back up to the full rate.
+func (h *Target) updateBackpressure(header http.Header) {
+	if h.config.BackpressureHeader == "" {
+		return
+	}
+	maxDelay := h.config.BackpressureMaxDelay
+	if maxDelay <= 0 {
+		maxDelay = defaultBackpressureMaxDelay
+	}
+	delay := time.Duration(atomic.LoadInt64(&h.backpressureDelay))
+	if strings.EqualFold(strings.TrimSpace(header.Get(h.config.BackpressureHeader)), BackpressureHigh) {
+		delay *= 2
+		if delay < backpressureMinDelay {
+			delay = backpressureMinDelay
+		}
+		if delay > maxDelay {
+			delay = maxDelay
+		}
+	} else {
+		delay /= 2
+		if delay < backpressureMinDelay {
+			delay = 0
+		}
+	}
+	atomic.StoreInt64(&h.backpressureDelay, int64(delay))
+}
+
+// waitBackpressure delays the next delivery while the endpoint
+// signals backpressure, unless the target is canceled and drains
+// its queue.
+func (h *Target) waitBackpressure() {
+	delay := time.Duration(atomic.LoadInt64(&h.backpressureDelay))
+	if delay <= 0 || atomic.LoadInt32(&h.status) != 1 {
+		return
+	}
+	timer := h.clock.NewTimer(delay)
+	defer timer.Stop()
+	<-timer.C()
+}
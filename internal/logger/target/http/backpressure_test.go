@@ -0,0 +1,110 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTargetUpdateBackpressure(t *testing.T) {
+	tgt := New(Config{BackpressureHeader: "X-Backpressure", BackpressureMaxDelay: 300 * time.Millisecond})
+	high := http.Header{"X-Backpressure": []string{"High"}}
+	testCases := []struct {
+		header   http.Header
+		expected time.Duration
+	}{
+		// Slows down further on each signal, up to the maximum delay.
+		{high, 50 * time.Millisecond},
+		{high, 100 * time.Millisecond},
+		{high, 200 * time.Millisecond},
+		{high, 300 * time.Millisecond},
+		{high, 300 * time.Millisecond},
+		// Ramps back up once the signal cleared.
+		{http.Header{}, 150 * time.Millisecond},
+		{http.Header{"X-Backpressure": []string{"low"}}, 75 * time.Millisecond},
+		{http.Header{}, 0},
+		{http.Header{}, 0},
+	}
+	for i, testCase := range testCases {
+		tgt.updateBackpressure(testCase.header)
+		if delay := time.Duration(tgt.backpressureDelay); delay != testCase.expected {
+			t.Fatalf("Test %d: expected a delay of %s, got %s", i+1, testCase.expected, delay)
+		}
+	}
+
+	// Ignored unless configured.
+	tgt = New(Config{})
+	tgt.updateBackpressure(high)
+	if tgt.backpressureDelay != 0 {
+		t.Fatal("expected no delay without a backpressure header")
+	}
+}
+
+func TestTargetBackpressureDelay(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received []time.Time
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		received = append(received, time.Now())
+		mu.Unlock()
+		w.Header().Set("X-Backpressure", BackpressureHigh)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	delivered := make(chan error, 2)
+	tgt := New(Config{
+		Endpoint:           srv.URL,
+		QueueSize:          10,
+		Transport:          http.DefaultTransport,
+		DisableProbe:       true,
+		BackpressureHeader: "X-Backpressure",
+		LogOnce:            func(_ context.Context, err error, _ interface{}, _ ...interface{}) { t.Error(err) },
+		OnDelivered:        func(_ interface{}, err error) { delivered <- err },
+	})
+	if err := tgt.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer tgt.Cancel()
+	for i := 0; i < 2; i++ {
+		if err := tgt.Send(map[string]int{"entry": i}, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if err := <-delivered; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(received))
+	}
+	if gap := received[1].Sub(received[0]); gap < backpressureMinDelay {
+		t.Fatalf("expected the second entry to be delayed by %s, got %s", backpressureMinDelay, gap)
+	}
+}
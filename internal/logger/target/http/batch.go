@@ -0,0 +1,311 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/minio/minio/internal/logger/target/throttle"
+)
+
+// Batch defaults, see Config.BatchMaxBytes and Config.BatchInterval
+const (
+	defaultBatchMaxBytes = 1 * humanize.MiByte
+	defaultBatchInterval = 2 * time.Second
+)
+
+// batching returns true if entries are sent in batches.
+func (h *Target) batching() bool {
+	return (h.config.BatchMaxBytes > 0 || h.config.BatchInterval > 0) && !h.templated()
+}
+
+// batchEntries sends the queued entries in batches of newline
+// delimited JSON, flushed when they reach the maximum size or
+// the batch interval elapsed since their first entry, whichever
+// comes first. The partial batch is flushed once the queue is
+// closed. Entries rejected by a partial failure response are
+// sent again with the next batch.
+func (h *Target) batchEntries() {
+	maxBytes := h.config.BatchMaxBytes
+	if maxBytes <= 0 {
+		maxBytes = defaultBatchMaxBytes
+	}
+	interval := h.config.BatchInterval
+	if interval <= 0 {
+		interval = defaultBatchInterval
+	}
+
+	var (
+		batch  bytes.Buffer
+		stream *batchStream
+		size   int
+		count  int64
+
+		// Entries of the batch
+		pending []batchEntry
+	)
+	// Only runs while the batch holds entries.
+	timer := h.clock.NewTimer(interval)
+	if !timer.Stop() {
+		<-timer.C()
+	}
+	defer timer.Stop()
+
+	flush := func() {
+		if count == 0 {
+			return
+		}
+		if !timer.Stop() {
+			// Drain the timer unless it already fired the flush.
+			select {
+			case <-timer.C():
+			default:
+			}
+		}
+		if stream != nil {
+			err := stream.close()
+			h.record(err, count)
+			for _, e := range pending {
+				h.delivered(e.entry, err)
+			}
+			stream = nil
+			pending = pending[:0]
+			size = 0
+			count = 0
+			return
+		}
+
+		payload := batch.Bytes()
+		throttle.Wait(context.Background(), int(count))
+		h.register()
+		err := h.transmit(h.config.Endpoint, payload, h.contentType(true), h.batchRequestID(), count)
+		retries, retryPayloads := h.recordBatch(err, payload, pending)
+		batch.Reset()
+		pending = pending[:0]
+		size = 0
+		count = 0
+
+		// Rejected entries start the next batch.
+		for i, e := range retries {
+			e.offset = batch.Len()
+			pending = append(pending, e)
+			batch.Write(retryPayloads[i])
+			size += len(retryPayloads[i])
+			count++
+		}
+		if count > 0 {
+			timer.Reset(interval)
+		}
+	}
+
+	lane := h.priorityCh
+	for {
+		entry, high, ok, fired := h.receive(&lane, timer.C())
+		if fired {
+			flush()
+			continue
+		}
+		if !ok {
+			// Until the entries sent again are out of attempts.
+			for count > 0 {
+				flush()
+			}
+			return
+		}
+		h.waitEnabled()
+		h.checkQueueRecovered()
+		if high {
+			// Sent right away, ahead of the batch.
+			h.logEntry(entry, true)
+			continue
+		}
+
+		logJSON, err := h.marshal(&entry)
+		if err != nil {
+			h.delivered(entry, err)
+			continue
+		}
+		if h.expired(logJSON, h.clock.Now()) {
+			h.delivered(entry, ErrEntryExpired)
+			continue
+		}
+		if logJSON, err = h.render(logJSON); err != nil {
+			h.record(err, 1)
+			h.delivered(entry, err)
+			continue
+		}
+		if count > 0 && size+len(logJSON)+1 > maxBytes {
+			flush()
+		}
+		if count == 0 {
+			if h.config.BatchStream {
+				h.waitBackpressure()
+				h.register()
+				stream = h.startBatchStream(interval)
+			}
+			timer.Reset(interval)
+		}
+		logJSON = append(logJSON, '\n')
+		pending = append(pending, batchEntry{entry: entry, offset: batch.Len()})
+		if stream != nil {
+			throttle.Wait(context.Background(), 1)
+			stream.write(logJSON)
+		} else {
+			batch.Write(logJSON)
+		}
+		size += len(logJSON)
+		count++
+		if size >= maxBytes {
+			flush()
+		}
+	}
+}
+
+// batchStream is a batch streamed to the endpoint while its
+// entries are written.
+type batchStream struct {
+	pw *io.PipeWriter
+	gw *gzip.Writer
+	// Returns gw to the pool of the target once the batch is sent.
+	putGzipWriter func(*gzip.Writer)
+	err           error
+	doneCh        chan error
+}
+
+// startBatchStream starts sending a batch, it must be sent within
+// interval once its first entry is written.
+func (h *Target) startBatchStream(interval time.Duration) *batchStream {
+	pr, pw := io.Pipe()
+	s := &batchStream{pw: pw, doneCh: make(chan error, 1)}
+	compress := h.shouldCompress()
+	if compress {
+		s.gw = h.gzipWriter(pw)
+		s.putGzipWriter = h.putGzipWriter
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), interval+webhookCallTimeout)
+		defer cancel()
+		err := h.do(ctx, h.config.Endpoint, pr, h.contentType(true), h.batchRequestID(), compress)
+		switch {
+		case err == errCompressRejected:
+			h.setCompress(compressRejected)
+		case err == nil && compress:
+			h.setCompress(compressAccepted)
+		}
+		// Unblocks the writer if the request ended early.
+		pr.CloseWithError(errBatchStreamAborted)
+		s.doneCh <- err
+	}()
+	return s
+}
+
+// errBatchStreamAborted is returned to writes of a batch
+// whose request already ended.
+var errBatchStreamAborted = errors.New("batch stream aborted")
+
+// write writes an entry to the batch, entries written after
+// an error are dropped and the batch fails as a whole.
+func (s *batchStream) write(logJSON []byte) {
+	if s.err != nil {
+		return
+	}
+	if s.gw != nil {
+		_, s.err = s.gw.Write(logJSON)
+	} else {
+		_, s.err = s.pw.Write(logJSON)
+	}
+}
+
+// close ends the batch and returns the outcome of its request.
+func (s *batchStream) close() error {
+	if s.gw != nil && s.err == nil {
+		s.err = s.gw.Close()
+	}
+	s.pw.CloseWithError(s.err)
+	err := <-s.doneCh
+	if s.gw != nil {
+		s.putGzipWriter(s.gw)
+	}
+	if err == nil && s.err != nil {
+		err = s.err
+	}
+	return err
+}
+
+// batchEntry is an entry of a batch.
+type batchEntry struct {
+	entry    interface{} // as queued, reported to OnDelivered.
+	offset   int         // of the entry in the batch payload.
+	attempts int         // times the entry was sent before.
+}
+
+// recordBatch records the outcome of sending a batch of entries
+// in payload and returns the entries to send again, along with
+// their payloads, their attempts so far are counted.
+func (h *Target) recordBatch(err error, payload []byte, entries []batchEntry) (retries []batchEntry, retryPayloads [][]byte) {
+	count := int64(len(entries))
+	var pf *partialFailureError
+	if !errors.As(err, &pf) {
+		h.record(err, count)
+		for _, e := range entries {
+			h.delivered(e.entry, err)
+		}
+		return nil, nil
+	}
+
+	var failed int64
+	rejected := make([]bool, len(entries))
+	for _, i := range pf.indices {
+		if i < 0 || i >= len(entries) || rejected[i] {
+			continue
+		}
+		rejected[i] = true
+		e := entries[i]
+		if e.attempts+1 >= maxBatchEntryAttempts {
+			failed++
+			h.delivered(e.entry, err)
+			continue
+		}
+		end := len(payload)
+		if i+1 < len(entries) {
+			end = entries[i+1].offset
+		}
+		retryPayloads = append(retryPayloads, append([]byte(nil), payload[e.offset:end]...))
+		e.attempts++
+		retries = append(retries, e)
+	}
+	for i, e := range entries {
+		if !rejected[i] {
+			h.delivered(e.entry, nil)
+		}
+	}
+	if delivered := count - failed - int64(len(retries)); delivered > 0 {
+		h.record(nil, delivered)
+	}
+	if failed > 0 {
+		h.record(err, failed)
+	}
+	return retries, retryPayloads
+}
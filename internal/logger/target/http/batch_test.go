@@ -0,0 +1,208 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// batchServer records the number of entries of each batch received.
+type batchServer struct {
+	*httptest.Server
+	mu      sync.Mutex
+	batches []int
+}
+
+func newBatchServer(t *testing.T) *batchServer {
+	s := &batchServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		// Skip the probe sent by Init.
+		if r.Header.Get("Content-Type") == "application/x-ndjson" {
+			s.mu.Lock()
+			s.batches = append(s.batches, bytes.Count(body, []byte("\n")))
+			s.mu.Unlock()
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	return s
+}
+
+func (s *batchServer) received() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]int(nil), s.batches...)
+}
+
+func newBatchTarget(t *testing.T, endpoint string, maxBytes int, interval time.Duration) *Target {
+	tgt := New(Config{
+		Endpoint:      endpoint,
+		QueueSize:     100,
+		Transport:     http.DefaultTransport,
+		BatchMaxBytes: maxBytes,
+		BatchInterval: interval,
+		LogOnce:       func(_ context.Context, err error, _ interface{}, _ ...interface{}) { t.Error(err) },
+	})
+	if err := tgt.Init(); err != nil {
+		t.Fatal(err)
+	}
+	return tgt
+}
+
+func TestTargetBatchMaxBytes(t *testing.T) {
+	srv := newBatchServer(t)
+	defer srv.Close()
+
+	// Each entry is 8 bytes with its newline, a batch holds 3 of them.
+	tgt := newBatchTarget(t, srv.URL, 30, time.Hour)
+	for i := 0; i < 7; i++ {
+		if err := tgt.Send(map[string]int{"e": i}, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// An oversized entry is sent alone, after flushing the partial batch.
+	if err := tgt.Send(map[string]string{"e": strings.Repeat("x", 50)}, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := tgt.Send(map[string]int{"e": 8}, ""); err != nil {
+		t.Fatal(err)
+	}
+	// The partial batch is flushed on shutdown.
+	tgt.Cancel()
+
+	expected := []int{3, 3, 1, 1, 1}
+	if got := srv.received(); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected batches %v, got %v", expected, got)
+	}
+	if stats := tgt.Stats(); stats.TotalMessages != 9 {
+		t.Fatalf("expected 9 messages, got %d", stats.TotalMessages)
+	}
+}
+
+func TestTargetBatchInterval(t *testing.T) {
+	srv := newBatchServer(t)
+	defer srv.Close()
+
+	tgt := newBatchTarget(t, srv.URL, 1<<20, 100*time.Millisecond)
+	defer tgt.Cancel()
+
+	waitForBatches := func(n int) []int {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			if got := srv.received(); len(got) >= n {
+				return got
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatalf("timed out waiting for %d batches", n)
+		return nil
+	}
+
+	for round := 1; round <= 2; round++ {
+		for i := 0; i < 3; i++ {
+			if err := tgt.Send(map[string]int{"e": i}, ""); err != nil {
+				t.Fatal(err)
+			}
+		}
+		// The timer is reset after each flush.
+		got := waitForBatches(round)
+		if len(got) != round || got[round-1] != 3 {
+			t.Fatalf("round %d: unexpected batches %v", round, got)
+		}
+	}
+}
+
+func TestTargetBatchStream(t *testing.T) {
+	srv := newBatchServer(t)
+	defer srv.Close()
+
+	tgt := New(Config{
+		Endpoint:      srv.URL,
+		QueueSize:     100,
+		Transport:     http.DefaultTransport,
+		BatchMaxBytes: 30,
+		BatchInterval: time.Hour,
+		BatchStream:   true,
+		LogOnce:       func(_ context.Context, err error, _ interface{}, _ ...interface{}) { t.Error(err) },
+	})
+	if err := tgt.Init(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 7; i++ {
+		if err := tgt.Send(map[string]int{"e": i}, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tgt.Cancel()
+
+	expected := []int{3, 3, 1}
+	if got := srv.received(); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected batches %v, got %v", expected, got)
+	}
+	if stats := tgt.Stats(); stats.TotalMessages != 7 || stats.FailedMessages != 0 {
+		t.Fatalf("unexpected stats %#v", stats)
+	}
+}
+
+func TestTargetBatchStreamFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") == "application/x-ndjson" {
+			// Fails the batch without reading it.
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	tgt := New(Config{
+		Endpoint:      srv.URL,
+		QueueSize:     100,
+		Transport:     http.DefaultTransport,
+		BatchMaxBytes: 1 << 20,
+		BatchInterval: time.Hour,
+		BatchStream:   true,
+		LogOnce:       func(_ context.Context, _ error, _ interface{}, _ ...interface{}) {},
+	})
+	if err := tgt.Init(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		if err := tgt.Send(map[string]string{"e": strings.Repeat("x", 1<<10)}, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tgt.Cancel()
+
+	if stats := tgt.Stats(); stats.TotalMessages != 5 || stats.FailedMessages != 5 {
+		t.Fatalf("expected the whole batch to fail, got %#v", stats)
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"errors"
+
+	xhttp "github.com/minio/minio/internal/http"
+	"github.com/minio/minio/internal/logger/message/cef"
+)
+
+// Payload formats, see Config.Format
+const (
+	FormatJSON = "json"
+	FormatCEF  = "cef"
+)
+
+// initFormat validates the Format and prepares
+// the CEF formatter if entries are sent in CEF.
+func (h *Target) initFormat() error {
+	switch h.config.Format {
+	case "", FormatJSON:
+		return nil
+	case FormatCEF:
+	default:
+		return errors.New("invalid format " + h.config.Format + ", expected json or cef")
+	}
+	if h.config.PayloadTemplate != "" || h.config.AuthTokenField != "" || h.config.PartialFailureField != "" {
+		return errors.New("a payload template, auth token field or partial failure field cannot be used with the cef format")
+	}
+	var fields []cef.Field
+	if h.config.CEFFields != "" {
+		var err error
+		if fields, err = cef.ParseFields(h.config.CEFFields); err != nil {
+			return err
+		}
+	}
+	h.cef = cef.NewFormatter(xhttp.GlobalMinIOVersion, fields)
+	return nil
+}
+
+// contentType returns the content type of the payloads of single
+// entries, batches are newline delimited payloads.
+func (h *Target) contentType(batch bool) string {
+	switch {
+	case h.cef != nil:
+		return "text/plain"
+	case batch:
+		return "application/x-ndjson"
+	default:
+		return "application/json"
+	}
+}
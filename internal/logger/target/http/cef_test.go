@@ -0,0 +1,94 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTargetFormatCEF(t *testing.T) {
+	for _, batch := range []bool{false, true} {
+		var (
+			mu       sync.Mutex
+			payloads []string
+		)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ct := r.Header.Get("Content-Type"); ct != "text/plain" {
+				t.Errorf("expected text/plain payloads, got %s", ct)
+			}
+			body, _ := ioutil.ReadAll(r.Body)
+			mu.Lock()
+			payloads = append(payloads, string(body))
+			mu.Unlock()
+			w.WriteHeader(http.StatusOK)
+		}))
+
+		config := Config{
+			Endpoint:  srv.URL,
+			QueueSize: 10,
+			Transport: http.DefaultTransport,
+			Format:    FormatCEF,
+			CEFFields: "cs1=api.bucket",
+			LogOnce:   func(_ context.Context, err error, _ interface{}, _ ...interface{}) { t.Error(err) },
+		}
+		if batch {
+			config.BatchMaxBytes = 1 << 20
+			config.BatchInterval = time.Hour
+		}
+		tgt := New(config)
+		if err := tgt.Init(); err != nil {
+			t.Fatal(err)
+		}
+		for _, bucket := range []string{"a|b", "c=d"} {
+			entry := map[string]interface{}{"api": map[string]interface{}{"name": "PutObject", "bucket": bucket, "statusCode": 200}}
+			if err := tgt.Send(entry, ""); err != nil {
+				t.Fatal(err)
+			}
+		}
+		tgt.Cancel()
+		srv.Close()
+
+		// Skip the probe sent by Init.
+		got := strings.Join(payloads[1:], "\n")
+		expected := "CEF:0|MinIO|MinIO||PutObject|PutObject|3|cs1=a|b cs1Label=bucket\n" +
+			"CEF:0|MinIO|MinIO||PutObject|PutObject|3|cs1=c\\=d cs1Label=bucket"
+		if batch {
+			expected += "\n"
+		}
+		if got != expected {
+			t.Fatalf("batch=%v: expected\n%s\ngot\n%s", batch, expected, got)
+		}
+	}
+
+	for _, config := range []Config{
+		{Endpoint: "http://localhost", Format: "xml"},
+		{Endpoint: "http://localhost", Format: FormatCEF, CEFFields: "src"},
+		{Endpoint: "http://localhost", Format: FormatCEF, PayloadTemplate: `{"event": {{json .Entry}}}`},
+	} {
+		if err := New(config).initFormat(); err == nil {
+			t.Fatalf("expected config %+v to be rejected", config)
+		}
+	}
+}
@@ -0,0 +1,259 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a clock only moving forward when advanced,
+// firing the timers and tickers whose deadline passed.
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	timers  []*fakeTimer
+	tickers []*fakeTicker
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) NewTimer(d time.Duration) clockTimer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTimer{clock: c, c: make(chan time.Time, 1), deadline: c.now.Add(d), active: true}
+	c.timers = append(c.timers, t)
+	return t
+}
+
+func (c *fakeClock) NewTicker(d time.Duration) clockTicker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTicker{clock: c, c: make(chan time.Time, 1), period: d, next: c.now.Add(d), active: true}
+	c.tickers = append(c.tickers, t)
+	return t
+}
+
+// Advance moves the clock forward by d.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	for _, t := range c.timers {
+		if t.active && !t.deadline.After(c.now) {
+			t.active = false
+			fire(t.c, c.now)
+		}
+	}
+	for _, t := range c.tickers {
+		for t.active && !t.next.After(c.now) {
+			t.next = t.next.Add(t.period)
+			fire(t.c, c.now)
+		}
+	}
+}
+
+// activeTimers returns the number of timers not yet fired or stopped.
+func (c *fakeClock) activeTimers() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := 0
+	for _, t := range c.timers {
+		if t.active {
+			n++
+		}
+	}
+	return n
+}
+
+// fire delivers now unless a previous tick is still pending, like the
+// time package does.
+func fire(c chan time.Time, now time.Time) {
+	select {
+	case c <- now:
+	default:
+	}
+}
+
+type fakeTimer struct {
+	clock    *fakeClock
+	c        chan time.Time
+	deadline time.Time
+	active   bool
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	wasActive := t.active
+	t.deadline = t.clock.now.Add(d)
+	t.active = true
+	return wasActive
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	wasActive := t.active
+	t.active = false
+	return wasActive
+}
+
+type fakeTicker struct {
+	clock  *fakeClock
+	c      chan time.Time
+	period time.Duration
+	next   time.Time
+	active bool
+}
+
+func (t *fakeTicker) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTicker) Stop() {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	t.active = false
+}
+
+func TestTargetCompressReprobeClock(t *testing.T) {
+	clock := newFakeClock()
+	tgt := New(Config{Compress: true})
+	tgt.clock = clock
+
+	if !tgt.shouldCompress() {
+		t.Fatal("expected entries to be compressed before any rejection")
+	}
+	tgt.setCompress(compressRejected)
+	if tgt.shouldCompress() {
+		t.Fatal("expected entries to be sent uncompressed after a rejection")
+	}
+	clock.Advance(compressReprobeInterval - time.Second)
+	if tgt.shouldCompress() {
+		t.Fatal("expected entries to be sent uncompressed before the reprobe interval")
+	}
+	clock.Advance(time.Second)
+	if !tgt.shouldCompress() {
+		t.Fatal("expected compression to be probed again after the reprobe interval")
+	}
+}
+
+func TestTargetBatchIntervalClock(t *testing.T) {
+	srv := newBatchServer(t)
+	defer srv.Close()
+
+	clock := newFakeClock()
+	tgt := New(Config{
+		Endpoint:      srv.URL,
+		QueueSize:     100,
+		Transport:     http.DefaultTransport,
+		BatchMaxBytes: 1 << 20,
+		BatchInterval: time.Minute,
+	})
+	tgt.clock = clock
+	if err := tgt.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer tgt.Cancel()
+
+	if err := tgt.Send(map[string]int{"e": 1}, ""); err != nil {
+		t.Fatal(err)
+	}
+	// Wait for the entry to be batched, which arms the batch timer.
+	deadline := time.Now().Add(5 * time.Second)
+	for clock.activeTimers() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the batch timer")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	clock.Advance(time.Minute - time.Second)
+	if got := srv.received(); len(got) != 0 {
+		t.Fatalf("expected no batch before the interval, got %v", got)
+	}
+	clock.Advance(time.Second)
+	for len(srv.received()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the batch")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if got := srv.received(); !reflect.DeepEqual(got, []int{1}) {
+		t.Fatalf("expected batches [1], got %v", got)
+	}
+}
+
+func TestTargetSecondsSinceLastSuccess(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	clock := newFakeClock()
+	delivered := make(chan error, 1)
+	tgt := New(Config{
+		Endpoint:    srv.URL,
+		QueueSize:   10,
+		Transport:   http.DefaultTransport,
+		LogOnce:     func(context.Context, error, interface{}, ...interface{}) {},
+		OnDelivered: func(_ interface{}, err error) { delivered <- err },
+	})
+	tgt.clock = clock
+	if n := tgt.Stats().SecondsSinceLastSuccess; n != 0 {
+		t.Fatalf("expected 0 before Init, got %d", n)
+	}
+	if err := tgt.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer tgt.Cancel()
+
+	// Counted from Init until a first delivery.
+	clock.Advance(90 * time.Second)
+	if n := tgt.Stats().SecondsSinceLastSuccess; n != 90 {
+		t.Fatalf("expected 90, got %d", n)
+	}
+	if err := tgt.Send(map[string]string{"message": "entry"}, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-delivered; err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(30 * time.Second)
+	if n := tgt.Stats().SecondsSinceLastSuccess; n != 30 {
+		t.Fatalf("expected 30, got %d", n)
+	}
+}
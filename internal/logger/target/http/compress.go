@@ -0,0 +1,120 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Interval after which an endpoint which rejected
+// compressed entries is probed again.
+const compressReprobeInterval = 10 * time.Minute
+
+// Whether the endpoint accepts gzip compressed entries.
+const (
+	compressUnknown int32 = iota
+	compressAccepted
+	compressRejected
+)
+
+// errCompressRejected is returned when the endpoint
+// rejects a compressed entry with 415.
+var errCompressRejected = errors.New("compressed entry rejected")
+
+// shouldCompress returns true if the next entry should be
+// compressed, endpoints which rejected compressed entries
+// are probed again after compressReprobeInterval.
+func (h *Target) shouldCompress() bool {
+	if !h.config.Compress {
+		return false
+	}
+	if atomic.LoadInt32(&h.compressState) != compressRejected {
+		return true
+	}
+	decidedAt := time.Unix(0, atomic.LoadInt64(&h.compressDecidedAt))
+	return h.clock.Now().Sub(decidedAt) >= compressReprobeInterval
+}
+
+// setCompress caches whether the endpoint accepts compressed entries.
+func (h *Target) setCompress(state int32) {
+	if atomic.SwapInt32(&h.compressState, state) != state || state == compressRejected {
+		atomic.StoreInt64(&h.compressDecidedAt, h.clock.Now().UnixNano())
+	}
+}
+
+// acceptsGzip returns true if the response advertises gzip support.
+func acceptsGzip(resp *http.Response) bool {
+	for _, encoding := range strings.Split(resp.Header.Get("Accept-Encoding"), ",") {
+		if strings.EqualFold(strings.TrimSpace(encoding), "gzip") {
+			return true
+		}
+	}
+	return false
+}
+
+// validateCompressionLevel validates the CompressionLevel,
+// 0 standing for gzip.DefaultCompression.
+func validateCompressionLevel(level int) error {
+	if level < gzip.HuffmanOnly || level > gzip.BestCompression {
+		return fmt.Errorf("invalid compression level %d, expected %d to %d", level, gzip.HuffmanOnly, gzip.BestCompression)
+	}
+	return nil
+}
+
+// gzipWriter returns a writer compressing to w at the CompressionLevel,
+// taken from the pool of the target, see putGzipWriter.
+func (h *Target) gzipWriter(w io.Writer) *gzip.Writer {
+	if gw, ok := h.gzipPool.Get().(*gzip.Writer); ok {
+		gw.Reset(w)
+		return gw
+	}
+	level := h.config.CompressionLevel
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+	// The level is validated on Init.
+	gw, _ := gzip.NewWriterLevel(w, level)
+	return gw
+}
+
+// putGzipWriter returns a writer to the pool of the target.
+func (h *Target) putGzipWriter(gw *gzip.Writer) {
+	gw.Reset(nil)
+	h.gzipPool.Put(gw)
+}
+
+func (h *Target) gzipCompress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	gw := h.gzipWriter(&buf)
+	defer h.putGzipWriter(gw)
+	if _, err := gw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := gw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,236 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTargetCompressNegotiation(t *testing.T) {
+	testCases := []struct {
+		name               string
+		acceptGzip         bool
+		expectedCompressed int32
+		expectedPlain      int32
+		expectedRejected   int32
+	}{
+		// Every entry is sent compressed.
+		{"accepted", true, 3, 0, 0},
+		// First entry is rejected and sent again uncompressed,
+		// the following ones are sent uncompressed right away.
+		{"rejected", false, 0, 3, 1},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var compressed, plain, rejected int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var entry map[string]interface{}
+				if r.Header.Get("Content-Encoding") == "gzip" {
+					if !testCase.acceptGzip {
+						atomic.AddInt32(&rejected, 1)
+						w.WriteHeader(http.StatusUnsupportedMediaType)
+						return
+					}
+					gr, err := gzip.NewReader(r.Body)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					if err = json.NewDecoder(gr).Decode(&entry); err != nil {
+						t.Error(err)
+					}
+					if len(entry) > 0 {
+						atomic.AddInt32(&compressed, 1)
+					}
+				} else {
+					if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+						t.Error(err)
+					}
+					if len(entry) > 0 {
+						atomic.AddInt32(&plain, 1)
+					}
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer srv.Close()
+
+			tgt := New(Config{
+				Endpoint:  srv.URL,
+				QueueSize: 10,
+				Transport: http.DefaultTransport,
+				Compress:  true,
+				LogOnce:   func(_ context.Context, err error, _ interface{}, _ ...interface{}) { t.Error(err) },
+			})
+			if err := tgt.Init(); err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < 3; i++ {
+				if err := tgt.Send(map[string]int{"entry": i}, ""); err != nil {
+					t.Fatal(err)
+				}
+			}
+			tgt.Cancel()
+
+			if got := atomic.LoadInt32(&compressed); got != testCase.expectedCompressed {
+				t.Errorf("expected %d compressed entries, got %d", testCase.expectedCompressed, got)
+			}
+			if got := atomic.LoadInt32(&plain); got != testCase.expectedPlain {
+				t.Errorf("expected %d uncompressed entries, got %d", testCase.expectedPlain, got)
+			}
+			if got := atomic.LoadInt32(&rejected); got != testCase.expectedRejected {
+				t.Errorf("expected %d rejected entries, got %d", testCase.expectedRejected, got)
+			}
+		})
+	}
+}
+
+func TestTargetCompressReprobe(t *testing.T) {
+	tgt := New(Config{Compress: true})
+	if !tgt.shouldCompress() {
+		t.Fatal("expected compression to be attempted while unknown")
+	}
+	tgt.setCompress(compressRejected)
+	if tgt.shouldCompress() {
+		t.Fatal("expected compression to be disabled once rejected")
+	}
+	atomic.StoreInt64(&tgt.compressDecidedAt, time.Now().Add(-compressReprobeInterval).UnixNano())
+	if !tgt.shouldCompress() {
+		t.Fatal("expected compression to be probed again")
+	}
+}
+
+func TestTargetCompressionLevel(t *testing.T) {
+	testCases := []struct {
+		level       int
+		expectedErr bool
+	}{
+		{0, false},
+		{gzip.HuffmanOnly, false},
+		{gzip.NoCompression, false},
+		{gzip.BestSpeed, false},
+		{gzip.BestCompression, false},
+		{gzip.HuffmanOnly - 1, true},
+		{gzip.BestCompression + 1, true},
+	}
+	for _, testCase := range testCases {
+		tgt := New(Config{
+			Endpoint:         "http://localhost:9000",
+			Transport:        http.DefaultTransport,
+			Compress:         true,
+			CompressionLevel: testCase.level,
+			DisableProbe:     true,
+		})
+		err := tgt.Init()
+		if testCase.expectedErr != (err != nil) {
+			t.Fatalf("level %d: expected error %v, got %v", testCase.level, testCase.expectedErr, err)
+		}
+		if err != nil {
+			continue
+		}
+		tgt.Cancel()
+
+		// Writers taken back from the pool must compress
+		// as freshly created ones.
+		for i := 0; i < 2; i++ {
+			body, err := tgt.gzipCompress([]byte(`{"entry":1}`))
+			if err != nil {
+				t.Fatal(err)
+			}
+			gr, err := gzip.NewReader(bytes.NewReader(body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := ioutil.ReadAll(gr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != `{"entry":1}` {
+				t.Fatalf("level %d: unexpected entry %s", testCase.level, data)
+			}
+		}
+	}
+}
+
+// BenchmarkGzipCompressLevels reports the cost and the ratio of
+// each compression level on a typical audit entry.
+func BenchmarkGzipCompressLevels(b *testing.B) {
+	entry := map[string]interface{}{
+		"version":      "1",
+		"deploymentid": "9b3e8a0e-39f5-4a5b-9f0c-3c2d1b0e6c1a",
+		"time":         "2021-06-01T10:00:00.000000000Z",
+		"trigger":      "incoming",
+		"api": map[string]interface{}{
+			"name":            "PutObject",
+			"bucket":          "photos",
+			"object":          "2021/06/01/IMG_0001.jpg",
+			"status":          "OK",
+			"statusCode":      200,
+			"timeToResponse":  "12345678ns",
+			"rx":              524288,
+			"tx":              0,
+			"timeToFirstByte": "0s",
+		},
+		"remotehost": "10.0.0.12",
+		"requestID":  "16A3B4C5D6E7F801",
+		"userAgent":  "MinIO (linux; amd64) minio-go/v7.0.11",
+		"requestHeader": map[string]string{
+			"Authorization":        "AWS4-HMAC-SHA256 Credential=minio/20210601/us-east-1/s3/aws4_request",
+			"Content-Type":         "image/jpeg",
+			"X-Amz-Content-Sha256": "UNSIGNED-PAYLOAD",
+			"X-Amz-Date":           "20210601T100000Z",
+		},
+		"responseHeader": map[string]string{
+			"Content-Length":   "0",
+			"ETag":             "\"d41d8cd98f00b204e9800998ecf8427e\"",
+			"Server":           "MinIO",
+			"X-Amz-Request-Id": "16A3B4C5D6E7F801",
+		},
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		b.Fatal(err)
+	}
+	levels := []int{gzip.HuffmanOnly, gzip.BestSpeed, gzip.DefaultCompression, gzip.BestCompression}
+	for _, level := range levels {
+		b.Run(fmt.Sprintf("level=%d", level), func(b *testing.B) {
+			tgt := New(Config{CompressionLevel: level})
+			var size int
+			b.SetBytes(int64(len(data)))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				body, err := tgt.gzipCompress(data)
+				if err != nil {
+					b.Fatal(err)
+				}
+				size = len(body)
+			}
+			b.ReportMetric(float64(len(data))/float64(size), "ratio")
+		})
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"hash/fnv"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Default number of distinct entries held while deduplicating.
+const defaultDedupCacheSize = 1000
+
+// dedupEntry is a single distinct entry held by the dedup cache.
+type dedupEntry struct {
+	data  []byte // marshaled entry
+	first time.Time
+	count int
+}
+
+// dedupCache collapses identical entries seen within a time window
+// into a single entry carrying the number of times it was seen.
+type dedupCache struct {
+	sync.Mutex
+	window  time.Duration
+	maxSize int
+	entries map[uint64]*dedupEntry
+
+	// stampReceivedAt stamps entries with the time they were first seen.
+	stampReceivedAt bool
+}
+
+func newDedupCache(window time.Duration, maxSize int) *dedupCache {
+	if maxSize <= 0 {
+		maxSize = defaultDedupCacheSize
+	}
+	return &dedupCache{
+		window:  window,
+		maxSize: maxSize,
+		entries: make(map[uint64]*dedupEntry, maxSize),
+	}
+}
+
+// add records the marshaled entry, returns false if the entry
+// could not be held by the cache and must be forwarded as is.
+func (d *dedupCache) add(data []byte, now time.Time) bool {
+	h := fnv.New64a()
+	h.Write(data)
+	key := h.Sum64()
+
+	d.Lock()
+	defer d.Unlock()
+
+	if e, ok := d.entries[key]; ok {
+		if !bytes.Equal(e.data, data) {
+			// Hash collision, do not collapse different entries.
+			return false
+		}
+		e.count++
+		return true
+	}
+	if len(d.entries) >= d.maxSize {
+		return false
+	}
+	d.entries[key] = &dedupEntry{data: data, first: now, count: 1}
+	return true
+}
+
+// expired removes and returns all entries whose window has elapsed.
+func (d *dedupCache) expired(now time.Time) (entries []json.RawMessage) {
+	d.Lock()
+	defer d.Unlock()
+	for key, e := range d.entries {
+		if now.Sub(e.first) >= d.window {
+			entries = append(entries, e.payload(d.stampReceivedAt))
+			delete(d.entries, key)
+		}
+	}
+	return entries
+}
+
+// drain removes and returns all entries held by the cache.
+func (d *dedupCache) drain() (entries []json.RawMessage) {
+	d.Lock()
+	defer d.Unlock()
+	for key, e := range d.entries {
+		entries = append(entries, e.payload(d.stampReceivedAt))
+		delete(d.entries, key)
+	}
+	return entries
+}
+
+// payload returns the entry to be forwarded, with the
+// repeat_count attached when it was seen more than once
+// and stamped with the time it was first seen if stamp.
+func (e *dedupEntry) payload(stamp bool) json.RawMessage {
+	data := e.data
+	if stamp {
+		data = stampReceivedAt(data, e.first)
+	}
+	if e.count <= 1 {
+		return data
+	}
+	return addJSONField(data, "repeat_count", []byte(strconv.Itoa(e.count)))
+}
+
+// addJSONField appends the key with an already encoded value to
+// a marshaled JSON object, data is returned unmodified if it is
+// not a JSON object.
+func addJSONField(data []byte, key string, value []byte) []byte {
+	data = bytes.TrimSpace(data)
+	if len(data) < 2 || data[0] != '{' || data[len(data)-1] != '}' {
+		return data
+	}
+	buf := make([]byte, 0, len(data)+len(key)+len(value)+4)
+	buf = append(buf, data[:len(data)-1]...)
+	if len(bytes.TrimSpace(data[1:len(data)-1])) > 0 {
+		buf = append(buf, ',')
+	}
+	buf = strconv.AppendQuote(buf, key)
+	buf = append(buf, ':')
+	buf = append(buf, value...)
+	return append(buf, '}')
+}
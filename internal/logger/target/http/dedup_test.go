@@ -0,0 +1,140 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAddJSONField(t *testing.T) {
+	testCases := []struct {
+		data     string
+		expected string
+	}{
+		{`{"a":1}`, `{"a":1,"repeat_count":3}`},
+		{`{}`, `{"repeat_count":3}`},
+		{`{ }`, `{ "repeat_count":3}`},
+		{`[1,2]`, `[1,2]`},
+		{`"str"`, `"str"`},
+	}
+	for i, testCase := range testCases {
+		got := string(addJSONField([]byte(testCase.data), "repeat_count", []byte("3")))
+		if got != testCase.expected {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.expected, got)
+		}
+	}
+}
+
+func TestDedupCache(t *testing.T) {
+	now := time.Now()
+	d := newDedupCache(time.Second, 2)
+
+	if !d.add([]byte(`{"a":1}`), now) {
+		t.Fatal("expected first entry to be held")
+	}
+	for i := 0; i < 4; i++ {
+		if !d.add([]byte(`{"a":1}`), now) {
+			t.Fatal("expected duplicate entry to be collapsed")
+		}
+	}
+	if !d.add([]byte(`{"b":1}`), now.Add(500*time.Millisecond)) {
+		t.Fatal("expected second entry to be held")
+	}
+	// Cache is full, new distinct entries are forwarded as is.
+	if d.add([]byte(`{"c":1}`), now) {
+		t.Fatal("expected entry to be rejected when cache is full")
+	}
+
+	if entries := d.expired(now.Add(500 * time.Millisecond)); len(entries) != 0 {
+		t.Fatalf("expected no expired entries, got %d", len(entries))
+	}
+
+	entries := d.expired(now.Add(time.Second))
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 expired entry, got %d", len(entries))
+	}
+	if string(entries[0]) != `{"a":1,"repeat_count":5}` {
+		t.Fatalf("unexpected expired entry %s", entries[0])
+	}
+
+	entries = d.drain()
+	if len(entries) != 1 || string(entries[0]) != `{"b":1}` {
+		t.Fatalf("unexpected drained entries %s", entries)
+	}
+}
+
+func TestTargetDedupFlush(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received []map[string]interface{}
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		var m map[string]interface{}
+		if err := json.Unmarshal(body, &m); err == nil && len(m) > 0 {
+			mu.Lock()
+			received = append(received, m)
+			mu.Unlock()
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	tgt := New(Config{
+		Endpoint:    srv.URL,
+		QueueSize:   10,
+		DedupWindow: 50 * time.Millisecond,
+		Transport:   http.DefaultTransport,
+	})
+	if err := tgt.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 10; i++ {
+		if err := tgt.Send(map[string]string{"message": "storm"}, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		n := len(received)
+		mu.Unlock()
+		if n > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	tgt.Cancel()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != 1 {
+		t.Fatalf("expected 1 entry to be delivered, got %d", len(received))
+	}
+	if received[0]["repeat_count"] != float64(10) {
+		t.Fatalf("expected repeat_count 10, got %v", received[0]["repeat_count"])
+	}
+}
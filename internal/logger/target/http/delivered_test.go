@@ -0,0 +1,94 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTargetOnDelivered(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if bytes.Contains(body, []byte("reject")) {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	for _, batch := range []bool{false, true} {
+		var (
+			mu       sync.Mutex
+			outcomes = make(map[string][]error)
+		)
+		config := Config{
+			Endpoint:    srv.URL,
+			QueueSize:   10,
+			Transport:   http.DefaultTransport,
+			MaxEntryAge: time.Minute,
+			LogOnce:     func(context.Context, error, interface{}, ...interface{}) {},
+			OnDelivered: func(entry interface{}, err error) {
+				mu.Lock()
+				defer mu.Unlock()
+				name := entry.(map[string]interface{})["name"].(string)
+				outcomes[name] = append(outcomes[name], err)
+			},
+		}
+		if batch {
+			// Batches of a single entry.
+			config.BatchMaxBytes = 1
+			config.BatchInterval = time.Hour
+		}
+		tgt := New(config)
+		if err := tgt.Init(); err != nil {
+			t.Fatal(err)
+		}
+		for _, entry := range []map[string]interface{}{
+			{"name": "sent"},
+			{"name": "reject"},
+			{"name": "expired", "time": time.Now().Add(-time.Hour)},
+		} {
+			if err := tgt.Send(entry, ""); err != nil {
+				t.Fatal(err)
+			}
+		}
+		tgt.Cancel()
+
+		if len(outcomes) != 3 {
+			t.Fatalf("batch=%v: expected the outcome of 3 entries, got %v", batch, outcomes)
+		}
+		if !reflect.DeepEqual(outcomes["sent"], []error{nil}) {
+			t.Errorf("batch=%v: expected the entry to be delivered once, got %v", batch, outcomes["sent"])
+		}
+		if errs := outcomes["reject"]; len(errs) != 1 || errs[0] == nil {
+			t.Errorf("batch=%v: expected the entry to fail once, got %v", batch, errs)
+		}
+		if !reflect.DeepEqual(outcomes["expired"], []error{ErrEntryExpired}) {
+			t.Errorf("batch=%v: expected the entry to expire once, got %v", batch, outcomes["expired"])
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+)
+
+// dnsCache caches the addresses of the resolved hosts for a TTL,
+// rotating through them on every lookup.
+type dnsCache struct {
+	sync.Mutex
+	ttl    time.Duration
+	lookup func(ctx context.Context, host string) ([]string, error)
+	hosts  map[string]*dnsCacheEntry
+}
+
+type dnsCacheEntry struct {
+	addrs   []string
+	expires time.Time
+	next    int
+}
+
+func newDNSCache(ttl time.Duration) *dnsCache {
+	return &dnsCache{
+		ttl:    ttl,
+		lookup: net.DefaultResolver.LookupHost,
+		hosts:  make(map[string]*dnsCacheEntry),
+	}
+}
+
+// lookupHost returns the addresses of host, starting with
+// the address following the one returned first last time.
+func (c *dnsCache) lookupHost(ctx context.Context, host string) ([]string, error) {
+	c.Lock()
+	entry, ok := c.hosts[host]
+	if !ok || time.Now().After(entry.expires) {
+		c.Unlock()
+		addrs, err := c.lookup(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		c.Lock()
+		entry = &dnsCacheEntry{addrs: addrs, expires: time.Now().Add(c.ttl)}
+		c.hosts[host] = entry
+	}
+	start := entry.next % len(entry.addrs)
+	entry.next++
+	addrs := append(append(make([]string, 0, len(entry.addrs)), entry.addrs[start:]...), entry.addrs[:start]...)
+	c.Unlock()
+	return addrs, nil
+}
+
+// invalidate removes host from the cache.
+func (c *dnsCache) invalidate(host string) {
+	c.Lock()
+	delete(c.hosts, host)
+	c.Unlock()
+}
+
+// dialContext returns a dial function resolving hosts through the
+// cache and dialing their addresses in turn with dial, the host is
+// invalidated when none of its addresses could be dialed.
+func (c *dnsCache) dialContext(dial func(ctx context.Context, network, addr string) (net.Conn, error)) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		if net.ParseIP(host) != nil {
+			// For IP only endpoints there is no need for DNS lookups.
+			return dial(ctx, network, addr)
+		}
+
+		addrs, err := c.lookupHost(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, ip := range addrs {
+			var conn net.Conn
+			if conn, err = dial(ctx, network, net.JoinHostPort(ip, port)); err == nil {
+				return conn, nil
+			}
+		}
+		c.invalidate(host)
+		return nil, err
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDNSCacheLookupHost(t *testing.T) {
+	var lookups int
+	c := newDNSCache(time.Minute)
+	c.lookup = func(_ context.Context, host string) ([]string, error) {
+		lookups++
+		return []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"}, nil
+	}
+
+	expected := [][]string{
+		{"10.0.0.1", "10.0.0.2", "10.0.0.3"},
+		{"10.0.0.2", "10.0.0.3", "10.0.0.1"},
+		{"10.0.0.3", "10.0.0.1", "10.0.0.2"},
+		{"10.0.0.1", "10.0.0.2", "10.0.0.3"},
+	}
+	for i, addrs := range expected {
+		got, err := c.lookupHost(context.Background(), "logs.example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, addrs) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, addrs, got)
+		}
+	}
+	if lookups != 1 {
+		t.Fatalf("expected 1 lookup, got %d", lookups)
+	}
+
+	// Expired entries are resolved again.
+	c.hosts["logs.example.com"].expires = time.Now().Add(-time.Second)
+	if _, err := c.lookupHost(context.Background(), "logs.example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if lookups != 2 {
+		t.Fatalf("expected 2 lookups, got %d", lookups)
+	}
+}
+
+func TestDNSCacheDialContext(t *testing.T) {
+	var lookups int
+	c := newDNSCache(time.Minute)
+	c.lookup = func(_ context.Context, host string) ([]string, error) {
+		lookups++
+		return []string{"10.0.0.1", "10.0.0.2"}, nil
+	}
+
+	var dialed []string
+	failing := map[string]bool{}
+	dial := c.dialContext(func(_ context.Context, _, addr string) (net.Conn, error) {
+		dialed = append(dialed, addr)
+		if failing[addr] {
+			return nil, errors.New("connection refused")
+		}
+		client, server := net.Pipe()
+		server.Close()
+		return client, nil
+	})
+
+	// Scheme default ports are already part of addr.
+	conn, err := dial(context.Background(), "tcp", "logs.example.com:443")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+	if !reflect.DeepEqual(dialed, []string{"10.0.0.1:443"}) {
+		t.Fatalf("unexpected dialed addresses %v", dialed)
+	}
+
+	// Next address is dialed when the first one fails.
+	dialed = nil
+	failing["10.0.0.2:443"] = true
+	conn, err = dial(context.Background(), "tcp", "logs.example.com:443")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+	if !reflect.DeepEqual(dialed, []string{"10.0.0.2:443", "10.0.0.1:443"}) {
+		t.Fatalf("unexpected dialed addresses %v", dialed)
+	}
+
+	// Host is invalidated when all its addresses fail.
+	failing["10.0.0.1:443"] = true
+	if _, err = dial(context.Background(), "tcp", "logs.example.com:443"); err == nil {
+		t.Fatal("expected dial to fail")
+	}
+	if _, ok := c.hosts["logs.example.com"]; ok {
+		t.Fatal("expected host to be invalidated")
+	}
+
+	// IP addresses are dialed as is.
+	dialed = nil
+	delete(failing, "10.0.0.1:443")
+	if conn, err = dial(context.Background(), "tcp", "10.0.0.1:443"); err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+	if lookups != 1 || !reflect.DeepEqual(dialed, []string{"10.0.0.1:443"}) {
+		t.Fatalf("unexpected lookups %d, dialed addresses %v", lookups, dialed)
+	}
+}
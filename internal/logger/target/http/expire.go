@@ -0,0 +1,48 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// ErrEntryExpired is reported to OnDelivered for the
+// entries dropped because they are older than MaxEntryAge.
+var ErrEntryExpired = errors.New("entry expired before it was sent")
+
+// expired returns true and counts the marshaled entry as expired if it
+// is older than MaxEntryAge, entries without a time field never expire.
+func (h *Target) expired(logJSON []byte, now time.Time) bool {
+	if h.config.MaxEntryAge <= 0 {
+		return false
+	}
+	var entry struct {
+		Time time.Time `json:"time"`
+	}
+	if err := json.Unmarshal(logJSON, &entry); err != nil || entry.Time.IsZero() {
+		return false
+	}
+	if now.Sub(entry.Time) <= h.config.MaxEntryAge {
+		return false
+	}
+	atomic.AddInt64(&h.expiredMessages, 1)
+	return true
+}
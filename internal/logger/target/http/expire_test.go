@@ -0,0 +1,68 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTargetMaxEntryAge(t *testing.T) {
+	var delivered int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength > 2 {
+			// Not the probe sent by Init.
+			atomic.AddInt32(&delivered, 1)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	tgt := New(Config{
+		Endpoint:    srv.URL,
+		QueueSize:   10,
+		Transport:   http.DefaultTransport,
+		MaxEntryAge: time.Minute,
+		LogOnce:     func(_ context.Context, err error, _ interface{}, _ ...interface{}) { t.Error(err) },
+	})
+	if err := tgt.Init(); err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range []interface{}{
+		map[string]time.Time{"time": time.Now().Add(-time.Hour)},
+		map[string]time.Time{"time": time.Now()},
+		map[string]string{"time": "not a time"},
+		map[string]string{"message": "no time"},
+	} {
+		if err := tgt.Send(entry, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tgt.Cancel()
+
+	if got := atomic.LoadInt32(&delivered); got != 3 {
+		t.Fatalf("expected 3 entries delivered, got %d", got)
+	}
+	if stats := tgt.Stats(); stats.ExpiredMessages != 1 || stats.TotalMessages != 3 {
+		t.Fatalf("unexpected stats %#v", stats)
+	}
+}
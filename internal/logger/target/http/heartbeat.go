@@ -0,0 +1,94 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"time"
+)
+
+// HeartbeatHeader is set on the requests of heartbeats,
+// letting receivers discard them, see Config.Heartbeat.
+const HeartbeatHeader = "X-Minio-Heartbeat"
+
+// HeartbeatEntry is the entry sent as a heartbeat, its
+// minioHeartbeat field tells it apart from actual entries.
+type HeartbeatEntry struct {
+	Heartbeat bool      `json:"minioHeartbeat"`
+	Time      time.Time `json:"time"`
+}
+
+// startHeartbeat sends a heartbeat whenever no request
+// was sent to the endpoint for the heartbeat interval.
+func (h *Target) startHeartbeat() {
+	interval := h.config.Heartbeat
+	h.heartbeatDone = make(chan struct{})
+	ticker := h.clock.NewTicker(interval)
+	h.heartbeatWg.Add(1)
+	go func() {
+		defer h.heartbeatWg.Done()
+		defer ticker.Stop()
+		for {
+			select {
+			case now := <-ticker.C():
+				last := time.Unix(0, atomic.LoadInt64(&h.lastRequest))
+				if now.Sub(last) >= interval && atomic.LoadInt32(&h.disabled) == 0 {
+					h.sendHeartbeat(now)
+				}
+			case <-h.heartbeatDone:
+				return
+			}
+		}
+	}()
+}
+
+// sendHeartbeat sends a HeartbeatEntry, through the PayloadTemplate
+// if any. It is not counted in the target statistics and its
+// failures are left to be reported by the next entries.
+func (h *Target) sendHeartbeat(now time.Time) {
+	payload, err := json.Marshal(HeartbeatEntry{Heartbeat: true, Time: now.UTC()})
+	if err != nil {
+		return
+	}
+	if payload, err = h.render(payload); err != nil {
+		return
+	}
+	endpoint := h.config.Endpoint
+	if h.templated() {
+		endpoint = h.config.DefaultEndpoint
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), webhookCallTimeout)
+	defer cancel()
+	h.do(withHeartbeat(ctx), endpoint, bytes.NewReader(payload), "application/json", "", false)
+}
+
+type heartbeatKey struct{}
+
+// withHeartbeat marks the request sent with ctx as a heartbeat.
+func withHeartbeat(ctx context.Context) context.Context {
+	return context.WithValue(ctx, heartbeatKey{}, true)
+}
+
+// isHeartbeat returns true if the request sent with ctx is a heartbeat.
+func isHeartbeat(ctx context.Context) bool {
+	heartbeat, _ := ctx.Value(heartbeatKey{}).(bool)
+	return heartbeat
+}
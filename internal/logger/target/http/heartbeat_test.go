@@ -0,0 +1,107 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTargetHeartbeat(t *testing.T) {
+	var (
+		mu                  sync.Mutex
+		heartbeats, entries int
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var entry HeartbeatEntry
+		json.NewDecoder(r.Body).Decode(&entry)
+		mu.Lock()
+		switch {
+		case r.Header.Get(HeartbeatHeader) == "true":
+			if !entry.Heartbeat {
+				t.Error("expected the heartbeat entry to be marked")
+			}
+			heartbeats++
+		case r.ContentLength != 2: // Skip the probe sent by Init.
+			entries++
+		}
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	received := func() (int, int) {
+		mu.Lock()
+		defer mu.Unlock()
+		return heartbeats, entries
+	}
+	waitFor := func(wantHeartbeats, wantEntries int) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			heartbeats, entries := received()
+			if heartbeats >= wantHeartbeats && entries >= wantEntries {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for %d heartbeats and %d entries, got %d and %d",
+					wantHeartbeats, wantEntries, heartbeats, entries)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	clock := newFakeClock()
+	tgt := New(Config{
+		Endpoint:  srv.URL,
+		QueueSize: 10,
+		Transport: http.DefaultTransport,
+		Heartbeat: time.Minute,
+		LogOnce:   func(_ context.Context, err error, _ interface{}, _ ...interface{}) { t.Error(err) },
+	})
+	tgt.clock = clock
+	if err := tgt.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	clock.Advance(time.Minute)
+	waitFor(1, 0)
+
+	// An entry sent midway through the interval defers the next heartbeat.
+	clock.Advance(30 * time.Second)
+	if err := tgt.Send(map[string]int{"e": 1}, ""); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(1, 1)
+	clock.Advance(30 * time.Second)
+	clock.Advance(time.Minute)
+	waitFor(2, 1)
+	tgt.Cancel()
+
+	if heartbeats, entries := received(); heartbeats != 2 || entries != 1 {
+		t.Fatalf("expected 2 heartbeats and 1 entry, got %d and %d", heartbeats, entries)
+	}
+	if stats := tgt.Stats(); stats.TotalMessages != 1 {
+		t.Fatalf("expected heartbeats not to be counted, got %d messages", stats.TotalMessages)
+	}
+}
@@ -20,22 +20,48 @@ package http
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"text/template"
 	"time"
 
 	xhttp "github.com/minio/minio/internal/http"
+	"github.com/minio/minio/internal/logger/message/cef"
+	"github.com/minio/minio/internal/logger/target/filter"
+	"github.com/minio/minio/internal/logger/target/queued"
+	"github.com/minio/minio/internal/logger/target/throttle"
 	"github.com/minio/minio/internal/logger/target/types"
 )
 
 // Timeout for the webhook http call
 const webhookCallTimeout = 5 * time.Second
 
+// Maximum length of the response body reported
+// in the error of a rejected entry.
+const maxErrorBodyExcerpt = 256
+
+// Default queue high water mark in percentage of QueueSize
+const defaultQueueHighWater = 90
+
+// Default number of TLS sessions cached for resumption
+const defaultTLSSessionCacheSize = 64
+
+// tenantToken is replaced in a templated endpoint
+// by the tenant resolved from each entry.
+const tenantToken = "{tenant}"
+
+// Maximum number of per tenant clients kept around
+const maxTenantClients = 100
+
 // Config http logger target
 type Config struct {
 	Enabled    bool              `json:"enabled"`
@@ -48,22 +74,373 @@ type Config struct {
 	QueueSize  int               `json:"queueSize"`
 	Transport  http.RoundTripper `json:"-"`
 
+	// HTTPClient when set is used as is to deliver entries
+	// instead of building one from Transport, both must not
+	// be set at the same time, nor the options applied to the
+	// transport such as Proxy.
+	HTTPClient *http.Client `json:"-"`
+
+	// DedupWindow when set, collapses identical entries seen
+	// within the window into a single entry with a repeat_count.
+	DedupWindow time.Duration `json:"dedupWindow"`
+	// DedupCacheSize bounds the number of distinct entries
+	// held while deduplicating, defaults to 1000.
+	DedupCacheSize int `json:"dedupCacheSize"`
+
+	// OnQueueFull when set, is called with the current queue
+	// usage once the queue crosses QueueHighWater percent of
+	// QueueSize and again once it has recovered below it.
+	OnQueueFull func(target string, used, capacity int) `json:"-"`
+
+	// OnDelivered when set, is called exactly once for each entry
+	// taken off the queue with the outcome of its delivery, nil
+	// once delivered or the error it ultimately failed with, e.g.
+	// ErrEntryExpired. The entry is the one queued, or its JSON
+	// encoding as a json.RawMessage if it was encoded by Send. It
+	// is called by the goroutine delivering the entries, which it
+	// blocks, and must hence return quickly.
+	OnDelivered func(entry interface{}, err error) `json:"-"`
+	// QueueHighWater percentage of QueueSize, defaults to 90.
+	QueueHighWater int `json:"queueHighWater"`
+
+	// TLSSessionCacheSize is the number of TLS sessions cached
+	// for resumption with the endpoint, defaults to 64 and a
+	// negative value disables session resumption.
+	TLSSessionCacheSize int `json:"tlsSessionCacheSize"`
+
+	// DisableRedirects when set, stops the client from following
+	// 3xx redirects, they are reported as errors along with their
+	// Location instead. Ignored with a custom HTTPClient.
+	DisableRedirects bool `json:"disableRedirects"`
+
+	// Endpoint may contain a {tenant} token replaced by the
+	// value of the TenantField of each entry, a dot separated
+	// path in its JSON encoding. With a TenantSeparator only
+	// the value up to the separator is used as the tenant.
+	// Entries without a tenant are sent to DefaultEndpoint.
+	TenantField     string `json:"tenantField"`
+	TenantSeparator string `json:"tenantSeparator"`
+	DefaultEndpoint string `json:"defaultEndpoint"`
+
+	// PathSuffix when set, is appended to the path of the endpoint
+	// entries are sent to, e.g. a base endpoint of
+	// https://proxy/prefix/ and a suffix of /minio/audit send them to
+	// https://proxy/prefix/minio/audit, so that only the base differs
+	// across environments.
+	PathSuffix string `json:"pathSuffix"`
+
+	// PartialFailureField when set, is the dot separated path of the
+	// array listing the indices of the entries of a batch rejected by
+	// the endpoint in its response, accepted with a 2xx status or 207
+	// Multi-Status. With a PartialFailureIndexKey the array holds
+	// objects with the index at that key instead of the indices. Only
+	// the rejected entries are sent again, with the next batch, up to
+	// 3 times, the others are counted as delivered. A streamed batch,
+	// see BatchStream, fails as a whole.
+	PartialFailureField    string `json:"partialFailureField"`
+	PartialFailureIndexKey string `json:"partialFailureIndexKey"`
+
+	// Compress when set, sends gzip compressed entries as long
+	// as the endpoint accepts them, either advertised with an
+	// Accept-Encoding response header or by not rejecting them
+	// with 415 Unsupported Media Type.
+	Compress bool `json:"compress"`
+
+	// CompressionLevel of the gzip compressed entries, from
+	// gzip.BestSpeed to gzip.BestCompression, or gzip.HuffmanOnly.
+	// 0 stands for gzip.DefaultCompression.
+	CompressionLevel int `json:"compressionLevel"`
+
+	// DNSCacheTTL when set, caches the addresses the endpoint
+	// hosts resolve to for the given duration, they are used
+	// in turn and resolved again after a failed dial.
+	DNSCacheTTL time.Duration `json:"dnsCacheTTL"`
+
+	// QueueWhileDisabled when set, keeps queuing entries while
+	// the target is disabled with SetEnabled, they are dropped
+	// otherwise.
+	QueueWhileDisabled bool `json:"queueWhileDisabled"`
+
+	// BatchMaxBytes and BatchInterval when either is set, send
+	// entries in batches of newline delimited JSON, flushed once
+	// they reach BatchMaxBytes or are BatchInterval old, whichever
+	// comes first. They default to 1MiB and 2s respectively. An
+	// entry larger than BatchMaxBytes is sent alone. Entries of
+	// templated endpoints are never batched.
+	BatchMaxBytes int           `json:"batchMaxBytes"`
+	BatchInterval time.Duration `json:"batchInterval"`
+	// BatchStream when set, streams each batch to the endpoint
+	// as its entries are queued instead of buffering it, memory
+	// usage then does not depend on BatchMaxBytes. A batch failing
+	// midway fails as a whole and one rejected for compression is
+	// not sent again uncompressed.
+	BatchStream bool `json:"batchStream"`
+
+	// Proxy when set, is the URL of the proxy entries are sent
+	// through, either an HTTP(S) proxy or a SOCKS5 proxy with
+	// the socks5:// scheme, credentials in the URL are honored.
+	Proxy string `json:"proxy"`
+	// NoProxy is a comma separated list of hosts reached without
+	// the Proxy, as in NO_PROXY: host names also matching their
+	// subdomains, IP addresses, CIDRs or `*` for all hosts.
+	NoProxy string `json:"noProxy"`
+
+	// StampReceivedAt when set, adds a receivedAt field with the
+	// UTC time the entry was handed to the target, per the server
+	// clock, to entries without one. Entries held in the queue or
+	// by the dedup cache keep the time they were received at.
+	StampReceivedAt bool `json:"stampReceivedAt"`
+
+	// DisableKeepAlive when set, closes the connection after
+	// each request instead of reusing it, for endpoints behind
+	// load balancers dropping idle connections. Every request
+	// then pays for a new connection, and TLS handshake, which
+	// lowers the throughput. Connections the endpoint asks to
+	// close with a `Connection: close` response are never reused.
+	DisableKeepAlive bool `json:"disableKeepAlive"`
+
+	// ConnMaxLifetime when set, closes the connections reused past
+	// it before sending the next request, which is then sent on a new
+	// connection, for endpoints behind firewalls dropping long lived
+	// connections. Connections are still reused within their lifetime.
+	// A streamed batch, see BatchStream, fails instead of being sent
+	// again.
+	ConnMaxLifetime time.Duration `json:"connMaxLifetime"`
+
+	// MaxEntryAge when set, drops the entries older than it by the
+	// time they are about to be sent instead of delivering them late,
+	// going by their time field. They are counted as expired.
+	MaxEntryAge time.Duration `json:"maxEntryAge"`
+
+	// Heartbeat when set, sends a HeartbeatEntry to the endpoint
+	// once no request was sent to it for the given duration,
+	// keeping the connection and authentication warm. Heartbeats
+	// carry the HeartbeatHeader for receivers to discard them.
+	Heartbeat time.Duration `json:"heartbeat"`
+
+	// Registration when set, sends a RegistrationEntry carrying the
+	// RegistrationFields, e.g. the name of the site, ahead of the
+	// first delivery once the target comes online: after Init and
+	// after each failed delivery. It tells receivers a new session
+	// of the producer started. It is not counted in the statistics.
+	Registration       bool              `json:"registration"`
+	RegistrationFields map[string]string `json:"registrationFields"`
+
+	// DisableProbe when set, skips sending an empty entry to the
+	// endpoint on Init, for receivers alerting on malformed payloads.
+	// The target is then online unless its last delivery failed,
+	// optimistically so until the first one.
+	DisableProbe bool `json:"disableProbe"`
+
+	// QueueMaxSize when larger than QueueSize, sizes the queue
+	// adaptively between both: it grows while delivering entries
+	// takes longer than QueueGrowLatency on average, 1s by default,
+	// and shrinks back once the endpoint is healthy again. Streamed
+	// batches, see BatchStream, do not resize the queue.
+	QueueMaxSize     int           `json:"queueMaxSize"`
+	QueueGrowLatency time.Duration `json:"queueGrowLatency"`
+
+	// BackpressureHeader when set, is the response header with which
+	// the endpoint signals it is near capacity, set to BackpressureHigh.
+	// Deliveries are then spaced out by a delay doubling on each such
+	// response, up to BackpressureMaxDelay, 5s by default, and halving
+	// on each response without it until back to the full rate.
+	BackpressureHeader   string        `json:"backpressureHeader"`
+	BackpressureMaxDelay time.Duration `json:"backpressureMaxDelay"`
+
+	// DisableHTMLEscape when set, leaves the <, > and & characters
+	// of entries as is instead of escaping them as \u003c, \u003e
+	// and \u0026, for receivers not unescaping them.
+	DisableHTMLEscape bool `json:"disableHTMLEscape"`
+
+	// PayloadTemplate when set, is a text/template transforming
+	// each entry into the payload expected by the endpoint, e.g.
+	//   {"event": {{json .Entry}}, "sourcetype": "minio"}
+	// It is executed with the decoded .Entry, the .DeploymentID
+	// and .Version of the server, and the json function encoding
+	// any value. The output must be valid JSON.
+	PayloadTemplate string `json:"payloadTemplate"`
+
+	// PinnedServerCertSHA256 when set, only accepts the endpoints presenting
+	// a certificate, leaf or intermediate, whose SHA-256 fingerprint is one
+	// of these, see ParseCertPins, on top of the usual verification. Several
+	// pins allow rotating the certificate. Deliveries to other endpoints fail
+	// with ErrCertPinMismatch. It requires an *http.Transport, Init fails
+	// with a custom HTTPClient.
+	PinnedServerCertSHA256 []string `json:"pinnedServerCertSHA256"`
+
+	// APIFilter when set, only sends the entries whose API name matches
+	// one of these patterns, e.g. "PutObject" or "*Object", with the
+	// syntax of path.Match. Entries without an API name are sent unless
+	// DropMissingAPI is set.
+	APIFilter      []string `json:"apiFilter"`
+	DropMissingAPI bool     `json:"dropMissingAPI"`
+
+	// PriorityFilter when set, is an expression selecting the high
+	// priority entries, e.g. policy changes, see Filter. They are
+	// queued in a separate lane of PriorityQueueSize entries,
+	// QueueSize by default, drained before the other entries, and
+	// are neither deduplicated, batched nor rate limited.
+	PriorityFilter    string `json:"priorityFilter"`
+	PriorityQueueSize int    `json:"priorityQueueSize"`
+
+	// Format of the payloads, FormatJSON by default. With FormatCEF
+	// entries are sent as ArcSight Common Event Format events, their
+	// fields mapped to extensions with CEFFields, a comma separated
+	// list of key=path mappings, cef.DefaultFields if empty.
+	Format    string `json:"format"`
+	CEFFields string `json:"cefFields"`
+
+	// OrderingMode is OrderingUnordered by default. With OrderingOrdered
+	// entries are delivered strictly in the order they were queued, at
+	// the cost of the options reordering them: PriorityFilter,
+	// DedupWindow and PartialFailureField cannot be set.
+	OrderingMode string `json:"orderingMode"`
+
+	// Filter when set, is an expression selecting the entries sent,
+	// the others are dropped, see the filter package for its syntax.
+	Filter string `json:"filter"`
+
+	// AuthTokenField when set, is the dot separated path of the
+	// payload field the AuthToken is sent in, for receivers which
+	// authenticate entries by their body. The token is then only
+	// sent in the Authorization header as well with AuthTokenHeader.
+	// Payloads must be JSON objects, see PayloadTemplate.
+	AuthTokenField  string `json:"authTokenField"`
+	AuthTokenHeader bool   `json:"authTokenHeader"`
+
+	// AllowInsecureAuth when set, sends the AuthToken to plaintext
+	// http endpoints, which is otherwise refused as it leaks the
+	// token on the wire. Meant for development and testing only.
+	AllowInsecureAuth bool `json:"allowInsecureAuth"`
+
+	// RequestIDHeader when set, is the header the request ID of
+	// the entry sent is forwarded in, e.g. X-Request-ID, letting
+	// the endpoint tie the request to the logged event. Batches
+	// are sent with an ID generated for the batch instead.
+	RequestIDHeader string `json:"requestIDHeader"`
+
 	// Custom logger
 	LogOnce func(ctx context.Context, err error, id interface{}, errKind ...interface{}) `json:"-"`
 }
 
+// redacted is the placeholder of redacted secrets.
+const redacted = "*REDACTED*"
+
+// Redacted returns a copy of the config with its secrets redacted.
+func (c Config) Redacted() Config {
+	if c.AuthToken != "" {
+		c.AuthToken = redacted
+	}
+	if u, err := url.Parse(c.Endpoint); err == nil {
+		c.Endpoint = u.Redacted()
+	}
+	if u, err := url.Parse(c.DefaultEndpoint); err == nil {
+		c.DefaultEndpoint = u.Redacted()
+	}
+	if u, err := url.Parse(c.Proxy); err == nil {
+		c.Proxy = u.Redacted()
+	}
+	return c
+}
+
 // Target implements logger.Target and sends the json
 // format of a log entry to the configured http endpoint.
 // An internal buffer of logs is maintained but when the
 // buffer is full, new logs are just ignored and an error
 // is returned to the caller.
 type Target struct {
-	status int32
-	wg     sync.WaitGroup
+	// Accessed atomically, must stay 64-bit aligned.
+	totalMessages     int64
+	failedMessages    int64
+	expiredMessages   int64
+	compressDecidedAt int64
+	lastRequest       int64 // UnixNano of the last request sent
+	queueLimit        int64 // Entries an adaptive queue holds at most
+	backpressureDelay int64 // Delay between deliveries, see updateBackpressure
+
+	// Whether the endpoint accepts compressed entries
+	compressState int32
 
-	// Channel of log entries
+	// Writers of compressed entries, see gzipWriter.
+	gzipPool sync.Pool
+
+	// Whether a RegistrationEntry is to be sent.
+	unregistered int32
+
+	status  int32
+	wg      sync.WaitGroup
+	dedupWg sync.WaitGroup
+
+	// Closed to stop the heartbeats on Cancel.
+	heartbeatDone chan struct{}
+	heartbeatWg   sync.WaitGroup
+
+	// Channel of log entries, of QueueMaxSize entries
+	// with an adaptive queue limited to queueLimit.
 	logCh chan interface{}
 
+	// Channel of the high priority entries, nil without PriorityFilter.
+	priorityCh chan interface{}
+
+	// Average delivery latency of an adaptive queue, only
+	// accessed by the goroutine delivering the entries.
+	avgLatency time.Duration
+
+	// HTTP client used to deliver entries
+	client *http.Client
+
+	// Identical entries seen within the dedup window,
+	// nil when deduplication is disabled.
+	dedup     *dedupCache
+	dedupDone chan struct{}
+
+	// Queue usage thresholds for OnQueueFull and
+	// whether the queue is currently above them.
+	highWater int
+	lowWater  int
+	queueFull int32
+
+	// OnQueueFull is called in order by a single routine
+	// with the usages sent, under queueFullMu, on queueFullCh.
+	queueFullMu   sync.Mutex
+	queueFullCh   chan queueUsage
+	queueFullWg   sync.WaitGroup
+	queueFullOnce sync.Once
+
+	// Whether delivery is paused with SetEnabled, enabledCh
+	// is closed while the target is enabled.
+	disabled  int32
+	enabledMu sync.Mutex
+	enabledCh chan struct{}
+
+	// Clients of the endpoints resolved per tenant
+	tenantMu      sync.Mutex
+	tenantClients map[string]*http.Client
+
+	// Parsed PayloadTemplate, nil without one.
+	payloadTmpl *template.Template
+
+	// Parsed Filter, nil without one.
+	filter *filter.Expr
+
+	// Parsed PriorityFilter, nil without one.
+	priority *filter.Expr
+
+	// Formatter of CEF payloads, nil unless Format is FormatCEF.
+	cef *cef.Formatter
+
+	// Source of time, replaced in tests
+	clock clock
+
+	// Outcome of the last deliveries
+	statsMu      sync.Mutex
+	started      time.Time
+	lastSuccess  time.Time
+	lastError    time.Time
+	lastErrorMsg string
+	offlineErr   error // why the target is not online, nil once it is.
+
 	config Config
 }
 
@@ -76,28 +453,231 @@ func (h *Target) String() string {
 	return h.config.Name
 }
 
+// transport returns the configured transport with a TLS
+// client session cache set up to resume TLS sessions and
+// a DNS cache, proxy and connection lifetime if enabled.
+func (h *Target) transport() (http.RoundTripper, error) {
+	tr, ok := h.config.Transport.(*http.Transport)
+	if !ok {
+		if h.config.Proxy != "" {
+			return nil, errors.New("a proxy can only be configured with an *http.Transport")
+		}
+		if len(h.config.PinnedServerCertSHA256) > 0 {
+			return nil, errors.New("pinned certificates can only be configured with an *http.Transport")
+		}
+		return h.config.Transport, nil
+	}
+	if h.config.TLSSessionCacheSize < 0 && h.config.DNSCacheTTL <= 0 && h.config.Proxy == "" && h.config.ConnMaxLifetime <= 0 &&
+		len(h.config.PinnedServerCertSHA256) == 0 {
+		return h.config.Transport, nil
+	}
+	tr = tr.Clone()
+	if h.config.DNSCacheTTL > 0 {
+		dial := tr.DialContext
+		if dial == nil {
+			dial = defaultDialContext
+		}
+		tr.DialContext = newDNSCache(h.config.DNSCacheTTL).dialContext(dial)
+	}
+	if h.config.Proxy != "" {
+		if err := setProxy(tr, h.config.Proxy, h.config.NoProxy); err != nil {
+			return nil, err
+		}
+	}
+	if h.config.ConnMaxLifetime > 0 {
+		dial := tr.DialContext
+		if dial == nil {
+			dial = defaultDialContext
+		}
+		tr.DialContext = h.maxLifetimeDialContext(dial, h.config.ConnMaxLifetime)
+	}
+	if len(h.config.PinnedServerCertSHA256) > 0 {
+		pins, err := ParseCertPins(h.config.PinnedServerCertSHA256)
+		if err != nil {
+			return nil, err
+		}
+		if tr.TLSClientConfig == nil {
+			tr.TLSClientConfig = &tls.Config{}
+		}
+		pinCertificates(tr.TLSClientConfig, pins)
+	}
+	if h.config.TLSSessionCacheSize < 0 {
+		return tr, nil
+	}
+	if tr.TLSClientConfig == nil {
+		tr.TLSClientConfig = &tls.Config{}
+	}
+	if tr.TLSClientConfig.ClientSessionCache == nil {
+		size := h.config.TLSSessionCacheSize
+		if size == 0 {
+			size = defaultTLSSessionCacheSize
+		}
+		tr.TLSClientConfig.ClientSessionCache = tls.NewLRUClientSessionCache(size)
+	}
+	return tr, nil
+}
+
+// transportOption returns the name of the first option set which
+// is applied to the transport, unused with a custom HTTP client.
+func (h *Target) transportOption() string {
+	switch {
+	case len(h.config.PinnedServerCertSHA256) > 0:
+		return "pinned certificates"
+	case h.config.Proxy != "":
+		return "a proxy"
+	case h.config.NoProxy != "":
+		return "proxy exclusions"
+	case h.config.DNSCacheTTL > 0:
+		return "a DNS cache"
+	case h.config.ConnMaxLifetime > 0:
+		return "a connection lifetime"
+	case h.config.TLSSessionCacheSize > 0:
+		return "a TLS session cache size"
+	}
+	return ""
+}
+
+// SetHTTPClient sets a custom HTTP client used to deliver
+// entries, it must be called before Init, along with none
+// of the options applied to the transport.
+func (h *Target) SetHTTPClient(client *http.Client) {
+	h.client = client
+}
+
 // Init validate and initialize the http target
 func (h *Target) Init() error {
+	err := h.init()
+	h.statsMu.Lock()
+	h.offlineErr = err
+	h.statsMu.Unlock()
+	return err
+}
+
+func (h *Target) init() error {
+	if h.client == nil {
+		tr, err := h.transport()
+		if err != nil {
+			return err
+		}
+		h.client = &http.Client{Transport: tr}
+		if h.config.DisableRedirects {
+			h.client.CheckRedirect = func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			}
+		} else if h.config.AuthToken != "" {
+			h.client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+				// Do not follow a redirect downgrading to plaintext
+				// http, the Authorization header is kept on redirects
+				// to the same host.
+				if h.insecureAuth(req.URL.String()) {
+					return ErrInsecureAuth
+				}
+				if len(via) >= 10 {
+					return errors.New("stopped after 10 redirects")
+				}
+				return nil
+			}
+		}
+	} else if h.config.Transport != nil {
+		return errors.New("a custom http client and transport cannot be configured together")
+	} else if option := h.transportOption(); option != "" {
+		return fmt.Errorf("%s cannot be configured with a custom http client", option)
+	}
+
+	tmpl, err := parsePayloadTemplate(h.config.PayloadTemplate, !h.config.DisableHTMLEscape)
+	if err != nil {
+		return err
+	}
+	h.payloadTmpl = tmpl
+	if err = h.initFormat(); err != nil {
+		return err
+	}
+	if err = validateCompressionLevel(h.config.CompressionLevel); err != nil {
+		return err
+	}
+	if h.insecureAuth(h.config.Endpoint) || h.insecureAuth(h.config.DefaultEndpoint) {
+		return ErrInsecureAuth
+	}
+	if err = h.initOrdering(); err != nil {
+		return err
+	}
+	if h.config.Filter != "" {
+		if h.filter, err = filter.Parse(h.config.Filter); err != nil {
+			return err
+		}
+	}
+	if h.config.PriorityFilter != "" {
+		if h.priority, err = filter.Parse(h.config.PriorityFilter); err != nil {
+			return err
+		}
+	}
+	if err = ValidateAPIFilter(h.config.APIFilter); err != nil {
+		return err
+	}
+
+	if h.templated() && h.config.DefaultEndpoint == "" {
+		return errors.New("a default endpoint is required with a templated endpoint")
+	}
+	if !h.config.DisableProbe {
+		if err = h.probe(); err != nil {
+			return err
+		}
+	}
+
+	h.statsMu.Lock()
+	h.started = h.clock.Now()
+	h.statsMu.Unlock()
+	if h.config.Registration {
+		h.unregistered = 1
+	}
+	h.status = 1
+	if h.highWater > 0 {
+		h.startQueueFullNotifier()
+	}
+	h.startHTTPLogger()
+	if h.dedup != nil {
+		h.startDedupFlusher()
+	}
+	if h.config.Heartbeat > 0 {
+		atomic.StoreInt64(&h.lastRequest, h.clock.Now().UnixNano())
+		h.startHeartbeat()
+	}
+	return nil
+}
+
+// probe checks the endpoint is reachable by sending it an empty entry.
+func (h *Target) probe() error {
+	endpoint := h.config.Endpoint
+	if h.templated() {
+		endpoint = h.config.DefaultEndpoint
+	}
+	endpoint = h.withPathSuffix(endpoint)
+
 	ctx, cancel := context.WithTimeout(context.Background(), 2*webhookCallTimeout)
 	defer cancel()
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.config.Endpoint, strings.NewReader(`{}`))
+	// An empty entry, in the envelope of the payload template if any.
+	probe, err := h.render([]byte(`{}`))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(probe))
 	if err != nil {
 		return err
 	}
 
-	req.Header.Set(xhttp.ContentType, "application/json")
+	req.Close = h.config.DisableKeepAlive
+	req.Header.Set(xhttp.ContentType, h.contentType(false))
 
 	// Set user-agent to indicate MinIO release
 	// version to the configured log endpoint
 	req.Header.Set("User-Agent", h.config.UserAgent)
 
-	if h.config.AuthToken != "" {
+	if h.authHeader() {
 		req.Header.Set("Authorization", h.config.AuthToken)
 	}
 
-	client := http.Client{Transport: h.config.Transport}
-	resp, err := client.Do(req)
+	resp, err := h.client.Do(req)
 	if err != nil {
 		return err
 	}
@@ -106,17 +686,21 @@ func (h *Target) Init() error {
 	xhttp.DrainBody(resp.Body)
 
 	if !acceptedResponseStatusCode(resp.StatusCode) {
-		switch resp.StatusCode {
-		case http.StatusForbidden:
+		switch {
+		case resp.StatusCode == http.StatusForbidden:
 			return fmt.Errorf("%s returned '%s', please check if your auth token is correctly set",
-				h.config.Endpoint, resp.Status)
+				endpoint, resp.Status)
+		case isRedirect(resp.StatusCode):
+			return fmt.Errorf("%s returned '%s' redirecting to '%s', please check your endpoint configuration",
+				endpoint, resp.Status, resp.Header.Get("Location"))
 		}
 		return fmt.Errorf("%s returned '%s', please check your endpoint configuration",
-			h.config.Endpoint, resp.Status)
+			endpoint, resp.Status)
 	}
 
-	h.status = 1
-	go h.startHTTPLogger()
+	if acceptsGzip(resp) {
+		h.setCompress(compressAccepted)
+	}
 	return nil
 }
 
@@ -127,21 +711,124 @@ func acceptedResponseStatusCode(code int) bool {
 	return acceptedStatusCodeMap[code]
 }
 
-func (h *Target) logEntry(entry interface{}) {
-	logJSON, err := json.Marshal(&entry)
+func isRedirect(code int) bool {
+	return code >= http.StatusMultipleChoices && code < http.StatusBadRequest
+}
+
+func (h *Target) logEntry(entry interface{}, high bool) {
+	logJSON, err := h.marshal(&entry)
 	if err != nil {
+		h.delivered(entry, err)
 		return
 	}
+	if h.expired(logJSON, h.clock.Now()) {
+		h.delivered(entry, ErrEntryExpired)
+		return
+	}
+
+	endpoint := h.resolveEndpoint(logJSON)
+	requestID := h.entryRequestID(logJSON)
+	if logJSON, err = h.render(logJSON); err != nil {
+		h.record(err, 1)
+		h.delivered(entry, err)
+		return
+	}
+	if !high {
+		throttle.Wait(context.Background(), 1)
+	}
+	h.register()
+	err = h.transmit(endpoint, logJSON, h.contentType(false), requestID, 1)
+	h.record(err, 1)
+	h.delivered(entry, err)
+}
+
+// delivered reports the outcome of the delivery of
+// an entry to OnDelivered, if any.
+func (h *Target) delivered(entry interface{}, err error) {
+	if h.config.OnDelivered != nil {
+		h.config.OnDelivered(entry, err)
+	}
+}
+
+// transmit sends a payload of count entries to endpoint.
+func (h *Target) transmit(endpoint string, payload []byte, contentType, requestID string, count int64) error {
+	h.waitBackpressure()
+	start := h.clock.Now()
+	err := h.send(endpoint, payload, contentType, requestID)
+	h.adaptQueue(h.clock.Now().Sub(start))
+	return err
+}
+
+// record records the outcome of sending count entries in
+// the target statistics.
+func (h *Target) record(err error, count int64) {
+	h.statsMu.Lock()
+	if err != nil {
+		atomic.AddInt64(&h.failedMessages, count)
+		h.lastError = h.clock.Now()
+		h.lastErrorMsg = err.Error()
+		if h.config.Registration {
+			atomic.StoreInt32(&h.unregistered, 1)
+		}
+	} else {
+		h.lastSuccess = h.clock.Now()
+	}
+	atomic.AddInt64(&h.totalMessages, count)
+	h.statsMu.Unlock()
+
+	if err != nil {
+		h.config.LogOnce(context.Background(), err, h.config.Endpoint)
+	}
+}
+
+// send delivers a payload to the endpoint, compressed if
+// enabled, falling back to uncompressed if it is rejected.
+func (h *Target) send(endpoint string, payload []byte, contentType, requestID string) error {
+	if h.shouldCompress() {
+		err := h.post(endpoint, payload, contentType, requestID, true)
+		if err != errCompressRejected {
+			if err == nil {
+				h.setCompress(compressAccepted)
+			}
+			return err
+		}
+		h.setCompress(compressRejected)
+	}
+	return h.post(endpoint, payload, contentType, requestID, false)
+}
+
+// post sends a payload to endpoint.
+func (h *Target) post(endpoint string, payload []byte, contentType, requestID string, compress bool) error {
+	body := payload
+	if compress {
+		var err error
+		if body, err = h.gzipCompress(payload); err != nil {
+			return err
+		}
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), webhookCallTimeout)
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
-		h.config.Endpoint, bytes.NewReader(logJSON))
+	defer cancel()
+
+	return h.do(ctx, endpoint, bytes.NewReader(body), contentType, requestID, compress)
+}
+
+// do sends the body, already compressed if compress, to endpoint.
+func (h *Target) do(ctx context.Context, endpoint string, body io.Reader, contentType, requestID string, compress bool) error {
+	if h.insecureAuth(endpoint) {
+		return ErrInsecureAuth
+	}
+	client := h.clientFor(endpoint)
+	endpoint = h.withPathSuffix(endpoint)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, body)
 	if err != nil {
-		h.config.LogOnce(ctx, fmt.Errorf("%s returned '%w', please check your endpoint configuration", h.config.Endpoint, err), h.config.Endpoint)
-		cancel()
-		return
+		return fmt.Errorf("%s returned '%w', please check your endpoint configuration", endpoint, err)
+	}
+	req.Close = h.config.DisableKeepAlive
+	req.Header.Set(xhttp.ContentType, contentType)
+	if compress {
+		req.Header.Set(xhttp.ContentEncoding, "gzip")
 	}
-	req.Header.Set(xhttp.ContentType, "application/json")
 	req.Header.Set(xhttp.MinIOVersion, xhttp.GlobalMinIOVersion)
 	req.Header.Set(xhttp.MinioDeploymentID, xhttp.GlobalDeploymentID)
 
@@ -149,39 +836,193 @@ func (h *Target) logEntry(entry interface{}) {
 	// version to the configured log endpoint
 	req.Header.Set("User-Agent", h.config.UserAgent)
 
-	if h.config.AuthToken != "" {
+	if h.authHeader() {
 		req.Header.Set("Authorization", h.config.AuthToken)
 	}
+	if requestID != "" {
+		req.Header.Set(h.config.RequestIDHeader, requestID)
+	}
+	if isHeartbeat(ctx) {
+		req.Header.Set(HeartbeatHeader, "true")
+	}
+
+	atomic.StoreInt64(&h.lastRequest, h.clock.Now().UnixNano())
 
-	client := http.Client{Transport: h.config.Transport}
 	resp, err := client.Do(req)
-	cancel()
 	if err != nil {
-		h.config.LogOnce(ctx, fmt.Errorf("%s returned '%w', please check your endpoint configuration", h.config.Endpoint, err), h.config.Endpoint)
-		return
+		return fmt.Errorf("%s returned '%w', please check your endpoint configuration", endpoint, err)
 	}
+	h.updateBackpressure(resp.Header)
 
-	// Drain any response.
+	if h.partialFailures(resp.StatusCode) {
+		defer xhttp.DrainBody(resp.Body)
+		return h.readPartialFailure(endpoint, resp.Body)
+	}
+
+	// Keep the start of a rejection message and drain any response.
+	var excerpt []byte
+	if !acceptedResponseStatusCode(resp.StatusCode) {
+		excerpt, _ = ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBodyExcerpt))
+		excerpt = h.redactAuthToken(excerpt)
+	}
 	xhttp.DrainBody(resp.Body)
 
 	if !acceptedResponseStatusCode(resp.StatusCode) {
-		switch resp.StatusCode {
-		case http.StatusForbidden:
-			h.config.LogOnce(ctx, fmt.Errorf("%s returned '%s', please check if your auth token is correctly set", h.config.Endpoint, resp.Status), h.config.Endpoint)
+		switch {
+		case compress && resp.StatusCode == http.StatusUnsupportedMediaType:
+			return errCompressRejected
+		case resp.StatusCode == http.StatusForbidden:
+			return fmt.Errorf("%s returned '%s', please check if your auth token is correctly set", endpoint, resp.Status)
+		case isRedirect(resp.StatusCode):
+			return fmt.Errorf("%s returned '%s' redirecting to '%s', please check your endpoint configuration", endpoint, resp.Status, resp.Header.Get("Location"))
+		case len(bytes.TrimSpace(excerpt)) > 0:
+			return fmt.Errorf("%s returned '%s' with '%s', please check your endpoint configuration", endpoint, resp.Status, bytes.TrimSpace(excerpt))
 		default:
-			h.config.LogOnce(ctx, fmt.Errorf("%s returned '%s', please check your endpoint configuration", h.config.Endpoint, resp.Status), h.config.Endpoint)
+			return fmt.Errorf("%s returned '%s', please check your endpoint configuration", endpoint, resp.Status)
 		}
 	}
+	return nil
+}
+
+// withPathSuffix returns endpoint with the PathSuffix appended to its
+// path, separated by a single slash.
+func (h *Target) withPathSuffix(endpoint string) string {
+	if h.config.PathSuffix == "" {
+		return endpoint
+	}
+	base, query := endpoint, ""
+	if i := strings.IndexByte(endpoint, '?'); i >= 0 {
+		base, query = endpoint[:i], endpoint[i:]
+	}
+	return strings.TrimRight(base, "/") + "/" + strings.TrimLeft(h.config.PathSuffix, "/") + query
+}
+
+// templated returns true if the endpoint is resolved per tenant.
+func (h *Target) templated() bool {
+	return strings.Contains(h.config.Endpoint, tenantToken)
+}
+
+// resolveEndpoint returns the endpoint a json encoded entry is sent to.
+func (h *Target) resolveEndpoint(logJSON []byte) string {
+	if !h.templated() {
+		return h.config.Endpoint
+	}
+	tenant := h.tenant(logJSON)
+	if tenant == "" {
+		return h.config.DefaultEndpoint
+	}
+	return strings.ReplaceAll(h.config.Endpoint, tenantToken, url.PathEscape(tenant))
+}
+
+// tenant returns the tenant of a json encoded entry, if any.
+func (h *Target) tenant(logJSON []byte) string {
+	if h.config.TenantField == "" {
+		return ""
+	}
+	var v interface{}
+	if err := json.Unmarshal(logJSON, &v); err != nil {
+		return ""
+	}
+	for _, key := range strings.Split(h.config.TenantField, ".") {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		v = m[key]
+	}
+	tenant, _ := v.(string)
+	if h.config.TenantSeparator != "" {
+		i := strings.Index(tenant, h.config.TenantSeparator)
+		if i < 0 {
+			return ""
+		}
+		tenant = tenant[:i]
+	}
+	return tenant
+}
+
+// clientFor returns the client used to deliver to endpoint, tenant
+// endpoints get their own client so that they don't share idle
+// connections with each other.
+func (h *Target) clientFor(endpoint string) *http.Client {
+	if !h.templated() || endpoint == h.config.DefaultEndpoint {
+		return h.client
+	}
+	tr, ok := h.client.Transport.(*http.Transport)
+	if !ok {
+		return h.client
+	}
+
+	h.tenantMu.Lock()
+	defer h.tenantMu.Unlock()
+	if client, ok := h.tenantClients[endpoint]; ok {
+		return client
+	}
+	if h.tenantClients == nil {
+		h.tenantClients = make(map[string]*http.Client)
+	}
+	if len(h.tenantClients) >= maxTenantClients {
+		// Evict any client to make room.
+		for k, client := range h.tenantClients {
+			client.CloseIdleConnections()
+			delete(h.tenantClients, k)
+			break
+		}
+	}
+	client := &http.Client{
+		Transport:     tr.Clone(),
+		CheckRedirect: h.client.CheckRedirect,
+		Timeout:       h.client.Timeout,
+	}
+	h.tenantClients[endpoint] = client
+	return client
 }
 
 func (h *Target) startHTTPLogger() {
 	// Create a routine which sends json logs received
 	// from an internal channel.
+	h.wg.Add(1)
 	go func() {
-		h.wg.Add(1)
 		defer h.wg.Done()
-		for entry := range h.logCh {
-			h.logEntry(entry)
+		if h.batching() {
+			h.batchEntries()
+			return
+		}
+		lane := h.priorityCh
+		for {
+			entry, high, ok, _ := h.receive(&lane, nil)
+			if !ok {
+				return
+			}
+			h.waitEnabled()
+			h.checkQueueRecovered()
+			h.logEntry(entry, high)
+		}
+	}()
+}
+
+// startDedupFlusher forwards deduplicated entries
+// once their window has elapsed.
+func (h *Target) startDedupFlusher() {
+	interval := h.dedup.window / 2
+	if interval <= 0 {
+		interval = h.dedup.window
+	}
+	h.dedupDone = make(chan struct{})
+	h.dedupWg.Add(1)
+	go func() {
+		defer h.dedupWg.Done()
+		ticker := h.clock.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case now := <-ticker.C():
+				for _, entry := range h.dedup.expired(now) {
+					h.enqueue(context.Background(), entry)
+				}
+			case <-h.dedupDone:
+				return
+			}
 		}
 	}()
 }
@@ -190,8 +1031,36 @@ func (h *Target) startHTTPLogger() {
 // sends log over http to the specified endpoint
 func New(config Config) *Target {
 	h := &Target{
-		logCh:  make(chan interface{}, config.QueueSize),
-		config: config,
+		logCh:     make(chan interface{}, config.QueueSize),
+		client:    config.HTTPClient,
+		enabledCh: make(chan struct{}),
+		clock:     realClock{},
+		config:    config,
+	}
+	close(h.enabledCh)
+	if h.adaptive() {
+		h.logCh = make(chan interface{}, config.QueueMaxSize)
+		h.queueLimit = int64(config.QueueSize)
+	}
+	if config.PriorityFilter != "" {
+		size := config.PriorityQueueSize
+		if size <= 0 {
+			size = config.QueueSize
+		}
+		h.priorityCh = make(chan interface{}, size)
+	}
+	if config.DedupWindow > 0 {
+		h.dedup = newDedupCache(config.DedupWindow, config.DedupCacheSize)
+		h.dedup.stampReceivedAt = config.StampReceivedAt
+	}
+	if config.OnQueueFull != nil && config.QueueSize > 0 {
+		highWater := config.QueueHighWater
+		if highWater <= 0 || highWater > 100 {
+			highWater = defaultQueueHighWater
+		}
+		h.highWater = config.QueueSize * highWater / 100
+		// Recover 10% below the high water mark to avoid flapping.
+		h.lowWater = h.highWater - config.QueueSize/10
 	}
 
 	return h
@@ -199,28 +1068,308 @@ func New(config Config) *Target {
 
 // Send log message 'e' to http target.
 func (h *Target) Send(entry interface{}, errKind string) error {
+	return h.SendContext(context.Background(), entry, errKind)
+}
+
+// SendContext is like Send but the entry is not queued once ctx is
+// canceled, ctx.Err() is returned instead. It is meant for request
+// scoped logging, audit entries must be sent with Send so that they
+// are kept regardless of the outcome of the request.
+func (h *Target) SendContext(ctx context.Context, entry interface{}, errKind string) error {
 	if atomic.LoadInt32(&h.status) == 0 {
 		// Channel was closed or used before init.
 		return nil
 	}
 
+	if atomic.LoadInt32(&h.disabled) == 1 && !h.config.QueueWhileDisabled {
+		return nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var high bool
+	if h.dedup != nil || h.config.StampReceivedAt || h.filter != nil || h.priority != nil || len(h.config.APIFilter) > 0 {
+		now := h.clock.Now()
+		if logJSON, err := h.marshal(&entry); err == nil {
+			if len(h.config.APIFilter) > 0 && !h.apiAllowed(logJSON) {
+				return nil
+			}
+			if h.filter != nil && !h.filter.Match(logJSON) {
+				return nil
+			}
+			high = h.priority != nil && h.priority.Match(logJSON)
+			if !high && h.dedup != nil && h.dedup.add(logJSON, now) {
+				// Entry is held until its dedup window elapses.
+				return nil
+			}
+			// Spare marshaling the entry again once dequeued.
+			entry = json.RawMessage(logJSON)
+			if h.config.StampReceivedAt {
+				entry = json.RawMessage(stampReceivedAt(logJSON, now))
+			}
+		}
+	}
+
+	if high {
+		return h.enqueuePriority(ctx, entry)
+	}
+	return h.enqueue(ctx, entry)
+}
+
+// TestEntry is the entry sent by SendTest, its minioTest
+// field lets receivers tell it apart from actual entries.
+type TestEntry struct {
+	Test    bool      `json:"minioTest"`
+	Message string    `json:"message"`
+	Time    time.Time `json:"time"`
+}
+
+// SendTest sends a TestEntry to the endpoint bypassing the queue,
+// through the PayloadTemplate if any, and returns the outcome,
+// including the start of the response body of a rejected entry.
+// It is not counted in the target statistics.
+func (h *Target) SendTest(ctx context.Context) error {
+	if h.client == nil {
+		return types.ErrTargetNotInitialized
+	}
+	payload, err := json.Marshal(TestEntry{
+		Test:    true,
+		Message: "MinIO test entry, it can be safely ignored",
+		Time:    h.clock.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+	if payload, err = h.render(payload); err != nil {
+		return err
+	}
+	endpoint := h.config.Endpoint
+	if h.templated() {
+		endpoint = h.config.DefaultEndpoint
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, webhookCallTimeout)
+		defer cancel()
+	}
+	return h.do(ctx, endpoint, bytes.NewReader(payload), h.contentType(false), "", false)
+}
+
+func (h *Target) enqueue(ctx context.Context, entry interface{}) error {
+	if h.adaptive() && int64(len(h.logCh)) >= atomic.LoadInt64(&h.queueLimit) {
+		h.checkQueueFull()
+		return types.ErrLogBufferFull
+	}
+	if err := queued.Acquire(ctx); err != nil {
+		return err
+	}
 	select {
+	case <-ctx.Done():
+		queued.Release(1)
+		return ctx.Err()
 	case h.logCh <- entry:
 	default:
+		queued.Release(1)
+		h.checkQueueFull()
 		// log channel is full, do not wait and return
 		// an error immediately to the caller
-		return errors.New("log buffer full")
+		return types.ErrLogBufferFull
 	}
 
+	h.checkQueueFull()
 	return nil
 }
 
+// queueUsage is the queue usage OnQueueFull is called with.
+type queueUsage struct {
+	used, capacity int
+}
+
+// startQueueFullNotifier calls OnQueueFull with the queue
+// usages sent on queueFullCh, in order, until it is closed.
+func (h *Target) startQueueFullNotifier() {
+	h.queueFullCh = make(chan queueUsage, 16)
+	h.queueFullWg.Add(1)
+	go func() {
+		defer h.queueFullWg.Done()
+		for usage := range h.queueFullCh {
+			h.config.OnQueueFull(h.String(), usage.used, usage.capacity)
+		}
+	}()
+}
+
+// notifyQueueFull notifies OnQueueFull of the queue usage if the
+// queue was not already full, or with full false, if it was. The
+// lock orders the notifications as the transitions.
+func (h *Target) notifyQueueFull(full bool, used int) {
+	from, to := int32(0), int32(1)
+	if !full {
+		from, to = 1, 0
+	}
+	h.queueFullMu.Lock()
+	defer h.queueFullMu.Unlock()
+	if atomic.CompareAndSwapInt32(&h.queueFull, from, to) {
+		h.queueFullCh <- queueUsage{used: used, capacity: h.queueSize()}
+	}
+}
+
+// checkQueueFull notifies OnQueueFull when the
+// queue usage crosses the high water mark.
+func (h *Target) checkQueueFull() {
+	if h.highWater == 0 || atomic.LoadInt32(&h.queueFull) == 1 {
+		return
+	}
+	if used := len(h.logCh); used >= h.highWater {
+		h.notifyQueueFull(true, used)
+	}
+}
+
+// checkQueueRecovered notifies OnQueueFull when the
+// queue usage drops back below the high water mark.
+func (h *Target) checkQueueRecovered() {
+	if h.highWater == 0 || atomic.LoadInt32(&h.queueFull) == 0 {
+		return
+	}
+	if used := len(h.logCh); used < h.lowWater {
+		h.notifyQueueFull(false, used)
+	}
+}
+
+// SetEnabled pauses or resumes the delivery of entries, unlike
+// Cancel the target stays alive and keeps its queued entries.
+func (h *Target) SetEnabled(enabled bool) {
+	h.enabledMu.Lock()
+	defer h.enabledMu.Unlock()
+	if enabled && atomic.CompareAndSwapInt32(&h.disabled, 1, 0) {
+		close(h.enabledCh)
+	} else if !enabled && atomic.CompareAndSwapInt32(&h.disabled, 0, 1) {
+		h.enabledCh = make(chan struct{})
+	}
+}
+
+// waitEnabled blocks while the target is disabled.
+func (h *Target) waitEnabled() {
+	h.enabledMu.Lock()
+	enabledCh := h.enabledCh
+	h.enabledMu.Unlock()
+	<-enabledCh
+}
+
 // Cancel - cancels the target
 func (h *Target) Cancel() {
 	if atomic.CompareAndSwapInt32(&h.status, 1, 0) {
+		if h.heartbeatDone != nil {
+			close(h.heartbeatDone)
+			h.heartbeatWg.Wait()
+		}
+		if h.dedup != nil {
+			close(h.dedupDone)
+			h.dedupWg.Wait()
+			// Forward whatever is still held before closing.
+			for _, entry := range h.dedup.drain() {
+				h.enqueue(context.Background(), entry)
+			}
+		}
+		if h.priorityCh != nil {
+			// Closed first for its entries to be drained first.
+			close(h.priorityCh)
+		}
 		close(h.logCh)
+		// Deliver the queued entries of a disabled target.
+		h.SetEnabled(true)
+
+		h.statsMu.Lock()
+		h.offlineErr = types.ErrTargetCanceled
+		h.statsMu.Unlock()
 	}
 	h.wg.Wait()
+	h.queueFullOnce.Do(func() {
+		if h.queueFullCh != nil {
+			close(h.queueFullCh)
+			h.queueFullWg.Wait()
+		}
+	})
+
+	h.tenantMu.Lock()
+	for _, client := range h.tenantClients {
+		client.CloseIdleConnections()
+	}
+	h.tenantClients = nil
+	h.tenantMu.Unlock()
+}
+
+// IsOnline returns true if the target is initialized and not canceled,
+// and with DisableProbe, its last delivery did not fail.
+func (h *Target) IsOnline() bool {
+	if atomic.LoadInt32(&h.status) != 1 {
+		return false
+	}
+	if h.config.DisableProbe {
+		h.statsMu.Lock()
+		defer h.statsMu.Unlock()
+		return !h.lastError.After(h.lastSuccess)
+	}
+	return true
+}
+
+// OnlineStatus returns whether the target is online and if
+// not, why: the error of its initialization or the fact it
+// was not initialized yet or was canceled, or with DisableProbe
+// the error of its last delivery.
+func (h *Target) OnlineStatus() (bool, error) {
+	if h.IsOnline() {
+		return true, nil
+	}
+	h.statsMu.Lock()
+	defer h.statsMu.Unlock()
+	if h.config.DisableProbe && atomic.LoadInt32(&h.status) == 1 {
+		return false, errors.New(h.lastErrorMsg)
+	}
+	if h.offlineErr == nil {
+		return false, types.ErrTargetNotInitialized
+	}
+	return false, h.offlineErr
+}
+
+// Ready returns true if the target is online and its queue is
+// able to accept more entries, within the cap of all the queues.
+func (h *Target) Ready() bool {
+	if atomic.LoadInt32(&h.disabled) == 1 && !h.config.QueueWhileDisabled {
+		return false
+	}
+	return h.IsOnline() && len(h.logCh) < h.queueSize() && !queued.Full()
+}
+
+// Config returns the target config with its secrets redacted.
+func (h *Target) Config() Config {
+	return h.config.Redacted()
+}
+
+// Stats returns the delivery statistics of the target.
+func (h *Target) Stats() types.TargetStats {
+	_, offlineErr := h.OnlineStatus()
+	h.statsMu.Lock()
+	defer h.statsMu.Unlock()
+	stats := types.TargetStats{
+		Enabled:         atomic.LoadInt32(&h.disabled) == 0,
+		TotalMessages:   atomic.LoadInt64(&h.totalMessages),
+		FailedMessages:  atomic.LoadInt64(&h.failedMessages),
+		ExpiredMessages: atomic.LoadInt64(&h.expiredMessages),
+		QueueLength:     len(h.logCh),
+		QueueSize:       h.queueSize(),
+		LastSuccess:     h.lastSuccess,
+		LastError:       h.lastError,
+		LastErrorMsg:    h.lastErrorMsg,
+
+		PriorityQueueLength:     len(h.priorityCh),
+		SecondsSinceLastSuccess: types.SecondsSinceLastSuccess(h.lastSuccess, h.started, h.clock.Now()),
+	}
+	if offlineErr != nil {
+		stats.OfflineReason = offlineErr.Error()
+	}
+	return stats
 }
 
 // Type - returns type of the target
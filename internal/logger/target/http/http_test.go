@@ -0,0 +1,745 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/internal/logger/target/queued"
+	"github.com/minio/minio/internal/logger/target/types"
+)
+
+// countFullHandshakes sends n entries over fresh connections to a TLS
+// endpoint and returns the number of handshakes that were not resumed.
+func countFullHandshakes(t testing.TB, n, sessionCacheSize int) int32 {
+	var fullHandshakes int32
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !r.TLS.DidResume {
+			atomic.AddInt32(&fullHandshakes, 1)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	tgt := New(Config{
+		Endpoint:  srv.URL,
+		QueueSize: n,
+		Transport: &http.Transport{
+			// Force a new connection, hence a handshake, per request.
+			DisableKeepAlives: true,
+			TLSClientConfig:   &tls.Config{RootCAs: srv.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs},
+		},
+		TLSSessionCacheSize: sessionCacheSize,
+		LogOnce:             func(_ context.Context, err error, _ interface{}, _ ...interface{}) { t.Error(err) },
+	})
+	if err := tgt.Init(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < n; i++ {
+		if err := tgt.Send(map[string]int{"entry": i}, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tgt.Cancel()
+	return atomic.LoadInt32(&fullHandshakes)
+}
+
+func TestTargetTLSSessionResumption(t *testing.T) {
+	// n entries plus the probe sent by Init
+	const n = 10
+
+	if got := countFullHandshakes(t, n, -1); got != n+1 {
+		t.Fatalf("session cache disabled: expected %d full handshakes, got %d", n+1, got)
+	}
+	if got := countFullHandshakes(t, n, 0); got != 1 {
+		t.Fatalf("session cache enabled: expected 1 full handshake, got %d", got)
+	}
+}
+
+func BenchmarkTargetTLSHandshakes(b *testing.B) {
+	for _, bc := range []struct {
+		name string
+		size int
+	}{
+		{"no-session-cache", -1},
+		{"session-cache", 0},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			handshakes := countFullHandshakes(b, b.N, bc.size)
+			b.ReportMetric(float64(handshakes)/float64(b.N), "full-handshakes/op")
+		})
+	}
+}
+
+func TestTargetStats(t *testing.T) {
+	var fail int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&fail) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	tgt := New(Config{
+		Endpoint:  srv.URL,
+		QueueSize: 10,
+		Transport: http.DefaultTransport,
+		LogOnce:   func(_ context.Context, _ error, _ interface{}, _ ...interface{}) {},
+	})
+	if err := tgt.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	waitForTotal := func(total int64) types.TargetStats {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			if stats := tgt.Stats(); stats.TotalMessages == total {
+				return stats
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatalf("timed out waiting for %d messages", total)
+		return types.TargetStats{}
+	}
+
+	if err := tgt.Send(map[string]string{"message": "ok"}, ""); err != nil {
+		t.Fatal(err)
+	}
+	stats := waitForTotal(1)
+	if stats.FailedMessages != 0 || stats.LastSuccess.IsZero() || !stats.LastError.IsZero() {
+		t.Fatalf("unexpected stats after success %#v", stats)
+	}
+
+	atomic.StoreInt32(&fail, 1)
+	if err := tgt.Send(map[string]string{"message": "fail"}, ""); err != nil {
+		t.Fatal(err)
+	}
+	stats = waitForTotal(2)
+	tgt.Cancel()
+	if stats.FailedMessages != 1 || stats.LastError.Before(stats.LastSuccess) || !strings.Contains(stats.LastErrorMsg, "500") {
+		t.Fatalf("unexpected stats after failure %#v", stats)
+	}
+}
+
+func TestTargetFollowRedirects(t *testing.T) {
+	var redirected int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/regional" {
+			atomic.AddInt32(&redirected, 1)
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		http.Redirect(w, r, "/regional", http.StatusTemporaryRedirect)
+	}))
+	defer srv.Close()
+
+	tgt := New(Config{
+		Endpoint:  srv.URL + "/ingest",
+		QueueSize: 1,
+		Transport: http.DefaultTransport,
+	})
+	if err := tgt.Init(); err != nil {
+		t.Fatal(err)
+	}
+	tgt.Cancel()
+	if atomic.LoadInt32(&redirected) != 1 {
+		t.Fatal("expected redirect to be followed by default")
+	}
+
+	tgt = New(Config{
+		Endpoint:         srv.URL + "/ingest",
+		QueueSize:        1,
+		Transport:        http.DefaultTransport,
+		DisableRedirects: true,
+	})
+	err := tgt.Init()
+	if err == nil || !strings.Contains(err.Error(), "/regional") {
+		t.Fatalf("expected redirect error with its location, got %v", err)
+	}
+	if atomic.LoadInt32(&redirected) != 1 {
+		t.Fatal("expected redirect not to be followed")
+	}
+}
+
+func TestTargetTenantEndpoint(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		paths = map[string]int{}
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths[r.URL.Path]++
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	tgt := New(Config{
+		Endpoint:        srv.URL + "/{tenant}/ingest",
+		DefaultEndpoint: srv.URL + "/default/ingest",
+		TenantField:     "api.bucket",
+		TenantSeparator: "-",
+		QueueSize:       10,
+		Transport:       http.DefaultTransport,
+		LogOnce:         func(_ context.Context, err error, _ interface{}, _ ...interface{}) { t.Error(err) },
+	})
+	if err := tgt.Init(); err != nil {
+		t.Fatal(err)
+	}
+	for _, bucket := range []string{"acme-logs", "acme-data", "globex-logs", "nosep"} {
+		entry := map[string]interface{}{"api": map[string]string{"bucket": bucket}}
+		if err := tgt.Send(entry, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tgt.Send(map[string]string{"message": "no tenant"}, ""); err != nil {
+		t.Fatal(err)
+	}
+	tgt.Cancel()
+
+	mu.Lock()
+	defer mu.Unlock()
+	expected := map[string]int{
+		// Includes the probe sent by Init
+		"/default/ingest": 3,
+		"/acme/ingest":    2,
+		"/globex/ingest":  1,
+	}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Fatalf("expected %v, got %v", expected, paths)
+	}
+}
+
+func TestTargetSetEnabled(t *testing.T) {
+	for _, queueWhileDisabled := range []bool{false, true} {
+		var delivered int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var entry map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&entry); err == nil && len(entry) > 0 {
+				atomic.AddInt32(&delivered, 1)
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+
+		tgt := New(Config{
+			Endpoint:           srv.URL,
+			QueueSize:          10,
+			Transport:          http.DefaultTransport,
+			QueueWhileDisabled: queueWhileDisabled,
+			LogOnce:            func(_ context.Context, err error, _ interface{}, _ ...interface{}) { t.Error(err) },
+		})
+		if err := tgt.Init(); err != nil {
+			t.Fatal(err)
+		}
+
+		tgt.SetEnabled(false)
+		if tgt.Stats().Enabled {
+			t.Fatal("expected target to be reported disabled")
+		}
+		for i := 0; i < 3; i++ {
+			if err := tgt.Send(map[string]int{"entry": i}, ""); err != nil {
+				t.Fatal(err)
+			}
+		}
+		time.Sleep(100 * time.Millisecond)
+		if got := atomic.LoadInt32(&delivered); got != 0 {
+			t.Fatalf("expected no entries delivered while disabled, got %d", got)
+		}
+
+		tgt.SetEnabled(true)
+		if err := tgt.Send(map[string]int{"entry": 3}, ""); err != nil {
+			t.Fatal(err)
+		}
+		tgt.Cancel()
+		srv.Close()
+
+		expected := int32(1)
+		if queueWhileDisabled {
+			expected = 4
+		}
+		if got := atomic.LoadInt32(&delivered); got != expected {
+			t.Fatalf("queueWhileDisabled=%v: expected %d entries delivered, got %d", queueWhileDisabled, expected, got)
+		}
+	}
+}
+
+func TestTargetCancelDisabled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	tgt := New(Config{
+		Endpoint:           srv.URL,
+		QueueSize:          10,
+		Transport:          http.DefaultTransport,
+		QueueWhileDisabled: true,
+	})
+	if err := tgt.Init(); err != nil {
+		t.Fatal(err)
+	}
+	tgt.SetEnabled(false)
+	if err := tgt.Send(map[string]int{"entry": 1}, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		tgt.Cancel()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("cancel of a disabled target did not return")
+	}
+	if stats := tgt.Stats(); stats.TotalMessages != 1 {
+		t.Fatalf("expected queued entry to be delivered on cancel, got %d", stats.TotalMessages)
+	}
+}
+
+func TestTargetOnQueueFull(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	var mu sync.Mutex
+	var usages []int
+	tgt := New(Config{
+		Endpoint:           srv.URL,
+		QueueSize:          10,
+		QueueHighWater:     50,
+		Transport:          http.DefaultTransport,
+		QueueWhileDisabled: true,
+		OnQueueFull: func(_ string, used, capacity int) {
+			// A slow callback must not reorder the notifications.
+			time.Sleep(10 * time.Millisecond)
+			mu.Lock()
+			usages = append(usages, used)
+			mu.Unlock()
+		},
+	})
+	if err := tgt.Init(); err != nil {
+		t.Fatal(err)
+	}
+	tgt.SetEnabled(false)
+	for i := 0; i < 5; i++ {
+		if err := tgt.Send(map[string]int{"entry": i}, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tgt.SetEnabled(true)
+	tgt.Cancel()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(usages) != 2 || usages[0] < 5 || usages[1] >= 4 {
+		t.Fatalf("expected a full then a recovered notification, got usages %v", usages)
+	}
+}
+
+func TestTargetQueuedLimit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	tgt := New(Config{
+		Endpoint:           srv.URL,
+		QueueSize:          10,
+		Transport:          http.DefaultTransport,
+		QueueWhileDisabled: true,
+	})
+	if err := tgt.Init(); err != nil {
+		t.Fatal(err)
+	}
+	// Entries stay queued while disabled.
+	tgt.SetEnabled(false)
+
+	base := queued.Count()
+	queued.SetLimit(int(base)+2, queued.PolicyDrop)
+	defer queued.SetLimit(0, queued.PolicyDrop)
+	for i := 0; i < 2; i++ {
+		if err := tgt.Send(map[string]int{"entry": i}, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tgt.Send(map[string]int{"entry": 2}, ""); err != types.ErrLogBufferFull {
+		t.Fatalf("expected %v, got %v", types.ErrLogBufferFull, err)
+	}
+	if got := queued.Count(); got != base+2 {
+		t.Fatalf("expected %d queued entries, got %d", base+2, got)
+	}
+	if tgt.Ready() {
+		t.Fatal("expected the target not to be ready while the cap is reached")
+	}
+
+	tgt.Cancel()
+	if got := queued.Count(); got != base {
+		t.Fatalf("expected delivered entries to be released, got %d queued", got-base)
+	}
+	if stats := tgt.Stats(); stats.TotalMessages != 2 {
+		t.Fatalf("expected 2 entries delivered, got %d", stats.TotalMessages)
+	}
+}
+
+func TestTargetSendContext(t *testing.T) {
+	var delivered int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&delivered, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	tgt := New(Config{
+		Endpoint:  srv.URL,
+		QueueSize: 10,
+		Transport: http.DefaultTransport,
+	})
+	if err := tgt.Init(); err != nil {
+		t.Fatal(err)
+	}
+	atomic.StoreInt32(&delivered, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := tgt.SendContext(ctx, map[string]int{"entry": 1}, ""); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	if err := tgt.SendContext(ctx, map[string]int{"entry": 2}, ""); err != context.Canceled {
+		t.Fatalf("expected %v, got %v", context.Canceled, err)
+	}
+	tgt.Cancel()
+
+	if got := atomic.LoadInt32(&delivered); got != 1 {
+		t.Fatalf("expected 1 entry delivered, got %d", got)
+	}
+}
+
+func TestTargetConnectionClose(t *testing.T) {
+	for _, disableKeepAlive := range []bool{false, true} {
+		var (
+			requests, conns int32
+			closeConn       int32 = 1
+		)
+		srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			if r.Close != disableKeepAlive {
+				t.Errorf("disableKeepAlive=%v: unexpected request close %v", disableKeepAlive, r.Close)
+			}
+			// The first response asks for the connection to be closed.
+			if atomic.CompareAndSwapInt32(&closeConn, 1, 0) {
+				w.Header().Set("Connection", "close")
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+			if state == http.StateNew {
+				atomic.AddInt32(&conns, 1)
+			}
+		}
+		srv.Start()
+
+		tgt := New(Config{
+			Endpoint:         srv.URL,
+			QueueSize:        10,
+			Transport:        &http.Transport{},
+			DisableKeepAlive: disableKeepAlive,
+			LogOnce:          func(_ context.Context, err error, _ interface{}, _ ...interface{}) { t.Error(err) },
+		})
+		if err := tgt.Init(); err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 3; i++ {
+			if err := tgt.Send(map[string]int{"entry": i}, ""); err != nil {
+				t.Fatal(err)
+			}
+		}
+		tgt.Cancel()
+		srv.Close()
+
+		// The probe sent by Init closes its connection, the entries
+		// then share a single one unless keep-alive is disabled.
+		expected := int32(2)
+		if disableKeepAlive {
+			expected = 4
+		}
+		if got := atomic.LoadInt32(&conns); got != expected {
+			t.Fatalf("disableKeepAlive=%v: expected %d connections for %d requests, got %d",
+				disableKeepAlive, expected, atomic.LoadInt32(&requests), got)
+		}
+	}
+}
+
+func TestTargetSendTest(t *testing.T) {
+	var reject int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var entry TestEntry
+		if err := json.NewDecoder(r.Body).Decode(&entry); err == nil && entry.Test && atomic.LoadInt32(&reject) == 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("unknown index\n"))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	tgt := New(Config{
+		Endpoint:  srv.URL,
+		QueueSize: 1,
+		Transport: http.DefaultTransport,
+	})
+	if err := tgt.SendTest(context.Background()); err == nil {
+		t.Fatal("expected test entry of an uninitialized target to fail")
+	}
+	if err := tgt.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer tgt.Cancel()
+
+	if err := tgt.SendTest(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	atomic.StoreInt32(&reject, 1)
+	err := tgt.SendTest(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "400") || !strings.Contains(err.Error(), "unknown index") {
+		t.Fatalf("expected rejection with its status and body, got %v", err)
+	}
+	if stats := tgt.Stats(); stats.TotalMessages != 0 {
+		t.Fatalf("expected test entries not to be counted, got %d", stats.TotalMessages)
+	}
+}
+
+func TestTargetOnlineStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	tgt := New(Config{
+		Endpoint:  srv.URL,
+		QueueSize: 1,
+		Transport: http.DefaultTransport,
+	})
+	if online, err := tgt.OnlineStatus(); online || err != types.ErrTargetNotInitialized {
+		t.Fatalf("expected target not to be initialized, got %v, %v", online, err)
+	}
+	if err := tgt.Init(); err != nil {
+		t.Fatal(err)
+	}
+	if online, err := tgt.OnlineStatus(); !online || err != nil {
+		t.Fatalf("expected target to be online, got %v, %v", online, err)
+	}
+	tgt.Cancel()
+	if online, err := tgt.OnlineStatus(); online || err != types.ErrTargetCanceled {
+		t.Fatalf("expected target to be canceled, got %v, %v", online, err)
+	}
+
+	tgt = New(Config{
+		Endpoint:  srv.URL,
+		QueueSize: 1,
+		Transport: http.DefaultTransport,
+	})
+	srv.Close()
+	initErr := tgt.Init()
+	if initErr == nil {
+		t.Fatal("expected init to fail once the endpoint is down")
+	}
+	if online, err := tgt.OnlineStatus(); online || err != initErr {
+		t.Fatalf("expected the init error, got %v, %v", online, err)
+	}
+	if reason := tgt.Stats().OfflineReason; reason != initErr.Error() {
+		t.Fatalf("expected offline reason %q, got %q", initErr, reason)
+	}
+}
+
+func TestTargetFilter(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		buckets []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var entry struct {
+			Bucket string `json:"bucket"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&entry); err == nil && entry.Bucket != "" {
+			mu.Lock()
+			buckets = append(buckets, entry.Bucket)
+			mu.Unlock()
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	tgt := New(Config{
+		Endpoint:  srv.URL,
+		QueueSize: 10,
+		Transport: http.DefaultTransport,
+		Filter:    `bucket == 'sensitive' OR status >= 500`,
+	})
+	if err := tgt.Init(); err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range []map[string]interface{}{
+		{"bucket": "sensitive", "status": 200},
+		{"bucket": "other", "status": 200},
+		{"bucket": "failed", "status": 503},
+	} {
+		if err := tgt.Send(entry, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tgt.Cancel()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !reflect.DeepEqual(buckets, []string{"sensitive", "failed"}) {
+		t.Fatalf("expected the entries of sensitive and failed, got %v", buckets)
+	}
+
+	tgt = New(Config{
+		Endpoint:  srv.URL,
+		Transport: http.DefaultTransport,
+		Filter:    `bucket ==`,
+	})
+	if err := tgt.Init(); err == nil {
+		t.Fatal("expected an invalid filter to fail Init")
+	}
+}
+
+func TestTargetPathSuffix(t *testing.T) {
+	testCases := []struct {
+		endpoint, suffix, expected string
+	}{
+		{"http://proxy/prefix", "", "http://proxy/prefix"},
+		{"http://proxy/prefix", "minio/audit", "http://proxy/prefix/minio/audit"},
+		{"http://proxy/prefix/", "/minio/audit", "http://proxy/prefix/minio/audit"},
+		{"http://proxy", "/minio/audit/", "http://proxy/minio/audit/"},
+		{"http://proxy/prefix?token=x", "minio", "http://proxy/prefix/minio?token=x"},
+		{"http://proxy/{tenant}/", "minio", "http://proxy/{tenant}/minio"},
+	}
+	for i, testCase := range testCases {
+		tgt := New(Config{PathSuffix: testCase.suffix})
+		if got := tgt.withPathSuffix(testCase.endpoint); got != testCase.expected {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.expected, got)
+		}
+	}
+
+	var (
+		mu    sync.Mutex
+		paths = map[string]int{}
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths[r.URL.Path]++
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	tgt := New(Config{
+		Endpoint:   srv.URL + "/prefix/",
+		PathSuffix: "/minio/audit",
+		QueueSize:  10,
+		Transport:  http.DefaultTransport,
+		LogOnce:    func(_ context.Context, err error, _ interface{}, _ ...interface{}) { t.Error(err) },
+	})
+	if err := tgt.Init(); err != nil {
+		t.Fatal(err)
+	}
+	if err := tgt.Send(map[string]string{"message": "entry"}, ""); err != nil {
+		t.Fatal(err)
+	}
+	tgt.Cancel()
+
+	mu.Lock()
+	defer mu.Unlock()
+	// Includes the probe sent by Init
+	if expected := map[string]int{"/prefix/minio/audit": 2}; !reflect.DeepEqual(paths, expected) {
+		t.Fatalf("expected %v, got %v", expected, paths)
+	}
+}
+
+func TestTargetDisableProbe(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		var entry map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&entry); err != nil || entry["message"] == "fail" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	delivered := make(chan error, 1)
+	tgt := New(Config{
+		Endpoint:     srv.URL,
+		QueueSize:    10,
+		Transport:    http.DefaultTransport,
+		DisableProbe: true,
+		LogOnce:      func(context.Context, error, interface{}, ...interface{}) {},
+		OnDelivered:  func(_ interface{}, err error) { delivered <- err },
+	})
+	defer tgt.Cancel()
+	if err := tgt.Init(); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 0 {
+		t.Fatalf("expected no probe, got %d requests", n)
+	}
+	if !tgt.IsOnline() {
+		t.Fatal("expected the target to be online until a delivery fails")
+	}
+
+	for _, c := range []struct {
+		message string
+		online  bool
+	}{
+		{"fail", false},
+		{"entry", true},
+	} {
+		if err := tgt.Send(map[string]string{"message": c.message}, ""); err != nil {
+			t.Fatal(err)
+		}
+		<-delivered
+		online, err := tgt.OnlineStatus()
+		if online != c.online {
+			t.Fatalf("%s: expected online %v, got %v", c.message, c.online, online)
+		}
+		if !online && err == nil {
+			t.Fatalf("%s: expected the failed delivery to be reported", c.message)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+// errConnExpired is returned when writing a new request to a
+// connection past its lifetime, nothing is written then so the
+// transport sends the request again on a new connection.
+var errConnExpired = errors.New("connection exceeded its maximum lifetime")
+
+// maxLifetimeDialContext returns a dialer wrapping the connections of
+// dial so that they are closed once reused past lifetime.
+func (h *Target) maxLifetimeDialContext(dial func(ctx context.Context, network, addr string) (net.Conn, error), lifetime time.Duration) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		return &lifetimeConn{
+			Conn:    conn,
+			clock:   h.clock,
+			expires: h.clock.Now().Add(lifetime),
+		}, nil
+	}
+}
+
+// lifetimeConn is a connection expiring at the first request written
+// past its lifetime, a request in flight is never interrupted. Once
+// a response was read, the next write starts a new request, which
+// holds for HTTP/1.x connections.
+type lifetimeConn struct {
+	net.Conn
+	clock   clock
+	expires time.Time
+
+	mu   sync.Mutex
+	read bool // whether a response was read since the last write.
+}
+
+func (c *lifetimeConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n > 0 {
+		c.mu.Lock()
+		c.read = true
+		c.mu.Unlock()
+	}
+	return n, err
+}
+
+func (c *lifetimeConn) Write(b []byte) (int, error) {
+	c.mu.Lock()
+	newRequest := c.read
+	c.read = false
+	c.mu.Unlock()
+	if newRequest && c.clock.Now().After(c.expires) {
+		c.Conn.Close()
+		return 0, errConnExpired
+	}
+	return c.Conn.Write(b)
+}
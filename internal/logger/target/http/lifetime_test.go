@@ -0,0 +1,93 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTargetConnMaxLifetime(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		addrs []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		addrs = append(addrs, r.RemoteAddr)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	received := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), addrs...)
+	}
+	waitForRequests := func(n int) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for len(received()) < n {
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for %d requests", n)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	clock := newFakeClock()
+	tgt := New(Config{
+		Endpoint:        srv.URL,
+		QueueSize:       10,
+		Transport:       &http.Transport{},
+		ConnMaxLifetime: time.Minute,
+		LogOnce:         func(_ context.Context, err error, _ interface{}, _ ...interface{}) { t.Error(err) },
+	})
+	tgt.clock = clock
+	if err := tgt.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer tgt.Cancel()
+
+	// The probe sent by Init and the first entry share the connection.
+	if err := tgt.Send(map[string]int{"e": 1}, ""); err != nil {
+		t.Fatal(err)
+	}
+	waitForRequests(2)
+	clock.Advance(time.Minute + time.Second)
+	if err := tgt.Send(map[string]int{"e": 2}, ""); err != nil {
+		t.Fatal(err)
+	}
+	waitForRequests(3)
+
+	got := received()
+	if got[0] != got[1] {
+		t.Fatalf("expected the connection to be reused within its lifetime, got %v", got)
+	}
+	if got[2] == got[1] {
+		t.Fatalf("expected a new connection past the lifetime, got %v", got)
+	}
+	if stats := tgt.Stats(); stats.FailedMessages != 0 {
+		t.Fatalf("expected no failed messages, got %d", stats.FailedMessages)
+	}
+}
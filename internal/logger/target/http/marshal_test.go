@@ -0,0 +1,86 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestTargetDisableHTMLEscape(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		bodies []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		// Skip the probe sent by Init.
+		if string(body) != "{}" {
+			mu.Lock()
+			bodies = append(bodies, string(body))
+			mu.Unlock()
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	testCases := []struct {
+		config   Config
+		expected string
+	}{
+		{Config{}, `{"message":"\u003ca href=\"/b?x=1\u0026y=2\"\u003e"}`},
+		{Config{DisableHTMLEscape: true}, `{"message":"<a href=\"/b?x=1&y=2\">"}`},
+		// Entries marshaled early by the filter are not escaped either.
+		{Config{DisableHTMLEscape: true, Filter: "message"}, `{"message":"<a href=\"/b?x=1&y=2\">"}`},
+		{Config{DisableHTMLEscape: true, PayloadTemplate: `{"event": {{json .Entry}}}`}, `{"event":{"message":"<a href=\"/b?x=1&y=2\">"}}`},
+	}
+	for i, testCase := range testCases {
+		config := testCase.config
+		config.Endpoint = srv.URL
+		config.QueueSize = 1
+		config.Transport = http.DefaultTransport
+		config.LogOnce = func(_ context.Context, err error, _ interface{}, _ ...interface{}) { t.Error(err) }
+		tgt := New(config)
+		if err := tgt.Init(); err != nil {
+			t.Fatal(err)
+		}
+		entry := map[string]string{"message": `<a href="/b?x=1&y=2">`}
+		if err := tgt.Send(entry, ""); err != nil {
+			t.Fatal(err)
+		}
+		tgt.Cancel()
+
+		mu.Lock()
+		got := bodies[len(bodies)-1]
+		mu.Unlock()
+		if got != testCase.expected {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.expected, got)
+		}
+		if !json.Valid([]byte(got)) {
+			t.Errorf("Test %d: expected valid JSON, got %s", i+1, got)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import "errors"
+
+// Ordering modes, see Config.OrderingMode
+const (
+	OrderingUnordered = "unordered"
+	OrderingOrdered   = "ordered"
+)
+
+// initOrdering validates the OrderingMode, entries are sent by a
+// single worker one delivery at a time already, ordered rules out
+// the options delivering some entries ahead of the others or late.
+func (h *Target) initOrdering() error {
+	switch h.config.OrderingMode {
+	case "", OrderingUnordered:
+		return nil
+	case OrderingOrdered:
+	default:
+		return errors.New("invalid ordering mode " + h.config.OrderingMode + ", expected unordered or ordered")
+	}
+	if h.config.PriorityFilter != "" || h.config.DedupWindow > 0 || h.config.PartialFailureField != "" {
+		return errors.New("a priority filter, dedup window or partial failure field cannot be used with ordered delivery")
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTargetOrdered(t *testing.T) {
+	const n = 50
+
+	var (
+		mu       sync.Mutex
+		received []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var entry map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if entry["message"] != "" {
+			mu.Lock()
+			received = append(received, entry["message"])
+			mu.Unlock()
+		}
+	}))
+	defer srv.Close()
+
+	delivered := make(chan error, n)
+	tgt := New(Config{
+		Endpoint:     srv.URL,
+		QueueSize:    n,
+		Transport:    http.DefaultTransport,
+		OrderingMode: OrderingOrdered,
+		LogOnce:      func(_ context.Context, err error, _ interface{}, _ ...interface{}) { t.Error(err) },
+		OnDelivered:  func(_ interface{}, err error) { delivered <- err },
+	})
+	defer tgt.Cancel()
+	if err := tgt.Init(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < n; i++ {
+		if err := tgt.Send(map[string]string{"message": strconv.Itoa(i)}, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < n; i++ {
+		if err := <-delivered; err != nil {
+			t.Fatal(err)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != n {
+		t.Fatalf("expected %d entries, got %d", n, len(received))
+	}
+	for i, message := range received {
+		if message != strconv.Itoa(i) {
+			t.Fatalf("expected entry %d, got %s", i, message)
+		}
+	}
+}
+
+func TestTargetOrderingMode(t *testing.T) {
+	for _, c := range []struct {
+		config    Config
+		shouldErr bool
+	}{
+		{Config{OrderingMode: OrderingUnordered, PriorityFilter: "api.name == 'PutBucketPolicy'"}, false},
+		{Config{OrderingMode: "fifo"}, true},
+		{Config{OrderingMode: OrderingOrdered, PriorityFilter: "api.name == 'PutBucketPolicy'"}, true},
+		{Config{OrderingMode: OrderingOrdered, DedupWindow: time.Second}, true},
+		{Config{OrderingMode: OrderingOrdered, PartialFailureField: "errors"}, true},
+	} {
+		h := &Target{config: c.config}
+		if err := h.initOrdering(); (err != nil) != c.shouldErr {
+			t.Errorf("%+v: expected error %v, got %v", c.config, c.shouldErr, err)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/buger/jsonparser"
+)
+
+// Partial failure handling, see Config.PartialFailureField
+const (
+	// Largest partial failure response read.
+	maxPartialFailureBody = 1 << 20
+
+	// Times an entry of a batch is sent before being
+	// counted as failed.
+	maxBatchEntryAttempts = 3
+)
+
+// partialFailureError is returned when the endpoint accepted
+// a batch except for some of its entries.
+type partialFailureError struct {
+	endpoint string
+	indices  []int // of the rejected entries, within the batch
+}
+
+func (e *partialFailureError) Error() string {
+	return fmt.Sprintf("%s rejected %d entries of the batch, please check your endpoint configuration", e.endpoint, len(e.indices))
+}
+
+// partialFailures returns true if the response of the endpoint
+// lists the rejected entries of a batch.
+func (h *Target) partialFailures(statusCode int) bool {
+	return h.config.PartialFailureField != "" &&
+		(acceptedResponseStatusCode(statusCode) || statusCode == http.StatusMultiStatus)
+}
+
+// readPartialFailure returns a partialFailureError if the response
+// body lists rejected entries, nil if it lists none.
+func (h *Target) readPartialFailure(endpoint string, body io.Reader) error {
+	data, err := ioutil.ReadAll(io.LimitReader(body, maxPartialFailureBody))
+	if err != nil {
+		return fmt.Errorf("%s returned '%w', please check your endpoint configuration", endpoint, err)
+	}
+	var indices []int
+	var parseErr error
+	_, err = jsonparser.ArrayEach(data, func(value []byte, dataType jsonparser.ValueType, _ int, _ error) {
+		if h.config.PartialFailureIndexKey != "" {
+			value, dataType, _, _ = jsonparser.Get(value, h.config.PartialFailureIndexKey)
+		}
+		index, err := jsonparser.ParseInt(value)
+		if dataType != jsonparser.Number || err != nil {
+			parseErr = errors.New("invalid entry index")
+			return
+		}
+		indices = append(indices, int(index))
+	}, strings.Split(h.config.PartialFailureField, ".")...)
+	switch {
+	case errors.Is(err, jsonparser.KeyPathNotFoundError):
+		// Every entry was accepted.
+		return nil
+	case err == nil:
+		err = parseErr
+	}
+	if err != nil {
+		return fmt.Errorf("%s returned an invalid partial failure response: %v", endpoint, err)
+	}
+	if len(indices) == 0 {
+		return nil
+	}
+	return &partialFailureError{endpoint: endpoint, indices: indices}
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTargetBatchPartialFailure(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		batches []int
+		seen    = map[string]bool{}
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/x-ndjson" {
+			// Probe sent by Init.
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		var rejected []string
+		scanner := bufio.NewScanner(r.Body)
+		i := 0
+		for ; scanner.Scan(); i++ {
+			var entry struct {
+				Fail string `json:"fail"`
+			}
+			json.Unmarshal(scanner.Bytes(), &entry)
+			if entry.Fail == "always" || (entry.Fail == "once" && !seen[scanner.Text()]) {
+				rejected = append(rejected, fmt.Sprintf(`{"index":%d}`, i))
+			}
+			seen[scanner.Text()] = true
+		}
+		batches = append(batches, i)
+		w.WriteHeader(http.StatusMultiStatus)
+		fmt.Fprintf(w, `{"result":{"errors":[%s]}}`, strings.Join(rejected, ","))
+	}))
+	defer srv.Close()
+
+	tgt := New(Config{
+		Endpoint:               srv.URL,
+		QueueSize:              10,
+		Transport:              http.DefaultTransport,
+		BatchMaxBytes:          1 << 20,
+		BatchInterval:          time.Hour,
+		PartialFailureField:    "result.errors",
+		PartialFailureIndexKey: "index",
+		LogOnce:                func(_ context.Context, _ error, _ interface{}, _ ...interface{}) {},
+	})
+	if err := tgt.Init(); err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range []map[string]string{
+		{"e": "1"},
+		{"e": "2", "fail": "once"},
+		{"e": "3", "fail": "always"},
+		{"e": "4"},
+	} {
+		if err := tgt.Send(entry, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tgt.Cancel()
+
+	mu.Lock()
+	defer mu.Unlock()
+	// Only the rejected entries are sent again, up to 3 times.
+	if expected := []int{4, 2, 1}; !reflect.DeepEqual(batches, expected) {
+		t.Fatalf("expected batches %v, got %v", expected, batches)
+	}
+	if stats := tgt.Stats(); stats.TotalMessages != 4 || stats.FailedMessages != 1 {
+		t.Fatalf("expected 4 messages of which 1 failed, got %d and %d", stats.TotalMessages, stats.FailedMessages)
+	}
+}
+
+func TestReadPartialFailure(t *testing.T) {
+	testCases := []struct {
+		field, indexKey, body string
+		expected              []int
+		shouldErr             bool
+	}{
+		{"errors", "", `{"errors":[1,3]}`, []int{1, 3}, false},
+		{"errors", "", `{"errors":[]}`, nil, false},
+		{"errors", "", `{"accepted":true}`, nil, false},
+		{"items.failed", "i", `{"items":{"failed":[{"i":0,"reason":"bad"}]}}`, []int{0}, false},
+		{"errors", "", `{"errors":["x"]}`, nil, true},
+		{"errors", "", `{"errors":`, nil, true},
+	}
+	for i, testCase := range testCases {
+		tgt := New(Config{PartialFailureField: testCase.field, PartialFailureIndexKey: testCase.indexKey})
+		err := tgt.readPartialFailure("endpoint", strings.NewReader(testCase.body))
+		if testCase.shouldErr {
+			if err == nil {
+				t.Errorf("Test %d: expected an error", i+1)
+			}
+			continue
+		}
+		var indices []int
+		if pf, ok := err.(*partialFailureError); ok {
+			indices = pf.indices
+		} else if err != nil {
+			t.Errorf("Test %d: unexpected error %v", i+1, err)
+			continue
+		}
+		if !reflect.DeepEqual(indices, testCase.expected) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, indices)
+		}
+	}
+}
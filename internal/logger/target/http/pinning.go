@@ -0,0 +1,69 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrCertPinMismatch is returned when the certificates presented by
+// the endpoint match none of the PinnedServerCertSHA256.
+var ErrCertPinMismatch = errors.New("server certificate does not match any pinned certificate")
+
+// ParseCertPins parses SHA-256 certificate fingerprints, hex encoded
+// with or without colons, e.g. as printed by openssl x509 -fingerprint.
+func ParseCertPins(pins []string) ([][]byte, error) {
+	parsed := make([][]byte, 0, len(pins))
+	for _, pin := range pins {
+		sum, err := hex.DecodeString(strings.ReplaceAll(strings.TrimSpace(pin), ":", ""))
+		if err != nil || len(sum) != sha256.Size {
+			return nil, fmt.Errorf("invalid SHA-256 certificate fingerprint %q", pin)
+		}
+		parsed = append(parsed, sum)
+	}
+	return parsed, nil
+}
+
+// pinCertificates makes cfg reject the connections to endpoints presenting
+// no certificate matching one of pins, on top of the usual verification.
+// It is checked on resumed sessions as well.
+func pinCertificates(cfg *tls.Config, pins [][]byte) {
+	verify := cfg.VerifyConnection
+	cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+		if verify != nil {
+			if err := verify(cs); err != nil {
+				return err
+			}
+		}
+		for _, cert := range cs.PeerCertificates {
+			sum := sha256.Sum256(cert.Raw)
+			for _, pin := range pins {
+				if bytes.Equal(sum[:], pin) {
+					return nil
+				}
+			}
+		}
+		return ErrCertPinMismatch
+	}
+}
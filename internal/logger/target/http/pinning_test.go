@@ -0,0 +1,114 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseCertPins(t *testing.T) {
+	sum := sha256.Sum256([]byte("cert"))
+	pin := hex.EncodeToString(sum[:])
+	var colons []string
+	for i := 0; i < len(pin); i += 2 {
+		colons = append(colons, strings.ToUpper(pin[i:i+2]))
+	}
+	pins, err := ParseCertPins([]string{pin, strings.Join(colons, ":")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range pins {
+		if hex.EncodeToString(p) != pin {
+			t.Fatalf("expected %s, got %x", pin, p)
+		}
+	}
+	for _, invalid := range []string{"", "zz", pin[:32]} {
+		if _, err := ParseCertPins([]string{invalid}); err == nil {
+			t.Fatalf("%q: expected an error", invalid)
+		}
+	}
+}
+
+func TestTargetPinnedServerCert(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	sum := sha256.Sum256(srv.Certificate().Raw)
+	pin := hex.EncodeToString(sum[:])
+	other := strings.Repeat("00", sha256.Size)
+
+	for _, c := range []struct {
+		name string
+		pins []string
+		err  error
+	}{
+		{"pinned", []string{pin}, nil},
+		{"rotation", []string{other, pin}, nil},
+		{"mismatch", []string{other}, ErrCertPinMismatch},
+	} {
+		delivered := make(chan error, 1)
+		tgt := New(Config{
+			Endpoint:               srv.URL,
+			QueueSize:              10,
+			Transport:              srv.Client().Transport,
+			PinnedServerCertSHA256: c.pins,
+			DisableProbe:           true,
+			LogOnce:                func(context.Context, error, interface{}, ...interface{}) {},
+			OnDelivered:            func(_ interface{}, err error) { delivered <- err },
+		})
+		if err := tgt.Init(); err != nil {
+			t.Fatal(err)
+		}
+		if err := tgt.Send(map[string]string{"message": c.name}, ""); err != nil {
+			t.Fatal(err)
+		}
+		if err := <-delivered; !errors.Is(err, c.err) || (c.err == nil) != (err == nil) {
+			t.Fatalf("%s: expected %v, got %v", c.name, c.err, err)
+		}
+		tgt.Cancel()
+	}
+
+	tgt := New(Config{
+		Endpoint:               srv.URL,
+		Transport:              roundTripperFunc(http.DefaultTransport.RoundTrip),
+		PinnedServerCertSHA256: []string{pin},
+	})
+	if err := tgt.Init(); err == nil {
+		t.Fatal("expected pinning to require an *http.Transport")
+	}
+
+	tgt = New(Config{
+		Endpoint:               srv.URL,
+		HTTPClient:             srv.Client(),
+		PinnedServerCertSHA256: []string{other},
+	})
+	if err := tgt.Init(); err == nil {
+		t.Fatal("expected pinning to be rejected with a custom http client")
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
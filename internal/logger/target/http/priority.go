@@ -0,0 +1,78 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"context"
+	"time"
+
+	"github.com/minio/minio/internal/logger/target/queued"
+	"github.com/minio/minio/internal/logger/target/types"
+)
+
+// enqueuePriority queues a high priority entry in the priority lane.
+func (h *Target) enqueuePriority(ctx context.Context, entry interface{}) error {
+	if err := queued.Acquire(ctx); err != nil {
+		return err
+	}
+	select {
+	case <-ctx.Done():
+		queued.Release(1)
+		return ctx.Err()
+	case h.priorityCh <- entry:
+		return nil
+	default:
+		queued.Release(1)
+		return types.ErrLogBufferFull
+	}
+}
+
+// receive returns the next queued entry, the entries of the priority
+// lane first, and whether it is a high priority one. lane is the
+// priority lane, set to nil once it is closed. It returns with fired
+// set once timerC fires and with ok unset once the queue is closed.
+func (h *Target) receive(lane *chan interface{}, timerC <-chan time.Time) (entry interface{}, high, ok, fired bool) {
+	for {
+		select {
+		case entry, ok = <-*lane:
+			if !ok {
+				*lane = nil
+				continue
+			}
+			queued.Release(1)
+			return entry, true, true, false
+		default:
+		}
+		select {
+		case entry, ok = <-*lane:
+			if !ok {
+				*lane = nil
+				continue
+			}
+			queued.Release(1)
+			return entry, true, true, false
+		case entry, ok = <-h.logCh:
+			if ok {
+				queued.Release(1)
+			}
+			return entry, false, ok, false
+		case <-timerC:
+			return nil, false, true, true
+		}
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTargetPriority(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		for _, line := range strings.Split(strings.TrimSpace(string(body)), "\n") {
+			var entry struct {
+				Name string `json:"name"`
+			}
+			// Skips the probe sent by Init.
+			if err := json.Unmarshal([]byte(line), &entry); err == nil && entry.Name != "" {
+				received = append(received, entry.Name)
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	for _, batch := range []bool{false, true} {
+		received = nil
+		config := Config{
+			Endpoint:           srv.URL,
+			QueueSize:          10,
+			Transport:          http.DefaultTransport,
+			QueueWhileDisabled: true,
+			PriorityFilter:     `name == 'urgent'`,
+			LogOnce:            func(context.Context, error, interface{}, ...interface{}) {},
+		}
+		if batch {
+			config.BatchMaxBytes = 1 << 20
+			config.BatchInterval = time.Hour
+		}
+		tgt := New(config)
+		if err := tgt.Init(); err != nil {
+			t.Fatal(err)
+		}
+		tgt.SetEnabled(false)
+		for _, name := range []string{"a", "b", "c", "urgent"} {
+			if err := tgt.Send(map[string]interface{}{"name": name}, ""); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if n := tgt.Stats().PriorityQueueLength; n != 1 {
+			t.Fatalf("batch %v: expected 1 entry in the priority lane, got %d", batch, n)
+		}
+		tgt.SetEnabled(true)
+		tgt.Cancel()
+
+		if len(received) != 4 {
+			t.Fatalf("batch %v: expected 4 entries, got %v", batch, received)
+		}
+		// The worker may hold an entry taken before being disabled.
+		if received[0] != "urgent" && received[1] != "urgent" {
+			t.Fatalf("batch %v: expected the urgent entry first, got %v", batch, received)
+		}
+	}
+}
+
+func TestTargetPriorityQueueFull(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	tgt := New(Config{
+		Endpoint:           srv.URL,
+		QueueSize:          10,
+		PriorityQueueSize:  1,
+		Transport:          http.DefaultTransport,
+		QueueWhileDisabled: true,
+		PriorityFilter:     `name == 'urgent'`,
+		LogOnce:            func(context.Context, error, interface{}, ...interface{}) {},
+	})
+	if err := tgt.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer tgt.Cancel()
+	tgt.SetEnabled(false)
+
+	// The worker may hold an entry taken before being disabled.
+	var err error
+	for i := 0; i < 3 && err == nil; i++ {
+		err = tgt.Send(map[string]interface{}{"name": "urgent"}, "")
+	}
+	if err == nil {
+		t.Fatal("expected the full priority lane to be reported")
+	}
+	// The regular queue is left untouched.
+	if err = tgt.Send(map[string]interface{}{"name": "other"}, ""); err != nil {
+		t.Fatal(err)
+	}
+}
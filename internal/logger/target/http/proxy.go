@@ -0,0 +1,151 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/proxy"
+)
+
+// defaultDialContext is the same dialer as the one of http.DefaultTransport.
+var defaultDialContext = (&net.Dialer{
+	Timeout:   30 * time.Second,
+	KeepAlive: 30 * time.Second,
+}).DialContext
+
+// contextDialer adapts a dial function to proxy.Dialer
+// and proxy.ContextDialer.
+type contextDialer func(ctx context.Context, network, addr string) (net.Conn, error)
+
+func (d contextDialer) Dial(network, addr string) (net.Conn, error) {
+	return d(context.Background(), network, addr)
+}
+
+func (d contextDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	return d(ctx, network, addr)
+}
+
+// ValidateProxy parses the proxy URL, it must have a host and
+// either a socks5, socks5h, http or https scheme.
+func ValidateProxy(proxyURL string) (*url.URL, error) {
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy %s: %w", proxyURL, err)
+	}
+	switch u.Scheme {
+	case "socks5", "socks5h", "http", "https":
+	default:
+		return nil, fmt.Errorf("invalid proxy %s: unsupported scheme %q", u.Redacted(), u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy %s: missing host", u.Redacted())
+	}
+	return u, nil
+}
+
+// noProxy is a NO_PROXY style list of hosts reached directly.
+type noProxy []string
+
+// parseNoProxy parses a comma separated list of hosts, domains
+// matching their subdomains, IPs, CIDRs, or `*` for any host.
+func parseNoProxy(list string) (hosts noProxy) {
+	for _, host := range strings.Split(list, ",") {
+		host = strings.ToLower(strings.TrimSpace(host))
+		if host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
+// bypass returns true if addr, a host with an optional port,
+// must not go through the proxy.
+func (hosts noProxy) bypass(addr string) bool {
+	host := addr
+	if h, _, err := net.SplitHostPort(addr); err == nil {
+		host = h
+	}
+	host = strings.ToLower(host)
+	ip := net.ParseIP(host)
+	for _, entry := range hosts {
+		if entry == "*" {
+			return true
+		}
+		if _, ipnet, err := net.ParseCIDR(entry); err == nil {
+			if ip != nil && ipnet.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		domain := strings.TrimPrefix(entry, ".")
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// setProxy configures tr to connect through the proxy at proxyURL,
+// except for the hosts in the noProxy list. SOCKS5 proxies are dialed
+// through the transport dialer while any other scheme is used as an
+// HTTP proxy.
+func setProxy(tr *http.Transport, proxyURL, noProxyList string) error {
+	u, err := ValidateProxy(proxyURL)
+	if err != nil {
+		return err
+	}
+	bypass := parseNoProxy(noProxyList)
+	switch u.Scheme {
+	case "socks5", "socks5h":
+		dial := tr.DialContext
+		if dial == nil {
+			dial = defaultDialContext
+		}
+		d, err := proxy.FromURL(u, contextDialer(dial))
+		if err != nil {
+			return fmt.Errorf("invalid proxy %s: %w", u.Redacted(), err)
+		}
+		cd, ok := d.(proxy.ContextDialer)
+		if !ok {
+			return errors.New("socks5 proxy dialer does not support contexts")
+		}
+		tr.Proxy = nil
+		tr.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			if bypass.bypass(addr) {
+				return dial(ctx, network, addr)
+			}
+			return cd.DialContext(ctx, network, addr)
+		}
+	default:
+		tr.Proxy = func(req *http.Request) (*url.URL, error) {
+			if bypass.bypass(req.URL.Host) {
+				return nil, nil
+			}
+			return u, nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,286 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// startSOCKS5Proxy starts a minimal SOCKS5 proxy requiring the
+// given credentials and returns its address and the number of
+// connections it proxied.
+func startSOCKS5Proxy(t *testing.T, user, pass string) (string, *int32) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	var proxied int32
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				target, err := socks5Handshake(conn, user, pass)
+				if err != nil {
+					return
+				}
+				upstream, err := net.Dial("tcp", target)
+				if err != nil {
+					return
+				}
+				defer upstream.Close()
+				atomic.AddInt32(&proxied, 1)
+				// Reply succeeded with a zero bound address.
+				if _, err = conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0}); err != nil {
+					return
+				}
+				go io.Copy(upstream, conn)
+				io.Copy(conn, upstream)
+			}()
+		}
+	}()
+	return l.Addr().String(), &proxied
+}
+
+// socks5Handshake authenticates the client with username/password
+// and returns the address of its CONNECT request.
+func socks5Handshake(conn net.Conn, user, pass string) (string, error) {
+	readBytes := func(n int) ([]byte, error) {
+		b := make([]byte, n)
+		_, err := io.ReadFull(conn, b)
+		return b, err
+	}
+
+	// Version and methods, only username/password is accepted.
+	hdr, err := readBytes(2)
+	if err != nil {
+		return "", err
+	}
+	if _, err = readBytes(int(hdr[1])); err != nil {
+		return "", err
+	}
+	if _, err = conn.Write([]byte{5, 2}); err != nil {
+		return "", err
+	}
+
+	// Username/password sub-negotiation.
+	ver, err := readBytes(2)
+	if err != nil {
+		return "", err
+	}
+	u, err := readBytes(int(ver[1]))
+	if err != nil {
+		return "", err
+	}
+	plen, err := readBytes(1)
+	if err != nil {
+		return "", err
+	}
+	p, err := readBytes(int(plen[0]))
+	if err != nil {
+		return "", err
+	}
+	if string(u) != user || string(p) != pass {
+		conn.Write([]byte{1, 1})
+		return "", io.EOF
+	}
+	if _, err = conn.Write([]byte{1, 0}); err != nil {
+		return "", err
+	}
+
+	// CONNECT request.
+	req, err := readBytes(4)
+	if err != nil {
+		return "", err
+	}
+	var host string
+	switch req[3] {
+	case 1:
+		ip, err := readBytes(4)
+		if err != nil {
+			return "", err
+		}
+		host = net.IP(ip).String()
+	case 3:
+		n, err := readBytes(1)
+		if err != nil {
+			return "", err
+		}
+		name, err := readBytes(int(n[0]))
+		if err != nil {
+			return "", err
+		}
+		host = string(name)
+	default:
+		return "", io.EOF
+	}
+	port, err := readBytes(2)
+	if err != nil {
+		return "", err
+	}
+	return net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port)))), nil
+}
+
+func TestTargetSOCKS5Proxy(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	proxyAddr, proxied := startSOCKS5Proxy(t, "user", "secret")
+
+	tgt := New(Config{
+		Endpoint:  srv.URL,
+		QueueSize: 1,
+		Transport: &http.Transport{DisableKeepAlives: true},
+		Proxy:     "socks5://user:wrong@" + proxyAddr,
+	})
+	if err := tgt.Init(); err == nil {
+		t.Fatal("expected wrong proxy credentials to fail")
+	}
+
+	tgt = New(Config{
+		Endpoint:  srv.URL,
+		QueueSize: 1,
+		Transport: &http.Transport{DisableKeepAlives: true},
+		Proxy:     "socks5://user:secret@" + proxyAddr,
+	})
+	if err := tgt.Init(); err != nil {
+		t.Fatal(err)
+	}
+	tgt.Cancel()
+	if got := atomic.LoadInt32(proxied); got != 1 {
+		t.Fatalf("expected 1 proxied connection, got %d", got)
+	}
+}
+
+func TestTargetHTTPProxy(t *testing.T) {
+	var proxied int32
+	proxySrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Requests to an HTTP proxy carry the absolute URL of the endpoint.
+		if r.URL.Host == "logs.example.com" {
+			atomic.AddInt32(&proxied, 1)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxySrv.Close()
+
+	tgt := New(Config{
+		Endpoint:  "http://logs.example.com/ingest",
+		QueueSize: 1,
+		Transport: &http.Transport{},
+		Proxy:     proxySrv.URL,
+	})
+	if err := tgt.Init(); err != nil {
+		t.Fatal(err)
+	}
+	tgt.Cancel()
+	if got := atomic.LoadInt32(&proxied); got != 1 {
+		t.Fatalf("expected 1 proxied request, got %d", got)
+	}
+}
+
+func TestTargetProxyCustomClient(t *testing.T) {
+	for _, config := range []Config{
+		{Endpoint: "http://localhost:8080", Proxy: "http://proxy:3128"},
+		{Endpoint: "http://localhost:8080", NoProxy: "localhost"},
+		{Endpoint: "http://localhost:8080", DNSCacheTTL: time.Minute},
+		{Endpoint: "http://localhost:8080", ConnMaxLifetime: time.Minute},
+		{Endpoint: "http://localhost:8080", TLSSessionCacheSize: 10},
+	} {
+		config.HTTPClient = &http.Client{}
+		if err := New(config).Init(); err == nil {
+			t.Errorf("expected %+v to be rejected with a custom http client", config)
+		}
+	}
+}
+
+func TestSetProxyInvalid(t *testing.T) {
+	for _, proxyURL := range []string{"ftp://proxy:21", "://invalid", "http://", "proxy:3128"} {
+		if err := setProxy(&http.Transport{}, proxyURL, ""); err == nil {
+			t.Errorf("expected %s to be rejected", proxyURL)
+		}
+	}
+}
+
+func TestNoProxyBypass(t *testing.T) {
+	hosts := parseNoProxy(" example.com, .internal.net ,10.0.0.0/8,::1 ")
+	testCases := []struct {
+		addr   string
+		bypass bool
+	}{
+		{"example.com", true},
+		{"logs.example.com:443", true},
+		{"notexample.com", false},
+		{"internal.net", true},
+		{"a.b.internal.net:8080", true},
+		{"10.1.2.3:9000", true},
+		{"11.1.2.3", false},
+		{"[::1]:9000", true},
+		{"logs.example.org", false},
+	}
+	for i, testCase := range testCases {
+		if got := hosts.bypass(testCase.addr); got != testCase.bypass {
+			t.Errorf("Test %d: %s: expected bypass %v, got %v", i+1, testCase.addr, testCase.bypass, got)
+		}
+	}
+	if !parseNoProxy("*").bypass("anything:80") {
+		t.Error("expected * to bypass any host")
+	}
+}
+
+func TestTargetNoProxy(t *testing.T) {
+	var proxied int32
+	proxySrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&proxied, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxySrv.Close()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	tgt := New(Config{
+		Endpoint:  srv.URL,
+		QueueSize: 1,
+		Transport: &http.Transport{},
+		Proxy:     proxySrv.URL,
+		NoProxy:   "127.0.0.0/8",
+	})
+	if err := tgt.Init(); err != nil {
+		t.Fatal(err)
+	}
+	tgt.Cancel()
+	if got := atomic.LoadInt32(&proxied); got != 0 {
+		t.Fatalf("expected endpoint to be reached directly, got %d proxied requests", got)
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// receivedAtField is the field stamped on entries with StampReceivedAt.
+const receivedAtField = "receivedAt"
+
+// stampReceivedAt adds the receivedAt field with the given time to
+// a marshaled JSON object unless it already has one.
+func stampReceivedAt(data []byte, receivedAt time.Time) []byte {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		// Not a JSON object, leave it as is.
+		return data
+	}
+	if _, ok := fields[receivedAtField]; ok {
+		return data
+	}
+	value := strconv.Quote(receivedAt.UTC().Format(time.RFC3339Nano))
+	return addJSONField(data, receivedAtField, []byte(value))
+}
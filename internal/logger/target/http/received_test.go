@@ -0,0 +1,95 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStampReceivedAt(t *testing.T) {
+	receivedAt := time.Date(2022, 3, 4, 5, 6, 7, 8, time.FixedZone("", 3600))
+	testCases := []struct {
+		data     string
+		expected string
+	}{
+		{`{"a":1}`, `{"a":1,"receivedAt":"2022-03-04T04:06:07.000000008Z"}`},
+		{`{"receivedAt":"client"}`, `{"receivedAt":"client"}`},
+		{`[1,2]`, `[1,2]`},
+	}
+	for i, testCase := range testCases {
+		got := string(stampReceivedAt([]byte(testCase.data), receivedAt))
+		if got != testCase.expected {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.expected, got)
+		}
+	}
+}
+
+func TestTargetStampReceivedAtQueued(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		entries []map[string]string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var entry map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&entry); err == nil && len(entry) > 0 {
+			mu.Lock()
+			entries = append(entries, entry)
+			mu.Unlock()
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	tgt := New(Config{
+		Endpoint:           srv.URL,
+		QueueSize:          10,
+		Transport:          http.DefaultTransport,
+		QueueWhileDisabled: true,
+		StampReceivedAt:    true,
+	})
+	if err := tgt.Init(); err != nil {
+		t.Fatal(err)
+	}
+	tgt.SetEnabled(false)
+	sent := time.Now()
+	if err := tgt.Send(map[string]string{"message": "queued"}, ""); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	enabled := time.Now()
+	tgt.SetEnabled(true)
+	tgt.Cancel()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	receivedAt, err := time.Parse(time.RFC3339Nano, entries[0]["receivedAt"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receivedAt.Before(sent) || !receivedAt.Before(enabled) {
+		t.Fatalf("expected receivedAt between %s and %s, got %s", sent, enabled, receivedAt)
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+
+	xhttp "github.com/minio/minio/internal/http"
+)
+
+// RegistrationEntry is the entry sent when the target comes online,
+// see Config.Registration, its minioRegistration field tells it apart
+// from actual entries. Each one starts a new session.
+type RegistrationEntry struct {
+	Registration bool              `json:"minioRegistration"`
+	Session      string            `json:"session"`
+	Time         time.Time         `json:"time"`
+	DeploymentID string            `json:"deploymentid,omitempty"`
+	Version      string            `json:"version,omitempty"`
+	Fields       map[string]string `json:"fields,omitempty"`
+}
+
+// register sends a RegistrationEntry ahead of the next delivery once
+// the target came online, on Init or after a failed delivery. Its
+// failure is logged and the delivery goes on, it is sent again
+// before the next one until it is accepted.
+func (h *Target) register() {
+	if atomic.LoadInt32(&h.unregistered) == 0 {
+		return
+	}
+	payload, err := json.Marshal(RegistrationEntry{
+		Registration: true,
+		Session:      uuid.New().String(),
+		Time:         h.clock.Now().UTC(),
+		DeploymentID: xhttp.GlobalDeploymentID,
+		Version:      xhttp.GlobalMinIOVersion,
+		Fields:       h.config.RegistrationFields,
+	})
+	if err == nil {
+		payload, err = h.render(payload)
+	}
+	if err == nil {
+		endpoint := h.config.Endpoint
+		if h.templated() {
+			endpoint = h.config.DefaultEndpoint
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), webhookCallTimeout)
+		err = h.do(ctx, endpoint, bytes.NewReader(payload), "application/json", "", false)
+		cancel()
+	}
+	if err != nil {
+		h.config.LogOnce(context.Background(), err, h.config.Endpoint)
+		return
+	}
+	atomic.StoreInt32(&h.unregistered, 0)
+}
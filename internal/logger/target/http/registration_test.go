@@ -0,0 +1,97 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestTargetRegistration(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received []map[string]interface{}
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var entry map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		received = append(received, entry)
+		mu.Unlock()
+		if entry["message"] == "fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	delivered := make(chan error, 1)
+	tgt := New(Config{
+		Endpoint:           srv.URL,
+		QueueSize:          10,
+		Transport:          http.DefaultTransport,
+		DisableProbe:       true,
+		Registration:       true,
+		RegistrationFields: map[string]string{"site": "site1"},
+		LogOnce:            func(context.Context, error, interface{}, ...interface{}) {},
+		OnDelivered:        func(_ interface{}, err error) { delivered <- err },
+	})
+	defer tgt.Cancel()
+	if err := tgt.Init(); err != nil {
+		t.Fatal(err)
+	}
+	for _, message := range []string{"first", "second", "fail", "third"} {
+		if err := tgt.Send(map[string]string{"message": message}, ""); err != nil {
+			t.Fatal(err)
+		}
+		<-delivered
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	// A session starts on Init and after the failed delivery.
+	expected := []string{"", "first", "second", "fail", "", "third"}
+	if len(received) != len(expected) {
+		t.Fatalf("expected %d requests, got %v", len(expected), received)
+	}
+	sessions := map[interface{}]bool{}
+	for i, entry := range received {
+		if expected[i] != "" {
+			if entry["message"] != expected[i] {
+				t.Fatalf("request %d: expected entry %s, got %v", i, expected[i], entry)
+			}
+			continue
+		}
+		if entry["minioRegistration"] != true {
+			t.Fatalf("request %d: expected a registration entry, got %v", i, entry)
+		}
+		if fields, _ := entry["fields"].(map[string]interface{}); fields["site"] != "site1" {
+			t.Fatalf("request %d: expected the registration fields, got %v", i, entry)
+		}
+		sessions[entry["session"]] = true
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("expected 2 sessions, got %v", sessions)
+	}
+}
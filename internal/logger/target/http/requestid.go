@@ -0,0 +1,45 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"github.com/buger/jsonparser"
+	"github.com/google/uuid"
+)
+
+// entryRequestID returns the request ID of a json encoded
+// entry, empty if request IDs are not sent or it has none.
+func (h *Target) entryRequestID(logJSON []byte) string {
+	if h.config.RequestIDHeader == "" {
+		return ""
+	}
+	id, err := jsonparser.GetString(logJSON, "requestID")
+	if err != nil {
+		return ""
+	}
+	return id
+}
+
+// batchRequestID returns an ID identifying a batch of entries,
+// empty if request IDs are not sent.
+func (h *Target) batchRequestID() string {
+	if h.config.RequestIDHeader == "" {
+		return ""
+	}
+	return uuid.New().String()
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// requestIDServer records the request ID header of the entries received.
+func requestIDServer(t *testing.T) (*httptest.Server, func() []string) {
+	var (
+		mu  sync.Mutex
+		ids []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Skip the probe sent by Init.
+		if r.ContentLength != 2 {
+			mu.Lock()
+			ids = append(ids, r.Header.Get("X-Request-ID"))
+			mu.Unlock()
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	return srv, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), ids...)
+	}
+}
+
+func TestTargetRequestIDHeader(t *testing.T) {
+	srv, received := requestIDServer(t)
+	defer srv.Close()
+
+	send := func(header string) []string {
+		t.Helper()
+		tgt := New(Config{
+			Endpoint:        srv.URL,
+			QueueSize:       10,
+			Transport:       http.DefaultTransport,
+			RequestIDHeader: header,
+			LogOnce:         func(_ context.Context, err error, _ interface{}, _ ...interface{}) { t.Error(err) },
+		})
+		if err := tgt.Init(); err != nil {
+			t.Fatal(err)
+		}
+		before := len(received())
+		for _, entry := range []map[string]string{
+			{"requestID": "16F0A3E2C4B5D6E7"},
+			{"api": "no-request-id"},
+		} {
+			if err := tgt.Send(entry, ""); err != nil {
+				t.Fatal(err)
+			}
+		}
+		tgt.Cancel()
+		return received()[before:]
+	}
+
+	if got := send(""); !reflect.DeepEqual(got, []string{"", ""}) {
+		t.Fatalf("expected no request IDs by default, got %v", got)
+	}
+	if got := send("X-Request-ID"); !reflect.DeepEqual(got, []string{"16F0A3E2C4B5D6E7", ""}) {
+		t.Fatalf("expected the request ID of the entry, got %v", got)
+	}
+}
+
+func TestTargetBatchRequestIDHeader(t *testing.T) {
+	srv, received := requestIDServer(t)
+	defer srv.Close()
+
+	tgt := New(Config{
+		Endpoint:        srv.URL,
+		QueueSize:       10,
+		Transport:       http.DefaultTransport,
+		BatchMaxBytes:   1 << 20,
+		BatchInterval:   time.Hour,
+		RequestIDHeader: "X-Request-ID",
+		LogOnce:         func(_ context.Context, err error, _ interface{}, _ ...interface{}) { t.Error(err) },
+	})
+	if err := tgt.Init(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := tgt.Send(map[string]string{"requestID": "entry"}, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tgt.Cancel()
+
+	got := received()
+	if len(got) != 1 || got[0] == "" || got[0] == "entry" {
+		t.Fatalf("expected a single batch with its own ID, got %v", got)
+	}
+}
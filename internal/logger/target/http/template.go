@@ -0,0 +1,93 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"text/template"
+
+	xhttp "github.com/minio/minio/internal/http"
+)
+
+// templateData is the data a PayloadTemplate is executed with.
+type templateData struct {
+	// Entry is the entry decoded from its JSON encoding.
+	Entry        interface{}
+	DeploymentID string
+	Version      string
+}
+
+// templateFuncs returns the functions available to a PayloadTemplate
+// on top of the text/template builtins.
+func templateFuncs(escapeHTML bool) template.FuncMap {
+	return template.FuncMap{
+		// json returns the JSON encoding of a value, to embed
+		// the entry or any of its fields in the payload.
+		"json": func(v interface{}) (string, error) {
+			b, err := marshalJSON(v, escapeHTML)
+			return string(b), err
+		},
+	}
+}
+
+// parsePayloadTemplate parses the PayloadTemplate, if any, its json
+// function escapes HTML characters only if escapeHTML.
+func parsePayloadTemplate(text string, escapeHTML bool) (*template.Template, error) {
+	if text == "" {
+		return nil, nil
+	}
+	tmpl, err := template.New("payload").Funcs(templateFuncs(escapeHTML)).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payload template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// render returns the payload of a JSON encoded entry, its CEF event
+// with FormatCEF, otherwise the entry itself unless a PayloadTemplate
+// is configured, with the AuthToken injected if sent in the payload.
+// The rendered payload must be valid JSON, it is compacted to a single
+// line so that it can be batched.
+func (h *Target) render(logJSON []byte) ([]byte, error) {
+	if h.cef != nil {
+		return h.cef.Format(logJSON)
+	}
+	if h.payloadTmpl == nil {
+		return h.injectAuthToken(logJSON)
+	}
+	dec := json.NewDecoder(bytes.NewReader(logJSON))
+	dec.UseNumber()
+	data := templateData{
+		DeploymentID: xhttp.GlobalDeploymentID,
+		Version:      xhttp.GlobalMinIOVersion,
+	}
+	if err := dec.Decode(&data.Entry); err != nil {
+		return nil, err
+	}
+	var out bytes.Buffer
+	if err := h.payloadTmpl.Execute(&out, data); err != nil {
+		return nil, fmt.Errorf("unable to render payload template: %w", err)
+	}
+	var payload bytes.Buffer
+	if err := json.Compact(&payload, out.Bytes()); err != nil {
+		return nil, fmt.Errorf("payload template produced invalid JSON: %w", err)
+	}
+	return h.injectAuthToken(payload.Bytes())
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package http
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	xhttp "github.com/minio/minio/internal/http"
+)
+
+func TestRenderPayload(t *testing.T) {
+	defer func(id, version string) {
+		xhttp.GlobalDeploymentID, xhttp.GlobalMinIOVersion = id, version
+	}(xhttp.GlobalDeploymentID, xhttp.GlobalMinIOVersion)
+	xhttp.GlobalDeploymentID, xhttp.GlobalMinIOVersion = "deployment", "version"
+
+	testCases := []struct {
+		template  string
+		entry     string
+		expected  string
+		shouldErr bool
+	}{
+		// No template, the entry is sent as is.
+		{"", `{"a":1}`, `{"a":1}`, false},
+		{
+			`{"event": {{json .Entry}}, "sourcetype": "minio", "host": {{json .DeploymentID}}}`,
+			`{"a":12345678901234567890,"b":"x"}`,
+			`{"event":{"a":12345678901234567890,"b":"x"},"sourcetype":"minio","host":"deployment"}`,
+			false,
+		},
+		{
+			"{\n  \"api\": {{json .Entry.api.name}},\n  \"version\": \"{{.Version}}\",\n  \"missing\": {{json .Entry.missing}}\n}",
+			`{"api":{"name":"PutObject"}}`,
+			`{"api":"PutObject","version":"version","missing":null}`,
+			false,
+		},
+		// Output is not valid JSON.
+		{`{"event": {{.Entry.b}}}`, `{"b":"x"}`, "", true},
+	}
+	for i, testCase := range testCases {
+		tmpl, err := parsePayloadTemplate(testCase.template, true)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		h := &Target{payloadTmpl: tmpl}
+		got, err := h.render([]byte(testCase.entry))
+		if testCase.shouldErr {
+			if err == nil {
+				t.Errorf("Test %d: expected an error, got %s", i+1, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if string(got) != testCase.expected {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.expected, got)
+		}
+	}
+
+	if _, err := parsePayloadTemplate(`{{json .Entry`, true); err == nil {
+		t.Fatal("expected an invalid template to be rejected")
+	}
+}
+
+func TestTargetPayloadTemplate(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		bodies []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		mu.Lock()
+		bodies = append(bodies, string(body))
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	tgt := New(Config{
+		Endpoint:        srv.URL,
+		QueueSize:       10,
+		Transport:       http.DefaultTransport,
+		PayloadTemplate: `{"event": {{json .Entry}}}`,
+		LogOnce:         func(_ context.Context, err error, _ interface{}, _ ...interface{}) { t.Error(err) },
+	})
+	if err := tgt.Init(); err != nil {
+		t.Fatal(err)
+	}
+	if err := tgt.Send(map[string]string{"a": "b"}, ""); err != nil {
+		t.Fatal(err)
+	}
+	tgt.Cancel()
+
+	mu.Lock()
+	defer mu.Unlock()
+	// The first body is the probe sent by Init.
+	expected := `{"event":{"a":"b"}}`
+	if len(bodies) != 2 || bodies[1] != expected {
+		t.Fatalf("expected payload %s, got %v", expected, bodies)
+	}
+
+	tgt = New(Config{
+		Endpoint:        srv.URL,
+		Transport:       http.DefaultTransport,
+		PayloadTemplate: `{{if}}`,
+	})
+	if err := tgt.Init(); err == nil {
+		t.Fatal("expected an invalid payload template to fail Init")
+	}
+}
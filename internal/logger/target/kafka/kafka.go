@@ -24,19 +24,44 @@ import (
 	"encoding/json"
 	"errors"
 	"net"
+	nethttp "net/http"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/Shopify/sarama"
 	saramatls "github.com/Shopify/sarama/tools/tls"
 
+	"github.com/minio/minio/internal/config"
 	"github.com/minio/minio/internal/logger/message/audit"
+	"github.com/minio/minio/internal/logger/target/http"
+	"github.com/minio/minio/internal/logger/target/queued"
+	"github.com/minio/minio/internal/logger/target/throttle"
 	"github.com/minio/minio/internal/logger/target/types"
 	xnet "github.com/minio/pkg/net"
 )
 
+// Default number of consecutive failures switching to the fallback
+const defaultFallbackThreshold = 3
+
+// Interval after which Kafka is tried again while on the fallback
+var fallbackRetryInterval = 30 * time.Second
+
+// Time allowed to resolve the brokers on Init
+const resolveBrokersTimeout = 5 * time.Second
+
+// Sinks reported by Stats
+const (
+	sinkKafka    = "kafka"
+	sinkFallback = "fallback"
+)
+
 // Target - Kafka target.
 type Target struct {
+	// These fields must be first, they are accessed atomically
+	totalMessages  int64
+	failedMessages int64
+
 	status int32
 	wg     sync.WaitGroup
 
@@ -46,16 +71,32 @@ type Target struct {
 	producer sarama.SyncProducer
 	kconfig  Config
 	config   *sarama.Config
+
+	// Webhook entries are sent to while Kafka is unavailable,
+	// onFallback is accessed atomically, the other fields are
+	// only used by the logger routine.
+	fallback      *http.Target
+	onFallback    int32
+	failures      int
+	fallbackSince time.Time
+
+	// Why the target is not online, nil once it is.
+	offlineMu  sync.Mutex
+	offlineErr error
 }
 
 // Send log message 'e' to kafka target.
 func (h *Target) Send(entry interface{}, errKind string) error {
+	if err := queued.Acquire(context.Background()); err != nil {
+		return err
+	}
 	select {
 	case h.logCh <- entry:
 	default:
+		queued.Release(1)
 		// log channel is full, do not wait and return
 		// an error immediately to the caller
-		return errors.New("log buffer full")
+		return types.ErrLogBufferFull
 	}
 
 	return nil
@@ -69,6 +110,13 @@ func (h *Target) logEntry(entry interface{}) {
 
 	ae, ok := entry.(audit.Entry)
 	if ok {
+		throttle.Wait(context.Background(), 1)
+		atomic.AddInt64(&h.totalMessages, 1)
+		if atomic.LoadInt32(&h.onFallback) == 1 && time.Since(h.fallbackSince) < fallbackRetryInterval {
+			h.sendFallback(entry)
+			return
+		}
+
 		msg := sarama.ProducerMessage{
 			Topic: h.kconfig.Topic,
 			Key:   sarama.StringEncoder(ae.RequestID),
@@ -78,18 +126,41 @@ func (h *Target) logEntry(entry interface{}) {
 		_, _, err = h.producer.SendMessage(&msg)
 		if err != nil {
 			h.kconfig.LogOnce(context.Background(), err, h.kconfig.Topic)
+			if h.fallback == nil {
+				atomic.AddInt64(&h.failedMessages, 1)
+				return
+			}
+			// The entry was not acknowledged by Kafka, it is
+			// handed to the fallback only.
+			h.failures++
+			if h.failures >= h.kconfig.FallbackThreshold {
+				atomic.StoreInt32(&h.onFallback, 1)
+				h.fallbackSince = time.Now()
+			}
+			h.sendFallback(entry)
 			return
 		}
+		h.failures = 0
+		atomic.StoreInt32(&h.onFallback, 0)
+	}
+}
+
+// sendFallback queues the entry to the fallback webhook.
+func (h *Target) sendFallback(entry interface{}) {
+	if err := h.fallback.Send(entry, ""); err != nil {
+		atomic.AddInt64(&h.failedMessages, 1)
+		h.kconfig.LogOnce(context.Background(), err, h.kconfig.FallbackEndpoint)
 	}
 }
 
 func (h *Target) startKakfaLogger() {
 	// Create a routine which sends json logs received
 	// from an internal channel.
+	h.wg.Add(1)
 	go func() {
-		h.wg.Add(1)
 		defer h.wg.Done()
 		for entry := range h.logCh {
+			queued.Release(1)
 			h.logEntry(entry)
 		}
 	}()
@@ -108,18 +179,59 @@ type Config struct {
 		ClientAuth    tls.ClientAuthType `json:"clientAuth"`
 		ClientTLSCert string             `json:"clientTLSCert"`
 		ClientTLSKey  string             `json:"clientTLSKey"`
+
+		// CA is the path to the CA certificates, or their inline
+		// PEM data, loaded in RootCAs to verify the brokers.
+		CA string `json:"ca,omitempty"`
 	} `json:"tls"`
-	SASL struct {
-		Enable    bool   `json:"enable"`
-		User      string `json:"username"`
-		Password  string `json:"password"`
-		Mechanism string `json:"mechanism"`
-	} `json:"sasl"`
+	SASL SASLConfig `json:"sasl"`
+
+	// FallbackEndpoint when set, is a webhook receiving the entries
+	// Kafka failed to acknowledge. After FallbackThreshold consecutive
+	// failures, 3 by default, entries are sent straight to it and Kafka
+	// is only tried again every 30s. Each entry is sent to one sink.
+	FallbackEndpoint  string `json:"fallbackEndpoint"`
+	FallbackThreshold int    `json:"fallbackThreshold"`
+
+	// ResolveBrokers when set, resolves the Brokers again on Init,
+	// e.g. from their SRV records.
+	ResolveBrokers func(ctx context.Context) ([]xnet.Host, error) `json:"-"`
 
 	// Custom logger
 	LogOnce func(ctx context.Context, err error, id interface{}, errKind ...interface{}) `json:"-"`
 }
 
+// SASLConfig - kafka SASL authentication arguments.
+type SASLConfig struct {
+	Enable    bool   `json:"enable"`
+	User      string `json:"username"`
+	Password  string `json:"password"`
+	Mechanism string `json:"mechanism"`
+
+	// Kerberos settings used by the GSSAPI mechanism.
+	KerberosServiceName string `json:"kerberosServiceName"`
+	KerberosRealm       string `json:"kerberosRealm"`
+	KerberosKeytab      string `json:"kerberosKeytab"`
+	KerberosConfig      string `json:"kerberosConfig"`
+
+	// OAuth client credentials used by the OAUTHBEARER mechanism.
+	OAuthTokenURL     string   `json:"oauthTokenURL"`
+	OAuthClientID     string   `json:"oauthClientID"`
+	OAuthClientSecret string   `json:"oauthClientSecret"`
+	OAuthScopes       []string `json:"oauthScopes"`
+}
+
+// Redacted returns a copy of the config with its secrets redacted.
+func (k Config) Redacted() Config {
+	if k.SASL.Password != "" {
+		k.SASL.Password = "*REDACTED*"
+	}
+	if k.SASL.OAuthClientSecret != "" {
+		k.SASL.OAuthClientSecret = "*REDACTED*"
+	}
+	return k
+}
+
 // Check if atleast one broker in cluster is active
 func (k Config) pingBrokers() error {
 	var err error
@@ -147,9 +259,26 @@ func (h *Target) String() string {
 
 // Init initialize kafka target
 func (h *Target) Init() error {
+	err := h.init()
+	h.offlineMu.Lock()
+	h.offlineErr = err
+	h.offlineMu.Unlock()
+	return err
+}
+
+func (h *Target) init() error {
 	if !h.kconfig.Enabled {
 		return nil
 	}
+	if h.kconfig.ResolveBrokers != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), resolveBrokersTimeout)
+		brokers, err := h.kconfig.ResolveBrokers(ctx)
+		cancel()
+		if err != nil {
+			return err
+		}
+		h.kconfig.Brokers = brokers
+	}
 	if len(h.kconfig.Brokers) == 0 {
 		return errors.New("no broker address found")
 	}
@@ -173,12 +302,36 @@ func (h *Target) Init() error {
 
 	sconfig.Net.SASL.User = h.kconfig.SASL.User
 	sconfig.Net.SASL.Password = h.kconfig.SASL.Password
-	initScramClient(h.kconfig, sconfig) // initializes configured scram client.
+	switch h.kconfig.SASL.Mechanism {
+	case SASLMechanismGSSAPI:
+		if err := initGSSAPI(h.kconfig, sconfig); err != nil {
+			return err
+		}
+	case SASLMechanismOAuthBearer:
+		if err := initOAuth(h.kconfig, sconfig); err != nil {
+			return err
+		}
+	default:
+		initScramClient(h.kconfig, sconfig) // initializes configured scram client.
+	}
 	sconfig.Net.SASL.Enable = h.kconfig.SASL.Enable
 
-	tlsConfig, err := saramatls.NewConfig(h.kconfig.TLS.ClientTLSCert, h.kconfig.TLS.ClientTLSKey)
-	if err != nil {
-		return err
+	var tlsConfig *tls.Config
+	if config.IsPEM(h.kconfig.TLS.ClientTLSCert) || config.IsPEM(h.kconfig.TLS.ClientTLSKey) {
+		// Inline PEM data instead of paths.
+		cert, err := config.LoadClientKeyPair(h.kconfig.TLS.ClientTLSCert, h.kconfig.TLS.ClientTLSKey)
+		if err != nil {
+			return err
+		}
+		tlsConfig = &tls.Config{
+			MinVersion:   tls.VersionTLS12,
+			Certificates: []tls.Certificate{cert},
+		}
+	} else {
+		var err error
+		if tlsConfig, err = saramatls.NewConfig(h.kconfig.TLS.ClientTLSCert, h.kconfig.TLS.ClientTLSKey); err != nil {
+			return err
+		}
 	}
 
 	sconfig.Net.TLS.Enable = h.kconfig.TLS.Enable
@@ -205,17 +358,118 @@ func (h *Target) Init() error {
 
 	h.producer = producer
 
+	if err = h.initFallback(); err != nil {
+		producer.Close()
+		return err
+	}
+
 	h.status = 1
-	go h.startKakfaLogger()
+	h.startKakfaLogger()
+	return nil
+}
+
+// initFallback initializes the fallback webhook, if any. It is not
+// probed, an unreachable fallback must not keep Kafka from starting.
+func (h *Target) initFallback() error {
+	if h.kconfig.FallbackEndpoint == "" {
+		return nil
+	}
+	if h.kconfig.FallbackThreshold <= 0 {
+		h.kconfig.FallbackThreshold = defaultFallbackThreshold
+	}
+	fallback := http.New(http.Config{
+		Enabled:   true,
+		Name:      "kafka-fallback",
+		Endpoint:  h.kconfig.FallbackEndpoint,
+		QueueSize: cap(h.logCh),
+		LogOnce:   h.kconfig.LogOnce,
+		Transport: h.fallbackTransport(),
+
+		DisableProbe: true,
+	})
+	if err := fallback.Init(); err != nil {
+		return err
+	}
+	h.fallback = fallback
 	return nil
 }
 
+// fallbackTransport returns the transport of the fallback webhook,
+// verifying its certificate like the ones of the brokers.
+func (h *Target) fallbackTransport() nethttp.RoundTripper {
+	tr := nethttp.DefaultTransport.(*nethttp.Transport).Clone()
+	tr.TLSClientConfig = &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		RootCAs:            h.kconfig.TLS.RootCAs,
+		InsecureSkipVerify: h.kconfig.TLS.SkipVerify,
+	}
+	return tr
+}
+
 // Cancel - cancels the target
 func (h *Target) Cancel() {
 	if atomic.CompareAndSwapInt32(&h.status, 1, 0) {
 		close(h.logCh)
+
+		h.offlineMu.Lock()
+		h.offlineErr = types.ErrTargetCanceled
+		h.offlineMu.Unlock()
 	}
 	h.wg.Wait()
+	if h.fallback != nil {
+		h.fallback.Cancel()
+	}
+}
+
+// IsOnline returns true if the target is initialized and not canceled.
+func (h *Target) IsOnline() bool {
+	return atomic.LoadInt32(&h.status) == 1
+}
+
+// OnlineStatus returns whether the target is online and if
+// not, why: the error of its initialization or the fact it
+// was not initialized yet or was canceled.
+func (h *Target) OnlineStatus() (bool, error) {
+	if h.IsOnline() {
+		return true, nil
+	}
+	h.offlineMu.Lock()
+	defer h.offlineMu.Unlock()
+	if h.offlineErr == nil {
+		return false, types.ErrTargetNotInitialized
+	}
+	return false, h.offlineErr
+}
+
+// Ready returns true if the target is online and its queue is
+// able to accept more entries, within the cap of all the queues.
+func (h *Target) Ready() bool {
+	return h.IsOnline() && len(h.logCh) < cap(h.logCh) && !queued.Full()
+}
+
+// Stats returns the delivery statistics of the target, along
+// with the sink entries are currently sent to.
+func (h *Target) Stats() types.TargetStats {
+	sink := sinkKafka
+	if atomic.LoadInt32(&h.onFallback) == 1 {
+		sink = sinkFallback
+	}
+	stats := types.TargetStats{
+		Enabled:        true,
+		TotalMessages:  atomic.LoadInt64(&h.totalMessages),
+		FailedMessages: atomic.LoadInt64(&h.failedMessages),
+		QueueLength:    len(h.logCh),
+		ActiveSink:     sink,
+	}
+	if _, err := h.OnlineStatus(); err != nil {
+		stats.OfflineReason = err.Error()
+	}
+	return stats
+}
+
+// Config returns the target config with its secrets redacted.
+func (h *Target) Config() Config {
+	return h.kconfig.Redacted()
 }
 
 // New initializes a new logger target which
@@ -0,0 +1,86 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package kafka
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Shopify/sarama"
+	krbconfig "github.com/jcmturner/gokrb5/v8/config"
+	"github.com/jcmturner/gokrb5/v8/keytab"
+)
+
+// SASLMechanismGSSAPI - SASL mechanism for Kerberos authentication.
+const SASLMechanismGSSAPI = "gssapi"
+
+// ValidateKerberos - validates the Kerberos settings required by the GSSAPI mechanism.
+func (s SASLConfig) ValidateKerberos() error {
+	if s.KerberosServiceName == "" {
+		return errors.New("'sasl_kerberos_service_name' cannot be empty for GSSAPI")
+	}
+	if s.KerberosRealm == "" {
+		return errors.New("'sasl_kerberos_realm' cannot be empty for GSSAPI")
+	}
+	if s.KerberosConfig == "" {
+		return errors.New("'sasl_kerberos_config' cannot be empty for GSSAPI")
+	}
+	if s.User == "" {
+		return errors.New("'sasl_username' cannot be empty for GSSAPI, it is the Kerberos principal")
+	}
+	if s.KerberosKeytab == "" && s.Password == "" {
+		return errors.New("either 'sasl_kerberos_keytab' or 'sasl_password' is required for GSSAPI")
+	}
+	return nil
+}
+
+// initGSSAPI - loads and validates the Kerberos configuration and keytab,
+// such that a misconfiguration fails at Init instead of on first use.
+func initGSSAPI(cfg Config, config *sarama.Config) error {
+	if err := cfg.SASL.ValidateKerberos(); err != nil {
+		return err
+	}
+	if _, err := krbconfig.Load(cfg.SASL.KerberosConfig); err != nil {
+		return fmt.Errorf("unable to load kerberos config %s: %w", cfg.SASL.KerberosConfig, err)
+	}
+
+	gssapi := sarama.GSSAPIConfig{
+		KerberosConfigPath: cfg.SASL.KerberosConfig,
+		ServiceName:        cfg.SASL.KerberosServiceName,
+		Realm:              cfg.SASL.KerberosRealm,
+		Username:           cfg.SASL.User,
+	}
+	if cfg.SASL.KerberosKeytab != "" {
+		kt, err := keytab.Load(cfg.SASL.KerberosKeytab)
+		if err != nil {
+			return fmt.Errorf("unable to load kerberos keytab %s: %w", cfg.SASL.KerberosKeytab, err)
+		}
+		if len(kt.Entries) == 0 {
+			return fmt.Errorf("kerberos keytab %s has no entries", cfg.SASL.KerberosKeytab)
+		}
+		gssapi.AuthType = sarama.KRB5_KEYTAB_AUTH
+		gssapi.KeyTabPath = cfg.SASL.KerberosKeytab
+	} else {
+		gssapi.AuthType = sarama.KRB5_USER_AUTH
+		gssapi.Password = cfg.SASL.Password
+	}
+
+	config.Net.SASL.Mechanism = sarama.SASLTypeGSSAPI
+	config.Net.SASL.GSSAPI = gssapi
+	return nil
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// SASLMechanismOAuthBearer - SASL mechanism for OAuth 2.0 authentication,
+// with tokens obtained with the client credentials grant.
+const SASLMechanismOAuthBearer = "oauthbearer"
+
+// ErrOAuthToken is returned when no OAuth token could be obtained
+// from the token endpoint for SASL/OAUTHBEARER authentication.
+var ErrOAuthToken = errors.New("unable to obtain an OAuth token")
+
+const (
+	// Tokens are refreshed this long before they expire.
+	oauthRefreshBefore = time.Minute
+
+	// Time allowed for each request to the token endpoint.
+	oauthFetchTimeout = 10 * time.Second
+
+	// Attempts to fetch a token, the delay between them doubling.
+	oauthFetchAttempts = 3
+)
+
+// ValidateOAuth - validates the settings required by the OAUTHBEARER mechanism.
+func (s SASLConfig) ValidateOAuth() error {
+	if s.OAuthTokenURL == "" {
+		return errors.New("'sasl_oauth_token_url' cannot be empty for OAUTHBEARER")
+	}
+	if s.OAuthClientID == "" {
+		return errors.New("'sasl_oauth_client_id' cannot be empty for OAUTHBEARER")
+	}
+	if s.OAuthClientSecret == "" {
+		return errors.New("'sasl_oauth_client_secret' cannot be empty for OAUTHBEARER")
+	}
+	return nil
+}
+
+// initOAuth - sets up SASL/OAUTHBEARER authentication, a first token is
+// fetched such that a misconfiguration fails at Init instead of on first use.
+func initOAuth(cfg Config, config *sarama.Config) error {
+	if err := cfg.SASL.ValidateOAuth(); err != nil {
+		return err
+	}
+	provider := newOAuthTokenProvider(cfg.SASL)
+	if _, err := provider.Token(); err != nil {
+		return err
+	}
+	config.Net.SASL.Mechanism = sarama.SASLMechanism(sarama.SASLTypeOAuth)
+	config.Net.SASL.TokenProvider = provider
+	return nil
+}
+
+// oauthTokenProvider - provides the tokens of the client credentials
+// grant, a token is reused until it is about to expire.
+type oauthTokenProvider struct {
+	config  clientcredentials.Config
+	backoff time.Duration
+
+	mu    sync.Mutex
+	token *oauth2.Token
+}
+
+func newOAuthTokenProvider(s SASLConfig) *oauthTokenProvider {
+	return &oauthTokenProvider{
+		config: clientcredentials.Config{
+			ClientID:     s.OAuthClientID,
+			ClientSecret: s.OAuthClientSecret,
+			TokenURL:     s.OAuthTokenURL,
+			Scopes:       s.OAuthScopes,
+		},
+		backoff: time.Second,
+	}
+}
+
+// Token - returns the current token, a new one is fetched once it is
+// about to expire. The current token is returned as long as it is valid
+// if fetching a new one fails.
+func (p *oauthTokenProvider) Token() (*sarama.AccessToken, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.token != nil && (p.token.Expiry.IsZero() || time.Until(p.token.Expiry) > oauthRefreshBefore) {
+		return &sarama.AccessToken{Token: p.token.AccessToken}, nil
+	}
+
+	token, err := p.fetch()
+	if err != nil {
+		if p.token != nil && p.token.Valid() {
+			return &sarama.AccessToken{Token: p.token.AccessToken}, nil
+		}
+		return nil, err
+	}
+	p.token = token
+	return &sarama.AccessToken{Token: token.AccessToken}, nil
+}
+
+// fetch - requests a token from the token endpoint, retrying with backoff.
+func (p *oauthTokenProvider) fetch() (*oauth2.Token, error) {
+	var err error
+	backoff := p.backoff
+	for attempt := 1; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), oauthFetchTimeout)
+		var token *oauth2.Token
+		token, err = p.config.Token(ctx)
+		cancel()
+		if err == nil {
+			return token, nil
+		}
+		if attempt == oauthFetchAttempts {
+			break
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+	return nil, fmt.Errorf("%w from %s: %v", ErrOAuthToken, p.config.TokenURL, err)
+}
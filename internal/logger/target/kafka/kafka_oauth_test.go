@@ -0,0 +1,114 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package kafka
+
+import (
+	"errors"
+	"fmt"
+	nethttp "net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestOAuthTokenProvider(t *testing.T) {
+	var (
+		requests  int32
+		failing   int32
+		expiresIn int32 = 3600
+	)
+	srv := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		n := atomic.AddInt32(&requests, 1)
+		if atomic.LoadInt32(&failing) == 1 {
+			w.WriteHeader(nethttp.StatusServiceUnavailable)
+			return
+		}
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("scope") != "kafka.produce kafka.read" {
+			w.WriteHeader(nethttp.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":%d}`, n, atomic.LoadInt32(&expiresIn))
+	}))
+	defer srv.Close()
+
+	p := newOAuthTokenProvider(SASLConfig{
+		OAuthTokenURL:     srv.URL,
+		OAuthClientID:     "minio",
+		OAuthClientSecret: "secret",
+		OAuthScopes:       []string{"kafka.produce", "kafka.read"},
+	})
+	p.backoff = time.Millisecond
+
+	token, err := p.Token()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.Token != "token-1" {
+		t.Fatalf("expected token-1, got %s", token.Token)
+	}
+	// Reused until it is about to expire.
+	if token, err = p.Token(); err != nil || token.Token != "token-1" {
+		t.Fatalf("expected token-1 to be reused, got %v, %v", token, err)
+	}
+
+	// Refreshed ahead of its expiry.
+	p.token.Expiry = time.Now().Add(oauthRefreshBefore / 2)
+	if token, err = p.Token(); err != nil || token.Token != "token-2" {
+		t.Fatalf("expected token-2, got %v, %v", token, err)
+	}
+
+	// The still valid token is used while the endpoint fails.
+	atomic.StoreInt32(&failing, 1)
+	p.token.Expiry = time.Now().Add(oauthRefreshBefore / 2)
+	if token, err = p.Token(); err != nil || token.Token != "token-2" {
+		t.Fatalf("expected token-2 to be kept, got %v, %v", token, err)
+	}
+
+	atomic.StoreInt32(&requests, 0)
+	p.token.Expiry = time.Now().Add(-time.Second)
+	if _, err = p.Token(); !errors.Is(err, ErrOAuthToken) {
+		t.Fatalf("expected %v, got %v", ErrOAuthToken, err)
+	}
+	if n := atomic.LoadInt32(&requests); n != oauthFetchAttempts {
+		t.Fatalf("expected %d attempts, got %d", oauthFetchAttempts, n)
+	}
+}
+
+func TestSASLConfigValidateOAuth(t *testing.T) {
+	valid := SASLConfig{
+		OAuthTokenURL:     "https://sts.example.com/token",
+		OAuthClientID:     "minio",
+		OAuthClientSecret: "secret",
+	}
+	if err := valid.ValidateOAuth(); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []func(*SASLConfig){
+		func(s *SASLConfig) { s.OAuthTokenURL = "" },
+		func(s *SASLConfig) { s.OAuthClientID = "" },
+		func(s *SASLConfig) { s.OAuthClientSecret = "" },
+	} {
+		s := valid
+		c(&s)
+		if err := s.ValidateOAuth(); err == nil {
+			t.Fatalf("expected %+v to be rejected", s)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package kafka
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	nethttp "net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+
+	"github.com/minio/minio/internal/logger/message/audit"
+	xnet "github.com/minio/pkg/net"
+)
+
+// fakeProducer fails to produce messages while failing is set.
+type fakeProducer struct {
+	failing  int32
+	produced int32
+}
+
+func (p *fakeProducer) SendMessage(*sarama.ProducerMessage) (int32, int64, error) {
+	if atomic.LoadInt32(&p.failing) == 1 {
+		return 0, 0, errors.New("kafka: cluster unavailable")
+	}
+	atomic.AddInt32(&p.produced, 1)
+	return 0, 0, nil
+}
+
+func (p *fakeProducer) SendMessages([]*sarama.ProducerMessage) error { return nil }
+
+func (p *fakeProducer) Close() error { return nil }
+
+func TestTargetFallback(t *testing.T) {
+	var fallbackReceived int32
+	srv := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		atomic.AddInt32(&fallbackReceived, 1)
+		w.WriteHeader(nethttp.StatusOK)
+	}))
+	defer srv.Close()
+
+	defer func(interval time.Duration) { fallbackRetryInterval = interval }(fallbackRetryInterval)
+	fallbackRetryInterval = time.Hour
+
+	producer := &fakeProducer{failing: 1}
+	h := New(Config{
+		Enabled:           true,
+		FallbackEndpoint:  srv.URL,
+		FallbackThreshold: 2,
+		LogOnce:           func(_ context.Context, _ error, _ interface{}, _ ...interface{}) {},
+	})
+	h.producer = producer
+	if err := h.initFallback(); err != nil {
+		t.Fatal(err)
+	}
+	h.status = 1
+
+	// Below the threshold entries still go to Kafka first.
+	h.logEntry(audit.Entry{RequestID: "1"})
+	if sink := h.Stats().ActiveSink; sink != sinkKafka {
+		t.Fatalf("expected kafka sink after one failure, got %s", sink)
+	}
+	h.logEntry(audit.Entry{RequestID: "2"})
+	if sink := h.Stats().ActiveSink; sink != sinkFallback {
+		t.Fatalf("expected fallback sink after two failures, got %s", sink)
+	}
+	// Kafka is not tried again until the retry interval elapsed.
+	atomic.StoreInt32(&producer.failing, 0)
+	h.logEntry(audit.Entry{RequestID: "3"})
+	if got := atomic.LoadInt32(&producer.produced); got != 0 {
+		t.Fatalf("expected kafka to be skipped while on the fallback, got %d produced", got)
+	}
+
+	fallbackRetryInterval = 0
+	h.logEntry(audit.Entry{RequestID: "4"})
+	if sink := h.Stats().ActiveSink; sink != sinkKafka {
+		t.Fatalf("expected kafka sink once it recovered, got %s", sink)
+	}
+	h.Cancel()
+
+	if got := atomic.LoadInt32(&fallbackReceived); got != 3 {
+		t.Fatalf("expected 3 entries sent to the fallback, got %d", got)
+	}
+	if got := atomic.LoadInt32(&producer.produced); got != 1 {
+		t.Fatalf("expected 1 entry produced to kafka, got %d", got)
+	}
+	if stats := h.Stats(); stats.TotalMessages != 4 || stats.FailedMessages != 0 {
+		t.Fatalf("unexpected stats %#v", stats)
+	}
+}
+
+func TestTargetFallbackTLS(t *testing.T) {
+	var fallbackReceived int32
+	srv := httptest.NewTLSServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		atomic.AddInt32(&fallbackReceived, 1)
+		w.WriteHeader(nethttp.StatusOK)
+	}))
+	defer srv.Close()
+
+	// An unreachable fallback does not fail Init.
+	h := New(Config{
+		Enabled:          true,
+		FallbackEndpoint: "http://127.0.0.1:1",
+		LogOnce:          func(_ context.Context, _ error, _ interface{}, _ ...interface{}) {},
+	})
+	if err := h.initFallback(); err != nil {
+		t.Fatalf("expected an unreachable fallback to be accepted, got %v", err)
+	}
+	h.fallback.Cancel()
+
+	// The fallback is verified with the CAs of the brokers.
+	cfg := Config{
+		Enabled:           true,
+		FallbackEndpoint:  srv.URL,
+		FallbackThreshold: 1,
+		LogOnce:           func(_ context.Context, _ error, _ interface{}, _ ...interface{}) {},
+	}
+	cfg.TLS.RootCAs = x509.NewCertPool()
+	cfg.TLS.RootCAs.AddCert(srv.Certificate())
+	h = New(cfg)
+	h.producer = &fakeProducer{failing: 1}
+	if err := h.initFallback(); err != nil {
+		t.Fatal(err)
+	}
+	h.status = 1
+	h.logEntry(audit.Entry{RequestID: "1"})
+	h.Cancel()
+
+	if got := atomic.LoadInt32(&fallbackReceived); got != 1 {
+		t.Fatalf("expected 1 entry sent to the TLS fallback, got %d", got)
+	}
+	if stats := h.Stats(); stats.FailedMessages != 0 {
+		t.Fatalf("unexpected stats %#v", stats)
+	}
+}
+
+func TestTargetInitResolveBrokers(t *testing.T) {
+	errResolve := errors.New("no such host")
+	h := New(Config{
+		Enabled: true,
+		ResolveBrokers: func(ctx context.Context) ([]xnet.Host, error) {
+			if _, ok := ctx.Deadline(); !ok {
+				t.Error("expected the resolution to be bounded")
+			}
+			return nil, errResolve
+		},
+	})
+	if err := h.Init(); err != errResolve {
+		t.Fatalf("expected %v, got %v", errResolve, err)
+	}
+}
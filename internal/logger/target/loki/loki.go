@@ -0,0 +1,426 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package loki
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	xhttp "github.com/minio/minio/internal/http"
+	"github.com/minio/minio/internal/logger/target/queued"
+	"github.com/minio/minio/internal/logger/target/throttle"
+	"github.com/minio/minio/internal/logger/target/types"
+)
+
+// Timeout of a push to Loki
+const pushTimeout = 10 * time.Second
+
+// pushPath is the path of the push API, used when
+// the configured endpoint has none.
+const pushPath = "/loki/api/v1/push"
+
+// Batch defaults, see Config.BatchSize and Config.BatchInterval
+const (
+	defaultBatchSize     = 1000
+	defaultBatchInterval = time.Second
+)
+
+// Maximum length of the response body reported
+// in the error of a rejected push.
+const maxErrorBodyExcerpt = 256
+
+// defaultLabels are the stream labels without any configured.
+var defaultLabels = map[string]string{"job": "minio"}
+
+// Valid Loki label names
+var labelNameRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// Config Loki logger target
+type Config struct {
+	Enabled  bool   `json:"enabled"`
+	Name     string `json:"name"`
+	Endpoint string `json:"endpoint"`
+
+	// Labels of the stream the entries are pushed to,
+	// defaults to job=minio.
+	Labels map[string]string `json:"labels"`
+
+	// Username and Password for basic authentication,
+	// or BearerToken, TenantID is sent as X-Scope-OrgID
+	// to multi-tenant Loki deployments.
+	Username    string `json:"username"`
+	Password    string `json:"password"`
+	BearerToken string `json:"bearerToken"`
+	TenantID    string `json:"tenantID"`
+
+	QueueSize int `json:"queueSize"`
+
+	// BatchSize and BatchInterval, entries are pushed in batches
+	// of up to BatchSize entries, flushed BatchInterval after their
+	// first entry at the latest. They default to 1000 and 1s.
+	BatchSize     int           `json:"batchSize"`
+	BatchInterval time.Duration `json:"batchInterval"`
+
+	UserAgent string            `json:"userAgent"`
+	Transport http.RoundTripper `json:"-"`
+
+	// Custom logger
+	LogOnce func(ctx context.Context, err error, id interface{}, errKind ...interface{}) `json:"-"`
+}
+
+// redacted is the placeholder of redacted secrets.
+const redacted = "*REDACTED*"
+
+// Redacted returns a copy of the config with its secrets redacted.
+func (c Config) Redacted() Config {
+	if c.Password != "" {
+		c.Password = redacted
+	}
+	if c.BearerToken != "" {
+		c.BearerToken = redacted
+	}
+	return c
+}
+
+// Validate checks the endpoint and the labels.
+func (c Config) Validate() error {
+	u, err := url.Parse(c.Endpoint)
+	if err != nil {
+		return fmt.Errorf("invalid loki endpoint: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid loki endpoint %s: expected an http(s) URL", c.Endpoint)
+	}
+	if c.BearerToken != "" && c.Username != "" {
+		return errors.New("loki basic and bearer authentication cannot be configured together")
+	}
+	for name := range c.Labels {
+		if !labelNameRegexp.MatchString(name) {
+			return fmt.Errorf("invalid loki label name %q", name)
+		}
+	}
+	return nil
+}
+
+// Target implements logger.Target and pushes entries in
+// batches to a single stream of the Loki push API.
+type Target struct {
+	// Accessed atomically, must stay 64-bit aligned.
+	totalMessages  int64
+	failedMessages int64
+
+	status int32
+	wg     sync.WaitGroup
+
+	// Channel of log entries
+	logCh chan interface{}
+
+	client   *http.Client
+	endpoint string
+	labels   map[string]string
+
+	// Outcome of the last pushes
+	statsMu      sync.Mutex
+	started      time.Time
+	lastSuccess  time.Time
+	lastError    time.Time
+	lastErrorMsg string
+
+	config Config
+}
+
+// New initializes a new Loki target, Init must be called
+// before any entry is sent.
+func New(config Config) *Target {
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultBatchSize
+	}
+	if config.BatchInterval <= 0 {
+		config.BatchInterval = defaultBatchInterval
+	}
+	return &Target{
+		logCh:  make(chan interface{}, config.QueueSize),
+		config: config,
+	}
+}
+
+// Endpoint returns the push endpoint
+func (h *Target) Endpoint() string {
+	return h.endpoint
+}
+
+// String returns the name of the target
+func (h *Target) String() string {
+	return h.config.Name
+}
+
+// Config returns the target config with its secrets redacted.
+func (h *Target) Config() Config {
+	return h.config.Redacted()
+}
+
+// Init validates the config and pushes an empty batch to
+// check that Loki is reachable and accepts the credentials.
+func (h *Target) Init() error {
+	if err := h.config.Validate(); err != nil {
+		return err
+	}
+	u, err := url.Parse(h.config.Endpoint)
+	if err != nil {
+		return err
+	}
+	if strings.Trim(u.Path, "/") == "" {
+		u.Path = pushPath
+	}
+	h.endpoint = u.String()
+	h.labels = h.config.Labels
+	if len(h.labels) == 0 {
+		h.labels = defaultLabels
+	}
+	h.client = &http.Client{Transport: h.config.Transport}
+
+	if err = h.push(pushRequest{Streams: []stream{}}); err != nil {
+		return err
+	}
+
+	h.statsMu.Lock()
+	h.started = time.Now()
+	h.statsMu.Unlock()
+	h.status = 1
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+		h.batchEntries()
+	}()
+	return nil
+}
+
+// Send queues the entry, an error is returned if the queue is full.
+func (h *Target) Send(entry interface{}, errKind string) error {
+	if atomic.LoadInt32(&h.status) == 0 {
+		// Channel was closed or used before init.
+		return nil
+	}
+
+	if err := queued.Acquire(context.Background()); err != nil {
+		return err
+	}
+	select {
+	case h.logCh <- entry:
+	default:
+		queued.Release(1)
+		// log channel is full, do not wait and return
+		// an error immediately to the caller
+		return types.ErrLogBufferFull
+	}
+	return nil
+}
+
+// pushRequest is the payload of the push API.
+type pushRequest struct {
+	Streams []stream `json:"streams"`
+}
+
+type stream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// value returns the [timestamp, line] value of an entry, timestamped
+// with its time field, if any, in nanoseconds since the epoch.
+func value(entry interface{}, now time.Time) ([2]string, error) {
+	logJSON, err := json.Marshal(&entry)
+	if err != nil {
+		return [2]string{}, err
+	}
+	var e struct {
+		Time time.Time `json:"time"`
+	}
+	if json.Unmarshal(logJSON, &e) == nil && !e.Time.IsZero() {
+		now = e.Time
+	}
+	return [2]string{strconv.FormatInt(now.UnixNano(), 10), string(logJSON)}, nil
+}
+
+// batchEntries pushes the queued entries in batches, flushed when
+// they reach BatchSize or BatchInterval after their first entry,
+// whichever comes first. The partial batch is flushed once the queue
+// is closed.
+func (h *Target) batchEntries() {
+	var values [][2]string
+	// Only runs while the batch holds entries.
+	timer := time.NewTimer(h.config.BatchInterval)
+	if !timer.Stop() {
+		<-timer.C
+	}
+	defer timer.Stop()
+
+	flush := func() {
+		if len(values) == 0 {
+			return
+		}
+		if !timer.Stop() {
+			// Drain the timer unless it already fired the flush.
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		// Loki expects the values of a stream in time order.
+		sort.SliceStable(values, func(i, j int) bool {
+			return len(values[i][0]) < len(values[j][0]) ||
+				len(values[i][0]) == len(values[j][0]) && values[i][0] < values[j][0]
+		})
+		throttle.Wait(context.Background(), len(values))
+		err := h.push(pushRequest{Streams: []stream{{Stream: h.labels, Values: values}}})
+		h.record(err, int64(len(values)))
+		values = nil
+	}
+
+	for {
+		select {
+		case entry, ok := <-h.logCh:
+			if !ok {
+				flush()
+				return
+			}
+			queued.Release(1)
+			v, err := value(entry, time.Now())
+			if err != nil {
+				continue
+			}
+			if len(values) == 0 {
+				timer.Reset(h.config.BatchInterval)
+			}
+			values = append(values, v)
+			if len(values) >= h.config.BatchSize {
+				flush()
+			}
+		case <-timer.C:
+			flush()
+		}
+	}
+}
+
+// record records the outcome of pushing count entries.
+func (h *Target) record(err error, count int64) {
+	h.statsMu.Lock()
+	if err != nil {
+		atomic.AddInt64(&h.failedMessages, count)
+		h.lastError = time.Now()
+		h.lastErrorMsg = err.Error()
+	} else {
+		h.lastSuccess = time.Now()
+	}
+	atomic.AddInt64(&h.totalMessages, count)
+	h.statsMu.Unlock()
+
+	if err != nil && h.config.LogOnce != nil {
+		h.config.LogOnce(context.Background(), err, h.endpoint)
+	}
+}
+
+// push sends a push request to Loki.
+func (h *Target) push(r pushRequest) error {
+	body, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pushTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set(xhttp.ContentType, "application/json")
+	req.Header.Set("User-Agent", h.config.UserAgent)
+	switch {
+	case h.config.BearerToken != "":
+		req.Header.Set("Authorization", "Bearer "+h.config.BearerToken)
+	case h.config.Username != "":
+		req.SetBasicAuth(h.config.Username, h.config.Password)
+	}
+	if h.config.TenantID != "" {
+		req.Header.Set("X-Scope-OrgID", h.config.TenantID)
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s returned '%w', please check your endpoint configuration", h.endpoint, err)
+	}
+	defer xhttp.DrainBody(resp.Body)
+	if resp.StatusCode/100 != 2 {
+		excerpt, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBodyExcerpt))
+		if excerpt = bytes.TrimSpace(excerpt); len(excerpt) > 0 {
+			return fmt.Errorf("%s returned '%s' with '%s', please check your endpoint configuration", h.endpoint, resp.Status, excerpt)
+		}
+		return fmt.Errorf("%s returned '%s', please check your endpoint configuration", h.endpoint, resp.Status)
+	}
+	return nil
+}
+
+// Cancel pushes the queued entries and stops the target.
+func (h *Target) Cancel() {
+	if atomic.CompareAndSwapInt32(&h.status, 1, 0) {
+		close(h.logCh)
+	}
+	h.wg.Wait()
+}
+
+// IsOnline returns true if the target is initialized and not canceled.
+func (h *Target) IsOnline() bool {
+	return atomic.LoadInt32(&h.status) == 1
+}
+
+// Stats returns the delivery statistics of the target.
+func (h *Target) Stats() types.TargetStats {
+	h.statsMu.Lock()
+	defer h.statsMu.Unlock()
+	return types.TargetStats{
+		Enabled:        true,
+		TotalMessages:  atomic.LoadInt64(&h.totalMessages),
+		FailedMessages: atomic.LoadInt64(&h.failedMessages),
+		QueueLength:    len(h.logCh),
+		LastSuccess:    h.lastSuccess,
+		LastError:      h.lastError,
+		LastErrorMsg:   h.lastErrorMsg,
+
+		SecondsSinceLastSuccess: types.SecondsSinceLastSuccess(h.lastSuccess, h.started, time.Now()),
+	}
+}
+
+// Type returns the type of the target
+func (h *Target) Type() types.TargetType {
+	return types.TargetLoki
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package loki
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConfigValidate(t *testing.T) {
+	testCases := []struct {
+		config    Config
+		shouldErr bool
+	}{
+		{Config{Endpoint: "http://loki:3100"}, false},
+		{Config{Endpoint: "https://loki:3100/loki/api/v1/push", Labels: map[string]string{"job": "minio", "env_1": "prod"}}, false},
+		{Config{Endpoint: "loki:3100"}, true},
+		{Config{Endpoint: "ftp://loki:3100"}, true},
+		{Config{Endpoint: "http://loki:3100", Labels: map[string]string{"1job": "minio"}}, true},
+		{Config{Endpoint: "http://loki:3100", Username: "user", BearerToken: "token"}, true},
+	}
+	for i, testCase := range testCases {
+		err := testCase.config.Validate()
+		if testCase.shouldErr != (err != nil) {
+			t.Errorf("Test %d: expected error %v, got %v", i+1, testCase.shouldErr, err)
+		}
+	}
+}
+
+func TestTargetPush(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		pushes []pushRequest
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if r.URL.Path != pushPath || !ok || user != "user" || pass != "pass" || r.Header.Get("X-Scope-OrgID") != "tenant1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var push pushRequest
+		if err := json.NewDecoder(r.Body).Decode(&push); err != nil {
+			t.Error(err)
+		}
+		mu.Lock()
+		pushes = append(pushes, push)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	tgt := New(Config{
+		Endpoint:      srv.URL,
+		Labels:        map[string]string{"job": "minio", "env": "test"},
+		Username:      "user",
+		Password:      "pass",
+		TenantID:      "tenant1",
+		QueueSize:     10,
+		BatchSize:     2,
+		BatchInterval: time.Hour,
+	})
+	if err := tgt.Init(); err != nil {
+		t.Fatal(err)
+	}
+	t1 := time.Date(2022, 1, 1, 0, 0, 2, 0, time.UTC)
+	t0 := t1.Add(-time.Second)
+	for _, entry := range []interface{}{
+		map[string]interface{}{"time": t1, "msg": "b"},
+		map[string]interface{}{"time": t0, "msg": "a"},
+		map[string]interface{}{"msg": "c"},
+	} {
+		if err := tgt.Send(entry, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The partial batch is pushed on cancel.
+	tgt.Cancel()
+
+	mu.Lock()
+	defer mu.Unlock()
+	// The first push is the empty one sent by Init.
+	if len(pushes) != 3 || len(pushes[0].Streams) != 0 {
+		t.Fatalf("expected an empty push followed by 2 batches, got %v", pushes)
+	}
+	batch := pushes[1].Streams[0]
+	if !reflect.DeepEqual(batch.Stream, map[string]string{"job": "minio", "env": "test"}) {
+		t.Fatalf("unexpected labels %v", batch.Stream)
+	}
+	// Values are ordered by their entry time.
+	expected := [][2]string{
+		{"1640995201000000000", `{"msg":"a","time":"2022-01-01T00:00:01Z"}`},
+		{"1640995202000000000", `{"msg":"b","time":"2022-01-01T00:00:02Z"}`},
+	}
+	if !reflect.DeepEqual(batch.Values, expected) {
+		t.Fatalf("expected values %v, got %v", expected, batch.Values)
+	}
+	if values := pushes[2].Streams[0].Values; len(values) != 1 || values[0][1] != `{"msg":"c"}` {
+		t.Fatalf("unexpected last batch %v", values)
+	}
+	if stats := tgt.Stats(); stats.TotalMessages != 3 || stats.FailedMessages != 0 {
+		t.Fatalf("expected 3 messages sent, got %+v", stats)
+	}
+
+	unauthorized := New(Config{Endpoint: srv.URL, TenantID: "tenant1"})
+	if err := unauthorized.Init(); err == nil {
+		t.Fatal("expected Init to fail without credentials")
+	}
+}
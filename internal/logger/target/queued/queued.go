@@ -0,0 +1,157 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package queued accounts for the entries queued by all the logger
+// targets, optionally capping their total to bound the memory used.
+package queued
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/minio/minio/internal/logger/target/types"
+)
+
+// Policies applied to entries sent while the cap is reached.
+const (
+	// PolicyDrop drops the entry, the default.
+	PolicyDrop = "drop"
+	// PolicyBlock waits for room to be freed, up to blockTimeout.
+	PolicyBlock = "block"
+)
+
+// blockTimeout bounds the wait of a sender with PolicyBlock, the
+// senders are e.g. the goroutines serving requests.
+const blockTimeout = time.Second
+
+var (
+	// count is the number of queued entries, limit their cap,
+	// 0 if unlimited.
+	count int64
+	limit int64
+	block int32
+
+	// waiters is the number of blocked senders, freed is closed
+	// and replaced whenever room is freed while there are some.
+	waiters int32
+	freedMu sync.Mutex
+	freed   = make(chan struct{})
+)
+
+// SetLimit caps the total number of entries queued by all the
+// targets to n, entries sent beyond are dropped or wait for room per
+// policy. A value of 0 or less removes the cap, which is the default.
+func SetLimit(n int, policy string) {
+	if n < 0 {
+		n = 0
+	}
+	if policy == PolicyBlock {
+		atomic.StoreInt32(&block, 1)
+	} else {
+		atomic.StoreInt32(&block, 0)
+	}
+	atomic.StoreInt64(&limit, int64(n))
+	// Blocked senders check again against the new cap.
+	wake()
+}
+
+// Limit returns the current cap, 0 if unlimited.
+func Limit() int {
+	return int(atomic.LoadInt64(&limit))
+}
+
+// Count returns the number of entries queued by all the targets.
+func Count() int64 {
+	return atomic.LoadInt64(&count)
+}
+
+// Full returns true if the cap is reached.
+func Full() bool {
+	max := atomic.LoadInt64(&limit)
+	return max > 0 && atomic.LoadInt64(&count) >= max
+}
+
+// Acquire accounts for an entry about to be queued. Once the cap is
+// reached it fails with types.ErrLogBufferFull, or with PolicyBlock
+// waits until room is freed, failing the same way if there is none
+// within blockTimeout, or until the context is done.
+func Acquire(ctx context.Context) error {
+	var timeout <-chan time.Time
+	for {
+		if tryAcquire() {
+			return nil
+		}
+		if atomic.LoadInt32(&block) == 0 {
+			return types.ErrLogBufferFull
+		}
+		freedMu.Lock()
+		ch := freed
+		freedMu.Unlock()
+		atomic.AddInt32(&waiters, 1)
+		// Room freed before waiters was raised is not signaled.
+		if tryAcquire() {
+			atomic.AddInt32(&waiters, -1)
+			return nil
+		}
+		if timeout == nil {
+			timer := time.NewTimer(blockTimeout)
+			defer timer.Stop()
+			timeout = timer.C
+		}
+		select {
+		case <-ch:
+			atomic.AddInt32(&waiters, -1)
+		case <-timeout:
+			atomic.AddInt32(&waiters, -1)
+			return types.ErrLogBufferFull
+		case <-ctx.Done():
+			atomic.AddInt32(&waiters, -1)
+			return ctx.Err()
+		}
+	}
+}
+
+// tryAcquire accounts for an entry if under the cap.
+func tryAcquire() bool {
+	for {
+		n := atomic.LoadInt64(&count)
+		if max := atomic.LoadInt64(&limit); max > 0 && n >= max {
+			return false
+		}
+		if atomic.CompareAndSwapInt64(&count, n, n+1) {
+			return true
+		}
+	}
+}
+
+// Release accounts for n entries dequeued or dropped.
+func Release(n int) {
+	atomic.AddInt64(&count, -int64(n))
+	if atomic.LoadInt32(&waiters) > 0 {
+		wake()
+	}
+}
+
+// wake unblocks the senders waiting for room.
+func wake() {
+	freedMu.Lock()
+	close(freed)
+	freed = make(chan struct{})
+	freedMu.Unlock()
+}
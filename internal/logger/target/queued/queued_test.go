@@ -0,0 +1,100 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package queued
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/internal/logger/target/types"
+)
+
+func TestAcquire(t *testing.T) {
+	defer SetLimit(0, PolicyDrop)
+
+	for i := 0; i < 10; i++ {
+		if err := Acquire(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if Count() != 10 {
+		t.Fatalf("expected 10 queued entries, got %d", Count())
+	}
+	Release(10)
+
+	SetLimit(2, PolicyDrop)
+	if Limit() != 2 {
+		t.Fatalf("expected limit 2, got %d", Limit())
+	}
+	Acquire(context.Background())
+	Acquire(context.Background())
+	if err := Acquire(context.Background()); !errors.Is(err, types.ErrLogBufferFull) {
+		t.Fatalf("expected %v, got %v", types.ErrLogBufferFull, err)
+	}
+	if Count() != 2 {
+		t.Fatalf("expected 2 queued entries, got %d", Count())
+	}
+
+	SetLimit(2, PolicyBlock)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := Acquire(ctx); err == nil {
+		t.Fatal("expected acquire to fail once the context is done")
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- Acquire(context.Background())
+	}()
+	select {
+	case <-done:
+		t.Fatal("expected acquire to wait for room")
+	case <-time.After(50 * time.Millisecond):
+	}
+	Release(1)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected acquire to proceed once room was freed")
+	}
+	if !Full() {
+		t.Fatal("expected the cap to be reported reached")
+	}
+	// Senders wait for room up to blockTimeout.
+	start := time.Now()
+	if err := Acquire(context.Background()); !errors.Is(err, types.ErrLogBufferFull) {
+		t.Fatalf("expected %v, got %v", types.ErrLogBufferFull, err)
+	}
+	if elapsed := time.Since(start); elapsed < blockTimeout/2 {
+		t.Fatalf("expected acquire to wait for room, took %s", elapsed)
+	}
+	Release(2)
+	if Full() {
+		t.Fatal("expected the cap not to be reported reached")
+	}
+
+	SetLimit(0, PolicyDrop)
+	if Limit() != 0 || Count() != 0 {
+		t.Fatalf("expected no limit and no queued entries, got %d and %d", Limit(), Count())
+	}
+}
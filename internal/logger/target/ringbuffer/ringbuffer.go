@@ -0,0 +1,100 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package ringbuffer
+
+import (
+	"sync"
+
+	"github.com/minio/minio/internal/logger/target/types"
+)
+
+// Default number of entries kept
+const defaultSize = 1000
+
+// Target implements logger.Target and keeps the most recent
+// log entries in memory, overwriting the oldest one when full.
+type Target struct {
+	sync.RWMutex
+	entries []interface{}
+	next    int
+	full    bool
+}
+
+// New initializes a new ring buffer target keeping the
+// last size entries, defaults to 1000 entries if size
+// is not positive.
+func New(size int) *Target {
+	if size <= 0 {
+		size = defaultSize
+	}
+	return &Target{
+		entries: make([]interface{}, size),
+	}
+}
+
+// Endpoint - returns the backend endpoint
+func (t *Target) Endpoint() string {
+	return ""
+}
+
+func (t *Target) String() string {
+	return "ringbuffer"
+}
+
+// Init - ring buffer target is always ready
+func (t *Target) Init() error {
+	return nil
+}
+
+// Cancel - nothing to cancel
+func (t *Target) Cancel() {
+}
+
+// Send - adds the entry to the ring buffer
+func (t *Target) Send(entry interface{}, errKind string) error {
+	t.Lock()
+	t.entries[t.next] = entry
+	t.next++
+	if t.next == len(t.entries) {
+		t.next = 0
+		t.full = true
+	}
+	t.Unlock()
+	return nil
+}
+
+// Snapshot returns the entries currently held, newest first.
+func (t *Target) Snapshot() []interface{} {
+	t.RLock()
+	defer t.RUnlock()
+
+	n := t.next
+	if t.full {
+		n = len(t.entries)
+	}
+	snapshot := make([]interface{}, 0, n)
+	for i := 1; i <= n; i++ {
+		snapshot = append(snapshot, t.entries[(t.next-i+len(t.entries))%len(t.entries)])
+	}
+	return snapshot
+}
+
+// Type - returns type of the target
+func (t *Target) Type() types.TargetType {
+	return types.TargetRingBuffer
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package ringbuffer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTargetSnapshot(t *testing.T) {
+	tgt := New(3)
+	if snapshot := tgt.Snapshot(); len(snapshot) != 0 {
+		t.Fatalf("expected empty snapshot, got %v", snapshot)
+	}
+
+	testCases := []struct {
+		entry    int
+		expected []interface{}
+	}{
+		{1, []interface{}{1}},
+		{2, []interface{}{2, 1}},
+		{3, []interface{}{3, 2, 1}},
+		{4, []interface{}{4, 3, 2}},
+		{5, []interface{}{5, 4, 3}},
+	}
+	for i, testCase := range testCases {
+		if err := tgt.Send(testCase.entry, ""); err != nil {
+			t.Fatal(err)
+		}
+		if snapshot := tgt.Snapshot(); !reflect.DeepEqual(snapshot, testCase.expected) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, snapshot)
+		}
+	}
+}
+
+func TestNewDefaultSize(t *testing.T) {
+	if size := len(New(0).entries); size != defaultSize {
+		t.Fatalf("expected default size %d, got %d", defaultSize, size)
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package splunk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	nethttp "net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/minio/minio/internal/logger/target/http"
+)
+
+// eventPath is the path of the HEC event endpoint, used
+// when the configured URL has none.
+const eventPath = "/services/collector/event"
+
+// Config Splunk HTTP Event Collector (HEC) target
+type Config struct {
+	Enabled bool   `json:"enabled"`
+	Name    string `json:"name"`
+	URL     string `json:"url"`
+	Token   string `json:"token"`
+
+	// Index, SourceType and Source of the events, left
+	// to the defaults of the token when empty.
+	Index      string `json:"index"`
+	SourceType string `json:"sourceType"`
+	Source     string `json:"source"`
+
+	QueueSize int `json:"queueSize"`
+
+	// BatchInterval when set, sends the events in batches
+	// of concatenated events flushed every BatchInterval.
+	BatchInterval time.Duration `json:"batchInterval"`
+
+	UserAgent string               `json:"userAgent"`
+	Transport nethttp.RoundTripper `json:"-"`
+
+	// Custom logger
+	LogOnce func(ctx context.Context, err error, id interface{}, errKind ...interface{}) `json:"-"`
+}
+
+// Validate checks the HEC URL and token.
+func (c Config) Validate() error {
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return fmt.Errorf("invalid splunk url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid splunk url %s: scheme must be http or https", c.URL)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("invalid splunk url %s: missing host", c.URL)
+	}
+	if c.Token == "" {
+		return errors.New("missing splunk token")
+	}
+	if _, err = uuid.Parse(c.Token); err != nil {
+		return errors.New("invalid splunk token, expected a GUID")
+	}
+	return nil
+}
+
+// HTTPConfig returns the config of the http target sending the
+// entries to the HEC, each entry wrapped as the event of an HEC
+// envelope.
+func (c Config) HTTPConfig() http.Config {
+	endpoint := c.URL
+	if u, err := url.Parse(c.URL); err == nil && strings.Trim(u.Path, "/") == "" {
+		u.Path = eventPath
+		endpoint = u.String()
+	}
+	return http.Config{
+		Enabled:         c.Enabled,
+		Name:            c.Name,
+		Endpoint:        endpoint,
+		AuthToken:       "Splunk " + c.Token,
+		QueueSize:       c.QueueSize,
+		BatchInterval:   c.BatchInterval,
+		PayloadTemplate: c.envelope(),
+		UserAgent:       c.UserAgent,
+		Transport:       c.Transport,
+		LogOnce:         c.LogOnce,
+
+		// Plaintext HEC endpoints are supported, see Validate.
+		AllowInsecureAuth: true,
+	}
+}
+
+// envelope returns the payload template of the HEC envelope.
+func (c Config) envelope() string {
+	var b strings.Builder
+	b.WriteString(`{"event": {{json .Entry}}`)
+	for _, field := range []struct{ key, value string }{
+		{"index", c.Index},
+		{"sourcetype", c.SourceType},
+		{"source", c.Source},
+	} {
+		if field.value != "" {
+			// Quoted as a template string so that the value
+			// is JSON encoded and never parsed as an action.
+			fmt.Fprintf(&b, `, %q: {{json %s}}`, field.key, strconv.Quote(field.value))
+		}
+	}
+	b.WriteString("}")
+	return b.String()
+}
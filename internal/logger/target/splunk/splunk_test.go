@@ -0,0 +1,114 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package splunk
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	nethttp "net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/minio/minio/internal/logger/target/http"
+)
+
+const testToken = "7e1a3a8e-5a3e-4f5c-9b1c-0d2e3f4a5b6c"
+
+func TestConfigValidate(t *testing.T) {
+	testCases := []struct {
+		url       string
+		token     string
+		shouldErr bool
+	}{
+		{"https://splunk:8088", testToken, false},
+		{"http://splunk:8088/services/collector/event", testToken, false},
+		{"ftp://splunk:8088", testToken, true},
+		{"https://", testToken, true},
+		{"https://splunk:8088", "", true},
+		{"https://splunk:8088", "token", true},
+	}
+	for i, testCase := range testCases {
+		err := Config{URL: testCase.url, Token: testCase.token}.Validate()
+		if testCase.shouldErr != (err != nil) {
+			t.Errorf("Test %d: expected error %v, got %v", i+1, testCase.shouldErr, err)
+		}
+	}
+}
+
+func TestTargetHEC(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		events []map[string]interface{}
+	)
+	srv := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		if r.URL.Path != eventPath || r.Header.Get("Authorization") != "Splunk "+testToken {
+			w.WriteHeader(nethttp.StatusUnauthorized)
+			return
+		}
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		var event map[string]interface{}
+		if err = json.Unmarshal(body, &event); err != nil {
+			t.Error(err)
+		}
+		mu.Lock()
+		events = append(events, event)
+		mu.Unlock()
+		w.Write([]byte(`{"text":"Success","code":0}`))
+	}))
+	defer srv.Close()
+
+	cfg := Config{
+		Enabled:    true,
+		URL:        srv.URL,
+		Token:      testToken,
+		Index:      "minio",
+		SourceType: `audit "{{x}}"`,
+		QueueSize:  10,
+	}.HTTPConfig()
+	cfg.LogOnce = func(_ context.Context, err error, _ interface{}, _ ...interface{}) { t.Error(err) }
+	tgt := http.New(cfg)
+	if err := tgt.Init(); err != nil {
+		t.Fatal(err)
+	}
+	if err := tgt.Send(map[string]string{"api": "PutObject"}, ""); err != nil {
+		t.Fatal(err)
+	}
+	tgt.Cancel()
+
+	mu.Lock()
+	defer mu.Unlock()
+	// The first event is the probe sent by Init.
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %v", events)
+	}
+	event := events[1]
+	if event["index"] != "minio" || event["sourcetype"] != `audit "{{x}}"` {
+		t.Fatalf("unexpected envelope %v", event)
+	}
+	if _, ok := event["source"]; ok {
+		t.Fatalf("expected no source, got %v", event)
+	}
+	if entry, ok := event["event"].(map[string]interface{}); !ok || entry["api"] != "PutObject" {
+		t.Fatalf("unexpected event %v", event["event"])
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package throttle holds the send rate limit shared by all the
+// logger targets delivering entries over the network.
+package throttle
+
+import (
+	"context"
+	"sync/atomic"
+
+	"golang.org/x/time/rate"
+)
+
+// limiter holds the shared *rate.Limiter, nil when disabled.
+var limiter atomic.Value
+
+// SetRate limits the total number of entries sent per second by all
+// the targets to n, a burst of up to n entries is allowed. A value
+// of 0 or less removes the limit, which is the default.
+func SetRate(n int) {
+	if n <= 0 {
+		limiter.Store((*rate.Limiter)(nil))
+		return
+	}
+	limiter.Store(rate.NewLimiter(rate.Limit(n), n))
+}
+
+// Rate returns the current limit, 0 if unlimited.
+func Rate() int {
+	l, _ := limiter.Load().(*rate.Limiter)
+	if l == nil {
+		return 0
+	}
+	return l.Burst()
+}
+
+// Wait blocks until count entries may be sent under the
+// shared limit or the context is done.
+func Wait(ctx context.Context, count int) error {
+	l, _ := limiter.Load().(*rate.Limiter)
+	if l == nil {
+		return nil
+	}
+	for count > 0 {
+		n := count
+		if n > l.Burst() {
+			n = l.Burst()
+		}
+		if err := l.WaitN(ctx, n); err != nil {
+			return err
+		}
+		count -= n
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package throttle
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWait(t *testing.T) {
+	defer SetRate(0)
+
+	if err := Wait(context.Background(), 1000); err != nil {
+		t.Fatal(err)
+	}
+
+	SetRate(10)
+	if Rate() != 10 {
+		t.Fatalf("expected rate 10, got %d", Rate())
+	}
+	// The burst is available at once, the next 5 entries take 500ms.
+	start := time.Now()
+	if err := Wait(context.Background(), 15); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Fatalf("expected entries beyond the burst to be throttled, took %s", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := Wait(ctx, 100); err == nil {
+		t.Fatal("expected wait to fail once the context is done")
+	}
+
+	SetRate(0)
+	if Rate() != 0 {
+		t.Fatalf("expected no limit, got %d", Rate())
+	}
+}
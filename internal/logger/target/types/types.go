@@ -17,6 +17,11 @@
 
 package types
 
+import (
+	"errors"
+	"time"
+)
+
 // TargetType indicates type of the target e.g. console, http, kafka
 type TargetType uint8
 
@@ -26,4 +31,76 @@ const (
 	TargetConsole
 	TargetHTTP
 	TargetKafka
+	TargetRingBuffer
+	TargetFile
+	TargetLoki
+	TargetWebSocket
+	TargetAMQP
+)
+
+func (t TargetType) String() string {
+	switch t {
+	case TargetConsole:
+		return "console"
+	case TargetHTTP:
+		return "http"
+	case TargetKafka:
+		return "kafka"
+	case TargetRingBuffer:
+		return "ringbuffer"
+	case TargetFile:
+		return "file"
+	case TargetLoki:
+		return "loki"
+	case TargetWebSocket:
+		return "websocket"
+	case TargetAMQP:
+		return "amqp"
+	}
+	return "unknown"
+}
+
+// Reasons reported by targets which are not online.
+var (
+	ErrTargetNotInitialized = errors.New("target is not initialized")
+	ErrTargetCanceled       = errors.New("target was canceled")
 )
+
+// ErrLogBufferFull is returned by Send when the queue of a target is
+// full, the entry is dropped.
+var ErrLogBufferFull = errors.New("log buffer full")
+
+// SecondsSinceLastSuccess returns the whole seconds elapsed at now since
+// lastSuccess, or since started if it is zero, 0 if both are zero.
+func SecondsSinceLastSuccess(lastSuccess, started, now time.Time) int64 {
+	since := lastSuccess
+	if since.IsZero() {
+		since = started
+	}
+	if since.IsZero() || now.Before(since) {
+		return 0
+	}
+	return int64(now.Sub(since) / time.Second)
+}
+
+// TargetStats is the delivery statistics of a target.
+type TargetStats struct {
+	Enabled         bool      `json:"enabled"`
+	TotalMessages   int64     `json:"totalMessages"`
+	FailedMessages  int64     `json:"failedMessages"`
+	ExpiredMessages int64     `json:"expiredMessages,omitempty"`
+	QueueLength     int       `json:"queueLength"`
+	QueueSize       int       `json:"queueSize,omitempty"`
+	LastSuccess     time.Time `json:"lastSuccess"`
+	LastError       time.Time `json:"lastError"`
+	LastErrorMsg    string    `json:"lastErrorMsg"`
+	ActiveSink      string    `json:"activeSink,omitempty"`
+	OfflineReason   string    `json:"offlineReason,omitempty"`
+
+	// Whole seconds since the last successful delivery, or since the
+	// target was initialized without any yet, to alert on.
+	SecondsSinceLastSuccess int64 `json:"secondsSinceLastSuccess,omitempty"`
+
+	// Entries queued in the priority lane of targets having one.
+	PriorityQueueLength int `json:"priorityQueueLength,omitempty"`
+}
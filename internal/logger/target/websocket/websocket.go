@@ -0,0 +1,407 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package websocket
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/minio/minio/internal/logger/target/queued"
+	"github.com/minio/minio/internal/logger/target/throttle"
+	"github.com/minio/minio/internal/logger/target/types"
+)
+
+// Timeouts of the opening handshake and of writing an entry.
+const (
+	handshakeTimeout = 10 * time.Second
+	writeTimeout     = 10 * time.Second
+)
+
+// Delays between reconnection attempts, doubled
+// after each failed attempt up to the maximum.
+const (
+	defaultRetryMin = time.Second
+	defaultRetryMax = 30 * time.Second
+)
+
+// errDisconnected is reported for the queued entries
+// dropped when the target is canceled while disconnected.
+var errDisconnected = errors.New("websocket target is disconnected")
+
+// Config WebSocket logger target
+type Config struct {
+	Enabled  bool   `json:"enabled"`
+	Name     string `json:"name"`
+	Endpoint string `json:"endpoint"`
+
+	// AuthToken is sent as the Authorization
+	// header of the opening handshake.
+	AuthToken string `json:"authToken"`
+
+	QueueSize int    `json:"queueSize"`
+	UserAgent string `json:"userAgent"`
+
+	TLSConfig *tls.Config `json:"-"`
+
+	// Custom logger
+	LogOnce func(ctx context.Context, err error, id interface{}, errKind ...interface{}) `json:"-"`
+}
+
+// redacted is the placeholder of redacted secrets.
+const redacted = "*REDACTED*"
+
+// Redacted returns a copy of the config with its secrets redacted.
+func (c Config) Redacted() Config {
+	if c.AuthToken != "" {
+		c.AuthToken = redacted
+	}
+	return c
+}
+
+// Validate checks the endpoint.
+func (c Config) Validate() error {
+	u, err := url.Parse(c.Endpoint)
+	if err != nil {
+		return fmt.Errorf("invalid websocket endpoint: %w", err)
+	}
+	if (u.Scheme != "ws" && u.Scheme != "wss") || u.Host == "" {
+		return fmt.Errorf("invalid websocket endpoint %s: expected a ws(s) URL", c.Endpoint)
+	}
+	return nil
+}
+
+// Target implements logger.Target and streams entries as text
+// messages over a persistent WebSocket connection. The connection
+// is reopened with backoff when it drops, entries are kept in the
+// queue in the meantime. WebSocket messages are not acknowledged,
+// entries written right before a drop may be lost.
+type Target struct {
+	// Accessed atomically, must stay 64-bit aligned.
+	totalMessages  int64
+	failedMessages int64
+
+	status    int32
+	connected int32
+	wg        sync.WaitGroup
+
+	// Channel of log entries
+	logCh chan interface{}
+	// Closed on cancel, stops the reconnection attempts.
+	doneCh chan struct{}
+
+	dialer *websocket.Dialer
+	header http.Header
+
+	// Open connection, nil while disconnected.
+	connMu sync.Mutex
+	conn   *websocket.Conn
+
+	retryMin time.Duration
+	retryMax time.Duration
+	// Set once canceled while disconnected, the
+	// remaining entries are dropped.
+	gaveUp bool
+
+	// Outcome of the last writes
+	statsMu      sync.Mutex
+	started      time.Time
+	lastSuccess  time.Time
+	lastError    time.Time
+	lastErrorMsg string
+
+	config Config
+}
+
+// New initializes a new WebSocket target, Init must be called
+// before any entry is sent.
+func New(config Config) *Target {
+	return &Target{
+		logCh:    make(chan interface{}, config.QueueSize),
+		doneCh:   make(chan struct{}),
+		retryMin: defaultRetryMin,
+		retryMax: defaultRetryMax,
+		config:   config,
+	}
+}
+
+// Endpoint returns the WebSocket endpoint
+func (h *Target) Endpoint() string {
+	return h.config.Endpoint
+}
+
+// String returns the name of the target
+func (h *Target) String() string {
+	return h.config.Name
+}
+
+// Config returns the target config with its secrets redacted.
+func (h *Target) Config() Config {
+	return h.config.Redacted()
+}
+
+// Init validates the config and opens the connection
+// to check that the endpoint accepts it.
+func (h *Target) Init() error {
+	if err := h.config.Validate(); err != nil {
+		return err
+	}
+	h.dialer = &websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: handshakeTimeout,
+		TLSClientConfig:  h.config.TLSConfig,
+	}
+	h.header = make(http.Header)
+	h.header.Set("User-Agent", h.config.UserAgent)
+	if h.config.AuthToken != "" {
+		h.header.Set("Authorization", h.config.AuthToken)
+	}
+
+	if _, err := h.connect(); err != nil {
+		return err
+	}
+
+	h.statsMu.Lock()
+	h.started = time.Now()
+	h.statsMu.Unlock()
+	h.status = 1
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+		h.streamEntries()
+	}()
+	return nil
+}
+
+// Send queues the entry, an error is returned if the queue is full.
+func (h *Target) Send(entry interface{}, errKind string) error {
+	if atomic.LoadInt32(&h.status) == 0 {
+		// Channel was closed or used before init.
+		return nil
+	}
+
+	if err := queued.Acquire(context.Background()); err != nil {
+		return err
+	}
+	select {
+	case h.logCh <- entry:
+	default:
+		queued.Release(1)
+		// log channel is full, do not wait and return
+		// an error immediately to the caller
+		return types.ErrLogBufferFull
+	}
+	return nil
+}
+
+// streamEntries writes the queued entries until the queue is closed.
+func (h *Target) streamEntries() {
+	for entry := range h.logCh {
+		queued.Release(1)
+		data, err := json.Marshal(&entry)
+		if err != nil {
+			continue
+		}
+		throttle.Wait(context.Background(), 1)
+		h.record(h.write(data), 1)
+	}
+
+	h.connMu.Lock()
+	conn := h.conn
+	h.conn = nil
+	h.connMu.Unlock()
+	if conn != nil {
+		conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+			time.Now().Add(writeTimeout))
+		conn.Close()
+	}
+	atomic.StoreInt32(&h.connected, 0)
+}
+
+// write writes the message, reconnecting as long as it takes unless
+// the target is canceled.
+func (h *Target) write(data []byte) error {
+	for attempt := 0; ; {
+		h.connMu.Lock()
+		conn := h.conn
+		h.connMu.Unlock()
+		if conn == nil {
+			if h.gaveUp {
+				return errDisconnected
+			}
+			var err error
+			if conn, err = h.connect(); err != nil {
+				h.logError(err)
+				if !h.waitRetry(attempt) {
+					h.gaveUp = true
+					return errDisconnected
+				}
+				attempt++
+				continue
+			}
+			attempt = 0
+		}
+		conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		err := conn.WriteMessage(websocket.TextMessage, data)
+		if err == nil {
+			return nil
+		}
+		h.disconnect(conn)
+		h.logError(fmt.Errorf("%s connection lost: %w", h.config.Endpoint, err))
+	}
+}
+
+// waitRetry waits before the next reconnection attempt,
+// false is returned if the target was canceled.
+func (h *Target) waitRetry(attempt int) bool {
+	select {
+	case <-h.doneCh:
+		return false
+	default:
+	}
+	delay := h.retryMax
+	if attempt < 16 && h.retryMin<<attempt < h.retryMax {
+		delay = h.retryMin << attempt
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-h.doneCh:
+		return false
+	}
+}
+
+// connect opens a new connection to the endpoint.
+func (h *Target) connect() (*websocket.Conn, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), handshakeTimeout)
+	defer cancel()
+
+	conn, resp, err := h.dialer.DialContext(ctx, h.config.Endpoint, h.header)
+	if err != nil {
+		if resp != nil {
+			resp.Body.Close()
+			return nil, fmt.Errorf("%s returned '%s', please check your endpoint configuration", h.config.Endpoint, resp.Status)
+		}
+		return nil, fmt.Errorf("%s returned '%w', please check your endpoint configuration", h.config.Endpoint, err)
+	}
+
+	h.connMu.Lock()
+	h.conn = conn
+	h.connMu.Unlock()
+	atomic.StoreInt32(&h.connected, 1)
+
+	go func() {
+		// Nothing is expected from the endpoint, reading
+		// is required to process the control messages and
+		// notice the connection is closed.
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				h.disconnect(conn)
+				return
+			}
+		}
+	}()
+	return conn, nil
+}
+
+// disconnect closes conn unless it was already replaced.
+func (h *Target) disconnect(conn *websocket.Conn) {
+	h.connMu.Lock()
+	defer h.connMu.Unlock()
+	if h.conn != conn {
+		return
+	}
+	conn.Close()
+	h.conn = nil
+	atomic.StoreInt32(&h.connected, 0)
+}
+
+// logError records the last error and logs it.
+func (h *Target) logError(err error) {
+	h.statsMu.Lock()
+	h.lastError = time.Now()
+	h.lastErrorMsg = err.Error()
+	h.statsMu.Unlock()
+
+	if h.config.LogOnce != nil {
+		h.config.LogOnce(context.Background(), err, h.config.Endpoint)
+	}
+}
+
+// record records the outcome of writing count entries.
+func (h *Target) record(err error, count int64) {
+	if err != nil {
+		atomic.AddInt64(&h.failedMessages, count)
+		h.logError(err)
+	} else {
+		h.statsMu.Lock()
+		h.lastSuccess = time.Now()
+		h.statsMu.Unlock()
+	}
+	atomic.AddInt64(&h.totalMessages, count)
+}
+
+// Cancel writes the queued entries, those still queued while
+// disconnected are dropped, and closes the connection.
+func (h *Target) Cancel() {
+	if atomic.CompareAndSwapInt32(&h.status, 1, 0) {
+		close(h.doneCh)
+		close(h.logCh)
+	}
+	h.wg.Wait()
+}
+
+// IsOnline returns true if the target is initialized,
+// not canceled and connected.
+func (h *Target) IsOnline() bool {
+	return atomic.LoadInt32(&h.status) == 1 && atomic.LoadInt32(&h.connected) == 1
+}
+
+// Stats returns the delivery statistics of the target.
+func (h *Target) Stats() types.TargetStats {
+	h.statsMu.Lock()
+	defer h.statsMu.Unlock()
+	return types.TargetStats{
+		Enabled:        true,
+		TotalMessages:  atomic.LoadInt64(&h.totalMessages),
+		FailedMessages: atomic.LoadInt64(&h.failedMessages),
+		QueueLength:    len(h.logCh),
+		LastSuccess:    h.lastSuccess,
+		LastError:      h.lastError,
+		LastErrorMsg:   h.lastErrorMsg,
+
+		SecondsSinceLastSuccess: types.SecondsSinceLastSuccess(h.lastSuccess, h.started, time.Now()),
+	}
+}
+
+// Type returns the type of the target
+func (h *Target) Type() types.TargetType {
+	return types.TargetWebSocket
+}
@@ -0,0 +1,190 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package websocket
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestConfigValidate(t *testing.T) {
+	testCases := []struct {
+		config    Config
+		shouldErr bool
+	}{
+		{Config{Endpoint: "ws://logs:8080/ingest"}, false},
+		{Config{Endpoint: "wss://logs:8443"}, false},
+		{Config{Endpoint: "http://logs:8080"}, true},
+		{Config{Endpoint: "logs:8080"}, true},
+		{Config{Endpoint: "ws://"}, true},
+	}
+	for i, testCase := range testCases {
+		err := testCase.config.Validate()
+		if testCase.shouldErr != (err != nil) {
+			t.Errorf("Test %d: expected error %v, got %v", i+1, testCase.shouldErr, err)
+		}
+	}
+}
+
+// testServer accepts the connections authenticated with
+// token and forwards the received messages to msgCh.
+type testServer struct {
+	*httptest.Server
+	msgCh chan string
+
+	mu    sync.Mutex
+	conns []*websocket.Conn
+}
+
+func newTestServer(t *testing.T, token string) *testServer {
+	s := &testServer{msgCh: make(chan string, 100)}
+	upgrader := websocket.Upgrader{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != token || r.Header.Get("User-Agent") != "MinIO-Test" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		s.mu.Lock()
+		s.conns = append(s.conns, conn)
+		s.mu.Unlock()
+		for {
+			_, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			s.msgCh <- string(msg)
+		}
+	}))
+	return s
+}
+
+// dropConns closes the open connections.
+func (s *testServer) dropConns() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, conn := range s.conns {
+		conn.Close()
+	}
+	s.conns = nil
+}
+
+func (s *testServer) expect(t *testing.T, want string) {
+	t.Helper()
+	select {
+	case msg := <-s.msgCh:
+		if msg != want {
+			t.Fatalf("expected message %s, got %s", want, msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for message %s", want)
+	}
+}
+
+func waitOnline(t *testing.T, tgt *Target, online bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for tgt.IsOnline() != online {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected online %v", online)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestTargetStream(t *testing.T) {
+	srv := newTestServer(t, "Bearer token")
+	defer srv.Close()
+	endpoint := "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	tgt := New(Config{Endpoint: endpoint, AuthToken: "wrong", UserAgent: "MinIO-Test", QueueSize: 10})
+	if err := tgt.Init(); err == nil {
+		t.Fatal("expected the handshake to be rejected")
+	}
+
+	tgt = New(Config{Endpoint: endpoint, AuthToken: "Bearer token", UserAgent: "MinIO-Test", QueueSize: 10})
+	tgt.retryMin = time.Millisecond
+	tgt.retryMax = 10 * time.Millisecond
+	if err := tgt.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer tgt.Cancel()
+	if !tgt.IsOnline() {
+		t.Fatal("expected the target to be online")
+	}
+
+	send := func(n int) string {
+		entry := map[string]int{"n": n}
+		if err := tgt.Send(entry, ""); err != nil {
+			t.Fatal(err)
+		}
+		data, _ := json.Marshal(entry)
+		return string(data)
+	}
+	for i := 0; i < 3; i++ {
+		srv.expect(t, send(i))
+	}
+
+	// The entries sent after a drop are written to a new connection.
+	srv.dropConns()
+	waitOnline(t, tgt, false)
+	srv.expect(t, send(3))
+	waitOnline(t, tgt, true)
+
+	stats := tgt.Stats()
+	if stats.TotalMessages != 4 || stats.FailedMessages != 0 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
+
+func TestTargetCancelDisconnected(t *testing.T) {
+	srv := newTestServer(t, "")
+	endpoint := "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	tgt := New(Config{Endpoint: endpoint, UserAgent: "MinIO-Test", QueueSize: 10})
+	tgt.retryMin = time.Millisecond
+	tgt.retryMax = 10 * time.Millisecond
+	if err := tgt.Init(); err != nil {
+		t.Fatal(err)
+	}
+	srv.dropConns()
+	srv.Close()
+	waitOnline(t, tgt, false)
+
+	for i := 0; i < 3; i++ {
+		if err := tgt.Send(i, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Queued entries are dropped instead of waiting for the endpoint.
+	tgt.Cancel()
+	if stats := tgt.Stats(); stats.FailedMessages != 3 {
+		t.Fatalf("expected 3 failed messages, got %+v", stats)
+	}
+}
@@ -18,17 +18,26 @@
 package logger
 
 import (
+	"fmt"
 	"sync"
 	"sync/atomic"
 
+	"github.com/minio/minio/internal/logger/target/amqp"
+	"github.com/minio/minio/internal/logger/target/file"
 	"github.com/minio/minio/internal/logger/target/http"
 	"github.com/minio/minio/internal/logger/target/kafka"
+	"github.com/minio/minio/internal/logger/target/loki"
+	"github.com/minio/minio/internal/logger/target/queued"
+	"github.com/minio/minio/internal/logger/target/splunk"
+	"github.com/minio/minio/internal/logger/target/throttle"
 	"github.com/minio/minio/internal/logger/target/types"
+	"github.com/minio/minio/internal/logger/target/websocket"
 )
 
 // Target is the entity that we will receive
 // a single log entry and Send it to the log target
-//   e.g. Send the log to a http server
+//
+//	e.g. Send the log to a http server
 type Target interface {
 	String() string
 	Endpoint() string
@@ -127,6 +136,68 @@ func initSystemTargets(cfgMap map[string]http.Config) (tgts []Target, err error)
 	return tgts, err
 }
 
+// cancelTargets - cancels the targets started before one failed to start.
+func cancelTargets(tgts []Target) {
+	for _, tgt := range tgts {
+		tgt.Cancel()
+	}
+}
+
+func initFileTargets(cfgMap map[string]file.Config) (tgts []Target, err error) {
+	for _, l := range cfgMap {
+		if l.Enabled {
+			t := file.New(l)
+			if err = t.Init(); err != nil {
+				cancelTargets(tgts)
+				return nil, err
+			}
+			tgts = append(tgts, t)
+		}
+	}
+	return tgts, err
+}
+
+func initSplunkTargets(cfgMap map[string]splunk.Config) (tgts []Target, err error) {
+	for _, l := range cfgMap {
+		if l.Enabled {
+			t := http.New(l.HTTPConfig())
+			if err = t.Init(); err != nil {
+				return tgts, err
+			}
+			tgts = append(tgts, t)
+		}
+	}
+	return tgts, err
+}
+
+func initLokiTargets(cfgMap map[string]loki.Config) (tgts []Target, err error) {
+	for _, l := range cfgMap {
+		if l.Enabled {
+			t := loki.New(l)
+			if err = t.Init(); err != nil {
+				cancelTargets(tgts)
+				return nil, err
+			}
+			tgts = append(tgts, t)
+		}
+	}
+	return tgts, err
+}
+
+func initWebSocketTargets(cfgMap map[string]websocket.Config) (tgts []Target, err error) {
+	for _, l := range cfgMap {
+		if l.Enabled {
+			t := websocket.New(l)
+			if err = t.Init(); err != nil {
+				cancelTargets(tgts)
+				return nil, err
+			}
+			tgts = append(tgts, t)
+		}
+	}
+	return tgts, err
+}
+
 func initKafkaTargets(cfgMap map[string]kafka.Config) (tgts []Target, err error) {
 	for _, l := range cfgMap {
 		if l.Enabled {
@@ -140,12 +211,44 @@ func initKafkaTargets(cfgMap map[string]kafka.Config) (tgts []Target, err error)
 	return tgts, err
 }
 
+func initAMQPTargets(cfgMap map[string]amqp.Config) (tgts []Target, err error) {
+	for _, l := range cfgMap {
+		if l.Enabled {
+			t := amqp.New(l)
+			if err = t.Init(); err != nil {
+				cancelTargets(tgts)
+				return nil, err
+			}
+			tgts = append(tgts, t)
+		}
+	}
+	return tgts, err
+}
+
 // UpdateSystemTargets swaps targets with newly loaded ones from the cfg
 func UpdateSystemTargets(cfg Config) error {
 	updated, err := initSystemTargets(cfg.HTTP)
 	if err != nil {
 		return err
 	}
+	fileTgts, err := initFileTargets(cfg.File)
+	if err != nil {
+		cancelTargets(updated)
+		return err
+	}
+	updated = append(updated, fileTgts...)
+	lokiTgts, err := initLokiTargets(cfg.Loki)
+	if err != nil {
+		cancelTargets(updated)
+		return err
+	}
+	updated = append(updated, lokiTgts...)
+	wsTgts, err := initWebSocketTargets(cfg.WebSocket)
+	if err != nil {
+		cancelTargets(updated)
+		return err
+	}
+	updated = append(updated, wsTgts...)
 
 	swapMu.Lock()
 	for _, tgt := range systemTargets {
@@ -171,11 +274,14 @@ func cancelAuditTargetType(t types.TargetType) {
 	}
 }
 
-func existingAuditTargets(t types.TargetType) []Target {
+func existingAuditTargets(ts ...types.TargetType) []Target {
 	tgts := make([]Target, 0, len(auditTargets))
 	for _, tgt := range auditTargets {
-		if tgt.Type() == t {
-			tgts = append(tgts, tgt)
+		for _, t := range ts {
+			if tgt.Type() == t {
+				tgts = append(tgts, tgt)
+				break
+			}
 		}
 	}
 	return tgts
@@ -187,8 +293,17 @@ func UpdateAuditWebhookTargets(cfg Config) error {
 	if err != nil {
 		return err
 	}
-	// retain kafka targets
-	updated = append(existingAuditTargets(types.TargetKafka), updated...)
+	// Splunk targets are http targets as well.
+	splunkTgts, err := initSplunkTargets(cfg.AuditSplunk)
+	if err != nil {
+		for _, tgt := range updated {
+			tgt.Cancel()
+		}
+		return err
+	}
+	updated = append(updated, splunkTgts...)
+	// retain kafka and amqp targets
+	updated = append(existingAuditTargets(types.TargetKafka, types.TargetAMQP), updated...)
 
 	swapMu.Lock()
 	atomic.StoreInt32(&nAuditTargets, int32(len(updated)))
@@ -204,8 +319,8 @@ func UpdateAuditKafkaTargets(cfg Config) error {
 	if err != nil {
 		return err
 	}
-	// retain HTTP targets
-	updated = append(existingAuditTargets(types.TargetHTTP), updated...)
+	// retain HTTP and amqp targets
+	updated = append(existingAuditTargets(types.TargetHTTP, types.TargetAMQP), updated...)
 
 	swapMu.Lock()
 	atomic.StoreInt32(&nAuditTargets, int32(len(updated)))
@@ -214,3 +329,65 @@ func UpdateAuditKafkaTargets(cfg Config) error {
 	swapMu.Unlock()
 	return nil
 }
+
+// UpdateAuditAMQPTargets swaps audit amqp targets with newly loaded ones from the cfg
+func UpdateAuditAMQPTargets(cfg Config) error {
+	updated, err := initAMQPTargets(cfg.AuditAMQP)
+	if err != nil {
+		return err
+	}
+	// retain HTTP and kafka targets
+	updated = append(existingAuditTargets(types.TargetHTTP, types.TargetKafka), updated...)
+
+	swapMu.Lock()
+	atomic.StoreInt32(&nAuditTargets, int32(len(updated)))
+	cancelAuditTargetType(types.TargetAMQP) // cancel running targets
+	auditTargets = updated
+	swapMu.Unlock()
+	return nil
+}
+
+// EnvLoggerSendRate caps the entries sent per second by all targets.
+const EnvLoggerSendRate = "MINIO_LOGGER_SEND_RATE"
+
+// SetGlobalSendRate caps the total number of entries sent per second
+// by all the webhook and kafka targets to n, on top of any limit of
+// the targets themselves. Targets wait for their turn instead of
+// dropping entries, which then pile up in their queues. A value of 0
+// or less removes the cap, which is the default.
+func SetGlobalSendRate(n int) {
+	throttle.SetRate(n)
+}
+
+// Cap of the entries queued by all targets, and the policy applied to
+// entries sent beyond, either "drop" (default) or "block".
+const (
+	EnvLoggerMaxQueued       = "MINIO_LOGGER_MAX_QUEUED"
+	EnvLoggerMaxQueuedPolicy = "MINIO_LOGGER_MAX_QUEUED_POLICY"
+)
+
+// SetGlobalQueueLimit caps the total number of entries queued by all
+// the network targets, webhook, splunk, kafka, amqp, loki and websocket,
+// to n, bounding the memory they use on top of the queue_size of each.
+// The console and file targets are not counted. Beyond, entries are
+// dropped as when a queue is full, or with the "block" policy senders
+// wait up to a second for room before they are. These targets are not
+// Ready while the cap is reached. A value of 0 or less removes the cap,
+// which is the default.
+func SetGlobalQueueLimit(n int, policy string) error {
+	switch policy {
+	case "", queued.PolicyDrop:
+		policy = queued.PolicyDrop
+	case queued.PolicyBlock:
+	default:
+		return fmt.Errorf("unknown policy %q, expected %q or %q", policy, queued.PolicyDrop, queued.PolicyBlock)
+	}
+	queued.SetLimit(n, policy)
+	return nil
+}
+
+// QueuedEntries returns the number of entries
+// queued by all the network targets.
+func QueuedEntries() int64 {
+	return queued.Count()
+}
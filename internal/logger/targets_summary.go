@@ -0,0 +1,107 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package logger
+
+import (
+	"github.com/minio/minio/internal/logger/target/file"
+	"github.com/minio/minio/internal/logger/target/http"
+	"github.com/minio/minio/internal/logger/target/kafka"
+	"github.com/minio/minio/internal/logger/target/loki"
+	"github.com/minio/minio/internal/logger/target/types"
+	"github.com/minio/minio/internal/logger/target/websocket"
+)
+
+// Kinds of targets listed by ListTargets
+const (
+	TargetKindLogger = "logger"
+	TargetKindAudit  = "audit"
+)
+
+// TargetSummary describes a live logger or audit target,
+// secrets of its config are redacted.
+type TargetSummary struct {
+	Name     string             `json:"name"`
+	Kind     string             `json:"kind"`
+	Type     string             `json:"type"`
+	Endpoint string             `json:"endpoint"`
+	Enabled  bool               `json:"enabled"`
+	Online   bool               `json:"online"`
+	Stats    *types.TargetStats `json:"stats,omitempty"`
+	Config   interface{}        `json:"config,omitempty"`
+}
+
+// ListTargets returns the summary of all the logger and
+// audit targets currently running, in that order.
+func ListTargets() []TargetSummary {
+	systemTgts, auditTgts := SystemTargets(), AuditTargets()
+	summaries := make([]TargetSummary, 0, len(systemTgts)+len(auditTgts))
+	for _, tgt := range systemTgts {
+		summaries = append(summaries, summarizeTarget(tgt, TargetKindLogger))
+	}
+	for _, tgt := range auditTgts {
+		summaries = append(summaries, summarizeTarget(tgt, TargetKindAudit))
+	}
+	return summaries
+}
+
+func summarizeTarget(tgt Target, kind string) TargetSummary {
+	summary := TargetSummary{
+		Name:     tgt.String(),
+		Kind:     kind,
+		Type:     tgt.Type().String(),
+		Endpoint: tgt.Endpoint(),
+		Enabled:  true,
+		Online:   true,
+	}
+	switch t := tgt.(type) {
+	case *http.Target:
+		cfg := t.Config()
+		stats := t.Stats()
+		summary.Endpoint = cfg.Endpoint
+		summary.Enabled = cfg.Enabled && stats.Enabled
+		summary.Online = t.IsOnline()
+		summary.Stats = &stats
+		summary.Config = cfg
+	case *file.Target:
+		cfg := t.Config()
+		summary.Enabled = cfg.Enabled
+		summary.Config = cfg
+	case *loki.Target:
+		cfg := t.Config()
+		stats := t.Stats()
+		summary.Enabled = cfg.Enabled
+		summary.Stats = &stats
+		summary.Online = t.IsOnline()
+		summary.Config = cfg
+	case *websocket.Target:
+		cfg := t.Config()
+		stats := t.Stats()
+		summary.Enabled = cfg.Enabled
+		summary.Stats = &stats
+		summary.Online = t.IsOnline()
+		summary.Config = cfg
+	case *kafka.Target:
+		cfg := t.Config()
+		stats := t.Stats()
+		summary.Enabled = cfg.Enabled
+		summary.Stats = &stats
+		summary.Online = t.IsOnline()
+		summary.Config = cfg
+	}
+	return summary
+}
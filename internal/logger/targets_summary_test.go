@@ -0,0 +1,71 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package logger
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	xhttp "github.com/minio/minio/internal/logger/target/http"
+)
+
+func TestListTargets(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	endpoint := strings.Replace(srv.URL, "http://", "http://user:secret@", 1)
+	err := UpdateSystemTargets(Config{HTTP: map[string]xhttp.Config{
+		"target1": {
+			Enabled:   true,
+			Name:      "target1",
+			Endpoint:  endpoint,
+			AuthToken: "Bearer token",
+			QueueSize: 1,
+			Transport: http.DefaultTransport,
+
+			AllowInsecureAuth: true,
+		},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer UpdateSystemTargets(Config{})
+
+	summaries := ListTargets()
+	if len(summaries) != 1 {
+		t.Fatalf("expected 1 target, got %d", len(summaries))
+	}
+	summary := summaries[0]
+	if summary.Name != "target1" || summary.Kind != TargetKindLogger || summary.Type != "http" ||
+		!summary.Enabled || !summary.Online || summary.Stats == nil {
+		t.Fatalf("unexpected target summary %#v", summary)
+	}
+
+	data, err := json.Marshal(summaries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "secret") || strings.Contains(string(data), "Bearer") {
+		t.Fatalf("expected secrets to be redacted, got %s", data)
+	}
+}
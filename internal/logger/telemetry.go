@@ -0,0 +1,89 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package logger
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"time"
+
+	xhttp "github.com/minio/minio/internal/http"
+	"github.com/minio/minio/internal/logger/message/log"
+	"github.com/minio/minio/internal/logger/target/types"
+)
+
+// EnvLoggerTelemetryInterval enables the periodic telemetry entries.
+const EnvLoggerTelemetryInterval = "MINIO_LOGGER_TELEMETRY_INTERVAL"
+
+// StartTelemetry sends an entry with the resource usage of the node to
+// the logger targets, the console excepted, every interval until ctx is
+// done. Its errKind is Telemetry for receivers to route them apart.
+// requestCount returns the number of requests in flight, if not nil.
+func StartTelemetry(ctx context.Context, interval time.Duration, requestCount func() int) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				sendTelemetry(telemetryEntry(requestCount))
+			}
+		}
+	}()
+}
+
+// telemetryEntry returns an entry with the current resource usage.
+func telemetryEntry(requestCount func() int) log.Entry {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	t := &log.Telemetry{
+		Goroutines: runtime.NumGoroutine(),
+		HeapAlloc:  m.HeapAlloc,
+		Sys:        m.Sys,
+	}
+	// Only known where procfs is available.
+	if fds, err := os.ReadDir("/proc/self/fd"); err == nil {
+		t.OpenFDs = len(fds)
+	}
+	if requestCount != nil {
+		t.RequestsInFlight = requestCount()
+	}
+	return log.Entry{
+		DeploymentID: xhttp.GlobalDeploymentID,
+		Level:        InformationLvl.String(),
+		LogKind:      string(Telemetry),
+		Time:         time.Now().UTC(),
+		Message:      "telemetry",
+		Telemetry:    t,
+	}
+}
+
+func sendTelemetry(entry log.Entry) {
+	for _, t := range SystemTargets() {
+		if t.Type() == types.TargetConsole {
+			continue
+		}
+		t.Send(entry, entry.LogKind)
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package logger
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/minio/minio/internal/logger/message/log"
+)
+
+func TestSendTelemetry(t *testing.T) {
+	webhook, console := &testAuditTarget{}, &testConsoleTarget{}
+	swapMu.Lock()
+	systemTargets = []Target{webhook, console}
+	atomic.StoreInt32(&nTargets, 2)
+	swapMu.Unlock()
+	defer func() {
+		swapMu.Lock()
+		systemTargets = []Target{}
+		atomic.StoreInt32(&nTargets, 0)
+		swapMu.Unlock()
+	}()
+
+	sendTelemetry(telemetryEntry(func() int { return 3 }))
+
+	if len(console.entries) != 0 {
+		t.Fatal("expected no telemetry entries on the console")
+	}
+	if len(webhook.entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(webhook.entries))
+	}
+	entry := webhook.entries[0].(log.Entry)
+	if entry.LogKind != string(Telemetry) || entry.Telemetry == nil {
+		t.Fatalf("expected a telemetry entry, got %+v", entry)
+	}
+	if entry.Telemetry.RequestsInFlight != 3 || entry.Telemetry.Goroutines == 0 || entry.Telemetry.HeapAlloc == 0 {
+		t.Fatalf("unexpected telemetry %+v", entry.Telemetry)
+	}
+}
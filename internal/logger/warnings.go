@@ -0,0 +1,146 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package logger
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/minio/minio/internal/config"
+	"github.com/minio/minio/internal/logger/target/http"
+)
+
+// minQueueSize is the queue size below which targets are warned
+// about dropping entries during bursts.
+const minQueueSize = 1000
+
+// ConfigWarning - a debatable setting of a target, which does not fail
+// the config lookup but operators should be made aware of.
+type ConfigWarning struct {
+	SubSys  string `json:"subSys"`
+	Target  string `json:"target"`
+	Key     string `json:"key"`
+	Message string `json:"message"`
+}
+
+func (w ConfigWarning) String() string {
+	subSysTarget := w.SubSys
+	if w.Target != config.Default {
+		subSysTarget += config.SubSystemSeparator + w.Target
+	}
+	return fmt.Sprintf("%s %s: %s", subSysTarget, w.Key, w.Message)
+}
+
+// targetSettings - the settings of a target checked for warnings,
+// common to the kinds of targets.
+type targetSettings struct {
+	queueSize  int
+	skipVerify bool
+
+	// Whether an auth token is sent to a plaintext endpoint.
+	insecureAuth bool
+}
+
+// warningChecks - checks of the settings of a target, each returning
+// the key of a debatable setting and why, or an empty key.
+var warningChecks = []func(s targetSettings) (key, msg string){
+	func(s targetSettings) (string, string) {
+		if s.insecureAuth {
+			return AllowInsecureAuth, "the auth_token is sent in plaintext to an http endpoint"
+		}
+		return "", ""
+	},
+	func(s targetSettings) (string, string) {
+		if s.queueSize > 0 && s.queueSize < minQueueSize {
+			return QueueSize, fmt.Sprintf("a queue of %d entries may drop entries during bursts, %d or more is recommended", s.queueSize, minQueueSize)
+		}
+		return "", ""
+	},
+	func(s targetSettings) (string, string) {
+		if s.skipVerify {
+			// Same key as AMQPTLSSkipVerify.
+			return KafkaTLSSkipVerify, "the certificate of the endpoint is not verified"
+		}
+		return "", ""
+	},
+}
+
+// webhookSettings returns the settings of a webhook target.
+func webhookSettings(l http.Config) targetSettings {
+	return targetSettings{
+		queueSize:    l.QueueSize,
+		insecureAuth: l.AuthToken != "" && l.AllowInsecureAuth && http.IsPlaintextEndpoint(l.Endpoint),
+	}
+}
+
+// configWarnings - returns the warnings of the enabled targets of
+// subSys, looked up in cfg or in scfg for the Kafka targets.
+func configWarnings(scfg config.Config, cfg Config, subSys string) ([]ConfigWarning, error) {
+	settings := make(map[string]targetSettings)
+	switch subSys {
+	case config.LoggerWebhookSubSys:
+		for target, l := range cfg.HTTP {
+			if l.Enabled {
+				settings[target] = webhookSettings(l)
+			}
+		}
+	case config.AuditWebhookSubSys:
+		for target, l := range cfg.AuditWebhook {
+			if l.Enabled {
+				settings[target] = webhookSettings(l)
+			}
+		}
+	case config.AuditKafkaSubSys:
+		kafkaTargets, err := GetAuditKafka(scfg[config.AuditKafkaSubSys])
+		if err != nil {
+			return nil, err
+		}
+		for target, l := range kafkaTargets {
+			if l.Enabled {
+				settings[target] = targetSettings{skipVerify: l.TLS.SkipVerify}
+			}
+		}
+	case config.AuditAMQPSubSys:
+		for target, l := range cfg.AuditAMQP {
+			if l.Enabled {
+				settings[target] = targetSettings{queueSize: l.QueueSize, skipVerify: l.TLS.SkipVerify}
+			}
+		}
+	}
+
+	targets := make([]string, 0, len(settings))
+	for target := range settings {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+
+	var warnings []ConfigWarning
+	for _, target := range targets {
+		for _, check := range warningChecks {
+			if key, msg := check(settings[target]); key != "" {
+				warnings = append(warnings, ConfigWarning{
+					SubSys:  subSys,
+					Target:  target,
+					Key:     key,
+					Message: msg,
+				})
+			}
+		}
+	}
+	return warnings, nil
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package logger
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/minio/minio/internal/config"
+)
+
+func TestValidateSubSysConfigWarnings(t *testing.T) {
+	insecure := DefaultAuditWebhookKVS.Clone()
+	insecure.Set(config.Enable, config.EnableOn)
+	insecure.Set(Endpoint, "http://localhost:8080/logs")
+	insecure.Set(AuthToken, "secret")
+	insecure.Set(AllowInsecureAuth, config.EnableOn)
+	insecure.Set(QueueSize, "10")
+	secure := DefaultAuditWebhookKVS.Clone()
+	secure.Set(config.Enable, config.EnableOn)
+	secure.Set(Endpoint, "https://localhost:8080/logs")
+	secure.Set(AuthToken, "secret")
+	disabled := insecure.Clone()
+	disabled.Set(config.Enable, config.EnableOff)
+	scfg := config.Config{
+		config.AuditWebhookSubSys: map[string]config.KVS{
+			"insecure": insecure,
+			"secure":   secure,
+			"disabled": disabled,
+		},
+	}
+
+	warnings, err := ValidateSubSysConfig(scfg, config.AuditWebhookSubSys, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) != 0 {
+		t.Fatalf("expected no warnings unless asked for, got %v", warnings)
+	}
+
+	warnings, err = ValidateSubSysConfig(scfg, config.AuditWebhookSubSys, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for _, w := range warnings {
+		if w.SubSys != config.AuditWebhookSubSys || w.Target != "insecure" {
+			t.Fatalf("unexpected warning %v", w)
+		}
+		keys = append(keys, w.Key)
+	}
+	if expected := []string{AllowInsecureAuth, QueueSize}; !reflect.DeepEqual(keys, expected) {
+		t.Fatalf("expected warnings of %v, got %v", expected, warnings)
+	}
+	if s, expected := warnings[0].String(), "audit_webhook:insecure allow_insecure_auth: the auth_token is sent in plaintext to an http endpoint"; s != expected {
+		t.Fatalf("expected %q, got %q", expected, s)
+	}
+
+	// Warnings never fail the validation, errors still do.
+	secure.Set(Ordering, "fifo")
+	if _, err = ValidateSubSysConfig(scfg, config.AuditWebhookSubSys, true); err == nil {
+		t.Fatal("expected an invalid config to fail the validation")
+	}
+}
+
+func TestAMQPConfigWarnings(t *testing.T) {
+	kvs := DefaultAuditAMQPKVS.Clone()
+	kvs.Set(config.Enable, config.EnableOn)
+	kvs.Set(AMQPURL, "amqps://localhost:5671")
+	kvs.Set(AMQPTLSSkipVerify, config.EnableOn)
+	scfg := config.Config{
+		config.AuditAMQPSubSys: map[string]config.KVS{config.Default: kvs},
+	}
+	warnings, err := ValidateSubSysConfig(scfg, config.AuditAMQPSubSys, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) != 1 || warnings[0].Key != AMQPTLSSkipVerify {
+		t.Fatalf("expected a tls_skip_verify warning, got %v", warnings)
+	}
+	if s, expected := warnings[0].String(), "audit_amqp tls_skip_verify: the certificate of the endpoint is not verified"; s != expected {
+		t.Fatalf("expected %q, got %q", expected, s)
+	}
+}